@@ -10,6 +10,7 @@ import { DeleteTask, UpdateTask } from "../action";
 import { hasPermission, ROLES } from "@/app/utils/checkPermission";
 import Filter from "./Filter";
 import Swal from "sweetalert2";
+import { getUserToken } from "@/app/utils/token";
 
 interface BoardProps {
   boardDetail: Task[];
@@ -42,25 +43,40 @@ export default function Board({
   }, [tasks]);
 
   useEffect(() => {
-    const socket = new WebSocket(process.env.NEXT_PUBLIC_WS_URL || "");
-
-    socket.onmessage = (event) => {
-      const message = JSON.parse(event.data);
-      if (message.type === "create") {
-        // Add the new task to the state
-        setTasks((prev) => [...prev, message.data]);
-      } else if (message.type === "update") {
-        // Find the task in the state and update it
-        setTasks((prev) =>
-          prev.map((task) =>
-            task.id === message.data.id ? message.data : task
-          )
-        );
-      } else if (message.type === "delete") {
-        setTasks((prev) => prev.filter((task) => task.id !== message.data));
-      }
+    let socket: WebSocket | undefined;
+    let closed = false;
+
+    // board events are only sent to signed-in subscribers of the board
+    getUserToken().then((token) => {
+      if (closed) return;
+      const url = new URL(process.env.NEXT_PUBLIC_WS_URL || "");
+      url.searchParams.set("token", token || "");
+      url.searchParams.set("board_id", taskBoardID);
+      socket = new WebSocket(url);
+
+      socket.onmessage = (event) => {
+        const message = JSON.parse(event.data);
+        if (message.type === "create") {
+          // Add the new task to the state
+          setTasks((prev) => [...prev, message.data]);
+        } else if (message.type === "update") {
+          // Find the task in the state and update it
+          setTasks((prev) =>
+            prev.map((task) =>
+              task.id === message.data.id ? message.data : task
+            )
+          );
+        } else if (message.type === "delete") {
+          setTasks((prev) => prev.filter((task) => task.id !== message.data));
+        }
+      };
+    });
+
+    return () => {
+      closed = true;
+      socket?.close();
     };
-  }, []);
+  }, [taskBoardID]);
 
   const moveTask = async (taskId: string, newStatus: Task["status"]) => {
     setTasks((prevTasks) =>
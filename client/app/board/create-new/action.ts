@@ -1,6 +1,6 @@
 "use server";
 
-import { decodeUserToken, getUserToken } from "@/app/utils/token";
+import { getUserToken } from "@/app/utils/token";
 
 interface AddNewBoard {
   title: string;
@@ -13,7 +13,6 @@ export const AddNewBoardCollaboration = async ({
 }: AddNewBoard) => {
   try {
     const token = await getUserToken();
-    const userInfo = (await decodeUserToken()) as { id: string };
     const response = await fetch(`${process.env.API_URL}/task-boards`, {
       method: "POST",
       headers: {
@@ -23,8 +22,6 @@ export const AddNewBoardCollaboration = async ({
       },
       body: JSON.stringify({
         title,
-        user: userInfo.id,
-        role: "owner",
         description,
       }),
     });
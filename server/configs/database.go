@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"server/models"
+	"server/migrations"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -65,23 +65,24 @@ func Connect() {
 	sqlDB.SetConnMaxLifetime(time.Hour)  
 }
 
-func AutoMigrate() {
-	fmt.Println("Running AutoMigrate...")
-
-	// Enable uuid-ossp extension for PostgreSQL
-	if err := DB.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";").Error; err != nil {
-		log.Fatalf("Error creating uuid-ossp extension: %v", err)
+// Migrate applies the pending schema migrations. Instances starting together
+// take turns on the migration lock, so only the first one migrates. With
+// MIGRATE_ON_START=false it does nothing, for deployments that run
+// "server migrate up" as a release step instead.
+func Migrate() {
+	if os.Getenv("MIGRATE_ON_START") == "false" {
+		return
 	}
 
-	// Migrate all models at once
-	if err := DB.AutoMigrate(
-		&models.User{},
-		&models.TaskBoard{},
-		&models.UserTaskBoard{},
-		&models.Task{},
-	); err != nil {
-		log.Fatalf("Error migrating models: %v", err)
+	migrator, err := migrations.NewMigrator(DB)
+	if err != nil {
+		log.Fatalf("Error loading migrations: %v", err)
 	}
-
-	fmt.Println("AutoMigrate completed successfully")
-}
\ No newline at end of file
+	applied, err := migrator.Up(0)
+	for _, migration := range applied {
+		fmt.Printf("Applied migration %d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		log.Fatalf("Error migrating database: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"net/http"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ActivityController struct {
+	activityService services.ActivityService
+	logger          *zap.Logger
+}
+
+func NewActivityController(activityService services.ActivityService, logger *zap.Logger) *ActivityController {
+	return &ActivityController{
+		activityService: activityService,
+		logger:          logger,
+	}
+}
+
+func (c *ActivityController) GetTaskActivity(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TaskActivityPages)
+	if !ok {
+		return
+	}
+
+	activities, page, err := c.activityService.GetTaskActivity(taskID, params)
+	if err != nil {
+		c.logger.Error("Failed to fetch task activity", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch task activity",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Task activity retrieved successfully", activities, params, page)
+}
+
+func (c *ActivityController) GetTaskBoardActivity(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TaskActivityPages)
+	if !ok {
+		return
+	}
+
+	activities, page, err := c.activityService.GetTaskBoardActivity(taskBoardID, params)
+	if err != nil {
+		c.logger.Error("Failed to fetch board activity", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch board activity",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Board activity retrieved successfully", activities, params, page)
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type AuditController struct {
+	auditService services.AuditService
+	logger       *zap.Logger
+}
+
+func NewAuditController(auditService services.AuditService, logger *zap.Logger) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+func (c *AuditController) GetTaskBoardAuditLog(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+	c.list(ctx, func(query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+		return c.auditService.GetTaskBoardAuditLog(taskBoardID, query, params)
+	})
+}
+
+func (c *AuditController) GetWorkspaceAuditLog(ctx *gin.Context) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid workspace ID",
+		})
+		return
+	}
+	c.list(ctx, func(query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+		return c.auditService.GetWorkspaceAuditLog(workspaceID, query, params)
+	})
+}
+
+func (c *AuditController) GetMyAuditLog(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+	c.list(ctx, func(query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+		return c.auditService.GetUserAuditLog(userID, query, params)
+	})
+}
+
+// list reads the filter and page of an audit log request and answers with
+// the entries find returns.
+func (c *AuditController) list(ctx *gin.Context, find func(query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error)) {
+	var query dto.AuditLogQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.AuditLogPages)
+	if !ok {
+		return
+	}
+
+	entries, page, err := find(&query, params)
+	if err != nil {
+		c.logger.Error("Failed to fetch audit log", zap.Error(err))
+		statusCode := taskErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to fetch audit log",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Audit log retrieved successfully", entries, params, page)
+}
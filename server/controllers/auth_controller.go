@@ -35,7 +35,7 @@ func (c *AuthController) Login(ctx *gin.Context) {
         return
     }
 
-    token, err := c.authService.Login(loginDTO.Email, loginDTO.Password)
+    token, err := c.authService.Login(loginDTO.Email, loginDTO.Password, helpers.RequestMeta(ctx))
     if err != nil {
         c.logger.Warn("Login failed", zap.Error(err))
         ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
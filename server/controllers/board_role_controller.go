@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type BoardRoleController struct {
+	boardRoleService services.BoardRoleService
+	logger           *zap.Logger
+}
+
+func NewBoardRoleController(boardRoleService services.BoardRoleService, logger *zap.Logger) *BoardRoleController {
+	return &BoardRoleController{
+		boardRoleService: boardRoleService,
+		logger:           logger,
+	}
+}
+
+func (c *BoardRoleController) GetRoles(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	roles, err := c.boardRoleService.GetRoles(taskBoardID)
+	if err != nil {
+		c.logger.Error("Failed to fetch roles", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch roles",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Roles retrieved successfully",
+		Data:    roles,
+	})
+}
+
+func (c *BoardRoleController) CreateRole(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	var roleDTO dto.BoardRoleRequest
+	if err := ctx.ShouldBindJSON(&roleDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	role, err := c.boardRoleService.CreateRole(taskBoardID, &roleDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to create role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Role created successfully",
+		Data:    role,
+	})
+}
+
+func (c *BoardRoleController) UpdateRole(ctx *gin.Context) {
+	taskBoardID, roleID, ok := parseRoleParams(ctx)
+	if !ok {
+		return
+	}
+
+	var roleDTO dto.BoardRoleRequest
+	if err := ctx.ShouldBindJSON(&roleDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	role, err := c.boardRoleService.UpdateRole(taskBoardID, roleID, &roleDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to update role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Role updated successfully",
+		Data:    role,
+	})
+}
+
+func (c *BoardRoleController) DeleteRole(ctx *gin.Context) {
+	taskBoardID, roleID, ok := parseRoleParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.boardRoleService.DeleteRole(taskBoardID, roleID); err != nil {
+		c.respondError(ctx, "Failed to delete role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Role deleted successfully",
+	})
+}
+
+func parseRoleParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	roleID, err := uuid.Parse(ctx.Param("role_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid role ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return taskBoardID, roleID, true
+}
+
+func (c *BoardRoleController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := http.StatusInternalServerError
+	switch err.(type) {
+	case *services.ValidationError:
+		statusCode = http.StatusBadRequest
+	case *services.ConflictError:
+		statusCode = http.StatusConflict
+	default:
+		switch err.Error() {
+		case "task board not found", "role not found":
+			statusCode = http.StatusNotFound
+		}
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type BoardTemplateController struct {
+	boardTemplateService services.BoardTemplateService
+	logger               *zap.Logger
+}
+
+func NewBoardTemplateController(boardTemplateService services.BoardTemplateService, logger *zap.Logger) *BoardTemplateController {
+	return &BoardTemplateController{
+		boardTemplateService: boardTemplateService,
+		logger:               logger,
+	}
+}
+
+func (c *BoardTemplateController) CloneTaskBoard(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var cloneDTO dto.CloneTaskBoardRequest
+	if err := ctx.ShouldBindJSON(&cloneDTO); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	taskBoard, err := c.boardTemplateService.CloneTaskBoard(taskBoardID, &cloneDTO, actorID)
+	if err != nil {
+		c.logger.Error("Failed to clone task board", zap.Error(err))
+		statusCode := taskErrorStatus(err)
+		switch err.Error() {
+		case "task board not found", "workspace not found":
+			statusCode = http.StatusNotFound
+		}
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to clone task board",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Task board cloned successfully",
+		Data:    taskBoard,
+	})
+}
+
+func (c *BoardTemplateController) GetTemplates(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TaskBoardPages)
+	if !ok {
+		return
+	}
+
+	templates, page, err := c.boardTemplateService.GetTemplates(userID, params)
+	if err != nil {
+		c.logger.Error("Failed to fetch templates", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch templates",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Templates retrieved successfully", templates, params, page)
+}
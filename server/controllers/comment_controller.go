@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CommentController struct {
+	commentService services.CommentService
+	logger         *zap.Logger
+}
+
+func NewCommentController(commentService services.CommentService, logger *zap.Logger) *CommentController {
+	return &CommentController{
+		commentService: commentService,
+		logger:         logger,
+	}
+}
+
+func (c *CommentController) GetComments(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.CommentPages)
+	if !ok {
+		return
+	}
+
+	comments, page, err := c.commentService.GetComments(taskID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch comments", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Comments retrieved successfully", comments, params, page)
+}
+
+func (c *CommentController) CreateComment(ctx *gin.Context) {
+	taskID, actorID, ok := parseTaskAndActor(ctx)
+	if !ok {
+		return
+	}
+
+	var commentDTO dto.CommentRequest
+	if err := ctx.ShouldBindJSON(&commentDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	comment, err := c.commentService.CreateComment(taskID, &commentDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to create comment", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Comment created successfully",
+		Data:    comment,
+	})
+}
+
+func (c *CommentController) DeleteComment(ctx *gin.Context) {
+	taskID, actorID, ok := parseTaskAndActor(ctx)
+	if !ok {
+		return
+	}
+
+	commentID, err := uuid.Parse(ctx.Param("comment_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid comment ID",
+		})
+		return
+	}
+
+	if err := c.commentService.DeleteComment(taskID, commentID, actorID); err != nil {
+		c.respondError(ctx, "Failed to delete comment", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Comment deleted successfully",
+	})
+}
+
+func (c *CommentController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := http.StatusInternalServerError
+	switch err.(type) {
+	case *services.ValidationError:
+		statusCode = http.StatusBadRequest
+	case *services.ForbiddenError:
+		statusCode = http.StatusForbidden
+	case *services.ConflictError:
+		statusCode = http.StatusConflict
+	default:
+		switch err.Error() {
+		case "task not found", "comment not found":
+			statusCode = http.StatusNotFound
+		}
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CustomFieldController struct {
+	customFieldService services.CustomFieldService
+	logger             *zap.Logger
+}
+
+func NewCustomFieldController(customFieldService services.CustomFieldService, logger *zap.Logger) *CustomFieldController {
+	return &CustomFieldController{
+		customFieldService: customFieldService,
+		logger:             logger,
+	}
+}
+
+func (c *CustomFieldController) CreateCustomField(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	var fieldDTO dto.CustomFieldRequest
+	if err := ctx.ShouldBindJSON(&fieldDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	field, err := c.customFieldService.CreateCustomField(taskBoardID, &fieldDTO)
+	if err != nil {
+		c.logger.Error("Failed to create custom field", zap.Error(err))
+		statusCode := customFieldErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to create custom field",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Custom field created successfully",
+		Data:    field,
+	})
+}
+
+func (c *CustomFieldController) GetCustomFields(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.CustomFieldPages)
+	if !ok {
+		return
+	}
+
+	fields, page, err := c.customFieldService.GetCustomFields(taskBoardID, params)
+	if err != nil {
+		c.logger.Error("Failed to fetch custom fields", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch custom fields",
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Custom fields retrieved successfully", fields, params, page)
+}
+
+func (c *CustomFieldController) UpdateCustomField(ctx *gin.Context) {
+	taskBoardID, fieldID, ok := parseCustomFieldParams(ctx)
+	if !ok {
+		return
+	}
+
+	var fieldDTO dto.CustomFieldRequest
+	if err := ctx.ShouldBindJSON(&fieldDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	field, err := c.customFieldService.UpdateCustomField(taskBoardID, fieldID, &fieldDTO)
+	if err != nil {
+		c.logger.Error("Failed to update custom field", zap.Error(err))
+		statusCode := customFieldErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to update custom field",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Custom field updated successfully",
+		Data:    field,
+	})
+}
+
+func (c *CustomFieldController) DeleteCustomField(ctx *gin.Context) {
+	taskBoardID, fieldID, ok := parseCustomFieldParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.customFieldService.DeleteCustomField(taskBoardID, fieldID); err != nil {
+		c.logger.Error("Failed to delete custom field", zap.Error(err))
+		statusCode := customFieldErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to delete custom field",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Custom field deleted successfully",
+	})
+}
+
+func parseCustomFieldParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	fieldID, err := uuid.Parse(ctx.Param("field_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid custom field ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return taskBoardID, fieldID, true
+}
+
+func customFieldErrorStatus(err error) int {
+	switch err.(type) {
+	case *services.ValidationError:
+		return http.StatusBadRequest
+	case *services.ConflictError:
+		return http.StatusConflict
+	}
+	if err.Error() == "task board not found" || err.Error() == "custom field not found" {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type InvitationController struct {
+	invitationService services.InvitationService
+	logger            *zap.Logger
+}
+
+func NewInvitationController(invitationService services.InvitationService, logger *zap.Logger) *InvitationController {
+	return &InvitationController{
+		invitationService: invitationService,
+		logger:            logger,
+	}
+}
+
+func (c *InvitationController) Invite(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var invitationDTO dto.InvitationRequest
+	if err := ctx.ShouldBindJSON(&invitationDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	invitation, err := c.invitationService.Invite(taskBoardID, &invitationDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to send invitation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Invitation sent successfully",
+		Data:    invitation,
+	})
+}
+
+func (c *InvitationController) GetInvitations(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.InvitationPages)
+	if !ok {
+		return
+	}
+
+	invitations, page, err := c.invitationService.GetInvitations(taskBoardID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch invitations", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Invitations retrieved successfully", invitations, params, page)
+}
+
+func (c *InvitationController) RevokeInvitation(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+	invitationID, err := uuid.Parse(ctx.Param("invitation_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid invitation ID",
+		})
+		return
+	}
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	if err := c.invitationService.RevokeInvitation(taskBoardID, invitationID, actorID); err != nil {
+		c.respondError(ctx, "Failed to revoke invitation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Invitation revoked successfully",
+	})
+}
+
+func (c *InvitationController) GetInvitation(ctx *gin.Context) {
+	invitation, err := c.invitationService.GetInvitation(ctx.Param("token"))
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch invitation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Invitation retrieved successfully",
+		Data:    invitation,
+	})
+}
+
+func (c *InvitationController) AcceptInvitation(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	userTaskBoard, err := c.invitationService.AcceptInvitation(ctx.Param("token"), userID)
+	if err != nil {
+		c.respondError(ctx, "Failed to accept invitation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Invitation accepted successfully",
+		Data:    userTaskBoard,
+	})
+}
+
+func (c *InvitationController) DeclineInvitation(ctx *gin.Context) {
+	if err := c.invitationService.DeclineInvitation(ctx.Param("token")); err != nil {
+		c.respondError(ctx, "Failed to decline invitation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Invitation declined successfully",
+	})
+}
+
+func (c *InvitationController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := taskErrorStatus(err)
+	switch err.Error() {
+	case "invitation not found", "task board not found", "role not found", "user not found":
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type LabelController struct {
+	labelService services.LabelService
+	logger       *zap.Logger
+}
+
+func NewLabelController(labelService services.LabelService, logger *zap.Logger) *LabelController {
+	return &LabelController{
+		labelService: labelService,
+		logger:       logger,
+	}
+}
+
+func (c *LabelController) CreateLabel(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	var labelDTO dto.LabelRequest
+	if err := ctx.ShouldBindJSON(&labelDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	label, err := c.labelService.CreateLabel(taskBoardID, &labelDTO)
+	if err != nil {
+		c.logger.Error("Failed to create label", zap.Error(err))
+		statusCode := labelErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to create label",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Label created successfully",
+		Data:    label,
+	})
+}
+
+func (c *LabelController) GetLabels(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.LabelPages)
+	if !ok {
+		return
+	}
+
+	labels, page, err := c.labelService.GetLabels(taskBoardID, params)
+	if err != nil {
+		c.logger.Error("Failed to fetch labels", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch labels",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Labels retrieved successfully", labels, params, page)
+}
+
+func (c *LabelController) UpdateLabel(ctx *gin.Context) {
+	taskBoardID, labelID, ok := parseLabelParams(ctx)
+	if !ok {
+		return
+	}
+
+	var labelDTO dto.LabelRequest
+	if err := ctx.ShouldBindJSON(&labelDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	label, err := c.labelService.UpdateLabel(taskBoardID, labelID, &labelDTO)
+	if err != nil {
+		c.logger.Error("Failed to update label", zap.Error(err))
+		statusCode := labelErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to update label",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Label updated successfully",
+		Data:    label,
+	})
+}
+
+func (c *LabelController) DeleteLabel(ctx *gin.Context) {
+	taskBoardID, labelID, ok := parseLabelParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.labelService.DeleteLabel(taskBoardID, labelID); err != nil {
+		c.logger.Error("Failed to delete label", zap.Error(err))
+		statusCode := labelErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to delete label",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Label deleted successfully",
+	})
+}
+
+func parseLabelParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	labelID, err := uuid.Parse(ctx.Param("label_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid label ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return taskBoardID, labelID, true
+}
+
+func labelErrorStatus(err error) int {
+	if _, ok := err.(*services.ConflictError); ok {
+		return http.StatusConflict
+	}
+	if err.Error() == "task board not found" || err.Error() == "label not found" {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
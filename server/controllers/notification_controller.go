@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"net/http"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type NotificationController struct {
+	notificationService services.NotificationService
+	logger              *zap.Logger
+}
+
+func NewNotificationController(notificationService services.NotificationService, logger *zap.Logger) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// GetNotifications lists the current user's inbox; unread=true leaves out
+// what they have read.
+func (c *NotificationController) GetNotifications(ctx *gin.Context) {
+	userID, ok := c.currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.NotificationPages)
+	if !ok {
+		return
+	}
+
+	notifications, page, err := c.notificationService.GetNotifications(userID, ctx.Query("unread") == "true", params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch notifications", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Notifications retrieved successfully", notifications, params, page)
+}
+
+func (c *NotificationController) GetUnreadCount(ctx *gin.Context) {
+	userID, ok := c.currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	unread, err := c.notificationService.CountUnread(userID)
+	if err != nil {
+		c.respondError(ctx, "Failed to count unread notifications", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Unread count retrieved successfully",
+		Data:    map[string]int64{"unread_count": unread},
+	})
+}
+
+func (c *NotificationController) MarkRead(ctx *gin.Context) {
+	userID, ok := c.currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	notificationID, err := uuid.Parse(ctx.Param("notification_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid notification ID",
+		})
+		return
+	}
+
+	unread, err := c.notificationService.MarkRead(userID, notificationID)
+	if err != nil {
+		c.respondError(ctx, "Failed to mark notification read", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Notification marked read",
+		Data:    map[string]int64{"unread_count": unread},
+	})
+}
+
+func (c *NotificationController) MarkAllRead(ctx *gin.Context) {
+	userID, ok := c.currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	marked, err := c.notificationService.MarkAllRead(userID)
+	if err != nil {
+		c.respondError(ctx, "Failed to mark notifications read", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Notifications marked read",
+		Data:    map[string]int64{"marked": marked, "unread_count": 0},
+	})
+}
+
+func (c *NotificationController) currentUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (c *NotificationController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := http.StatusInternalServerError
+	if err.Error() == "notification not found" {
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RecurrenceController struct {
+	recurrenceService services.RecurrenceService
+	logger            *zap.Logger
+}
+
+func NewRecurrenceController(recurrenceService services.RecurrenceService, logger *zap.Logger) *RecurrenceController {
+	return &RecurrenceController{
+		recurrenceService: recurrenceService,
+		logger:            logger,
+	}
+}
+
+func (c *RecurrenceController) SetRecurrence(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return
+	}
+
+	var recurrenceDTO dto.RecurrenceRequest
+	if err := ctx.ShouldBindJSON(&recurrenceDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	recurrence, err := c.recurrenceService.SetRecurrence(taskID, &recurrenceDTO)
+	if err != nil {
+		c.logger.Error("Failed to set recurrence", zap.Error(err))
+		statusCode := recurrenceErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to set recurrence",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Recurrence saved successfully",
+		Data:    recurrence,
+	})
+}
+
+func (c *RecurrenceController) GetRecurrence(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return
+	}
+
+	recurrence, err := c.recurrenceService.GetRecurrence(taskID)
+	if err != nil {
+		statusCode := recurrenceErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to get recurrence",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Recurrence retrieved successfully",
+		Data:    recurrence,
+	})
+}
+
+func (c *RecurrenceController) DeleteRecurrence(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return
+	}
+
+	if err := c.recurrenceService.DeleteRecurrence(taskID); err != nil {
+		c.logger.Error("Failed to delete recurrence", zap.Error(err))
+		statusCode := recurrenceErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to delete recurrence",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Recurrence deleted successfully",
+	})
+}
+
+func recurrenceErrorStatus(err error) int {
+	if _, ok := err.(*services.ValidationError); ok {
+		return http.StatusBadRequest
+	}
+	if err.Error() == "task not found" || err.Error() == "task does not recur" {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ReminderController struct {
+	reminderService services.ReminderService
+	logger          *zap.Logger
+}
+
+func NewReminderController(reminderService services.ReminderService, logger *zap.Logger) *ReminderController {
+	return &ReminderController{
+		reminderService: reminderService,
+		logger:          logger,
+	}
+}
+
+func (c *ReminderController) GetSetting(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	setting, err := c.reminderService.GetSetting(userID)
+	if err != nil {
+		c.logger.Error("Failed to fetch reminder settings", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch reminder settings",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Reminder settings retrieved successfully",
+		Data:    setting,
+	})
+}
+
+func (c *ReminderController) UpdateSetting(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var settingDTO dto.ReminderSettingRequest
+	if err := ctx.ShouldBindJSON(&settingDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	setting, err := c.reminderService.UpdateSetting(userID, &settingDTO)
+	if err != nil {
+		c.logger.Error("Failed to update reminder settings", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update reminder settings",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Reminder settings updated successfully",
+		Data:    setting,
+	})
+}
+
+func (c *ReminderController) ListReminders(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.ReminderPages)
+	if !ok {
+		return
+	}
+
+	reminders, page, err := c.reminderService.ListReminders(userID, params)
+	if err != nil {
+		c.logger.Error("Failed to fetch reminders", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch reminders",
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Reminders retrieved successfully", reminders, params, page)
+}
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type SavedViewController struct {
+	savedViewService services.SavedViewService
+	logger           *zap.Logger
+}
+
+func NewSavedViewController(savedViewService services.SavedViewService, logger *zap.Logger) *SavedViewController {
+	return &SavedViewController{
+		savedViewService: savedViewService,
+		logger:           logger,
+	}
+}
+
+func (c *SavedViewController) GetViews(ctx *gin.Context) {
+	taskBoardID, actorID, ok := parseSavedViewBoard(ctx)
+	if !ok {
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.SavedViewPages)
+	if !ok {
+		return
+	}
+
+	views, page, err := c.savedViewService.GetViews(taskBoardID, actorID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch saved views", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Saved views retrieved successfully", views, params, page)
+}
+
+func (c *SavedViewController) CreateView(ctx *gin.Context) {
+	taskBoardID, actorID, ok := parseSavedViewBoard(ctx)
+	if !ok {
+		return
+	}
+
+	var viewDTO dto.SavedViewRequest
+	if err := ctx.ShouldBindJSON(&viewDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	view, err := c.savedViewService.CreateView(taskBoardID, &viewDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to create saved view", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Saved view created successfully",
+		Data:    view,
+	})
+}
+
+func (c *SavedViewController) UpdateView(ctx *gin.Context) {
+	taskBoardID, actorID, ok := parseSavedViewBoard(ctx)
+	if !ok {
+		return
+	}
+	viewID, ok := parseSavedViewID(ctx)
+	if !ok {
+		return
+	}
+
+	var viewDTO dto.SavedViewRequest
+	if err := ctx.ShouldBindJSON(&viewDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	view, err := c.savedViewService.UpdateView(taskBoardID, viewID, &viewDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to update saved view", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Saved view updated successfully",
+		Data:    view,
+	})
+}
+
+func (c *SavedViewController) DeleteView(ctx *gin.Context) {
+	taskBoardID, actorID, ok := parseSavedViewBoard(ctx)
+	if !ok {
+		return
+	}
+	viewID, ok := parseSavedViewID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.savedViewService.DeleteView(taskBoardID, viewID, actorID); err != nil {
+		c.respondError(ctx, "Failed to delete saved view", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Saved view deleted successfully",
+	})
+}
+
+func parseSavedViewBoard(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return taskBoardID, actorID, true
+}
+
+func parseSavedViewID(ctx *gin.Context) (uuid.UUID, bool) {
+	viewID, err := uuid.Parse(ctx.Param("view_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid saved view ID",
+		})
+		return uuid.Nil, false
+	}
+	return viewID, true
+}
+
+func (c *SavedViewController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := taskErrorStatus(err)
+	if err.Error() == "saved view not found" {
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
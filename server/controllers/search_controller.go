@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SearchController struct {
+	searchService services.SearchService
+	logger        *zap.Logger
+}
+
+func NewSearchController(searchService services.SearchService, logger *zap.Logger) *SearchController {
+	return &SearchController{
+		searchService: searchService,
+		logger:        logger,
+	}
+}
+
+// SearchTasks takes the query in q, e.g.
+// q=status:todo priority:high assignee:me due:<7d "login bug",
+// and the usual limit, cursor, sort and fields of a list.
+func (c *SearchController) SearchTasks(ctx *gin.Context) {
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TaskSearchPages)
+	if !ok {
+		return
+	}
+
+	results, page, err := c.searchService.SearchTasks(ctx.Query("q"), params, actorID)
+	if err != nil {
+		c.logger.Error("Failed to search tasks", zap.Error(err))
+		statusCode := taskErrorStatus(err)
+		message := "Failed to search tasks"
+		if statusCode == http.StatusBadRequest {
+			message = "Invalid search query"
+		}
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: message,
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	helpers.RespondPage(ctx, "Tasks retrieved successfully", results, params, page)
+}
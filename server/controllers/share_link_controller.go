@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/models"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ShareLinkController struct {
+	shareLinkService services.ShareLinkService
+	logger           *zap.Logger
+}
+
+func NewShareLinkController(shareLinkService services.ShareLinkService, logger *zap.Logger) *ShareLinkController {
+	return &ShareLinkController{
+		shareLinkService: shareLinkService,
+		logger:           logger,
+	}
+}
+
+func (c *ShareLinkController) CreateShareLink(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var shareDTO dto.ShareLinkRequest
+	if err := ctx.ShouldBindJSON(&shareDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	link, err := c.shareLinkService.CreateShareLink(taskBoardID, &shareDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to create share link", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Share link created successfully",
+		Data:    link,
+	})
+}
+
+func (c *ShareLinkController) GetShareLinks(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.ShareLinkPages)
+	if !ok {
+		return
+	}
+
+	links, page, err := c.shareLinkService.GetShareLinks(taskBoardID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch share links", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Share links retrieved successfully", links, params, page)
+}
+
+func (c *ShareLinkController) RevokeShareLink(ctx *gin.Context) {
+	taskBoardID, linkID, ok := parseShareLinkParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.shareLinkService.RevokeShareLink(taskBoardID, linkID); err != nil {
+		c.respondError(ctx, "Failed to revoke share link", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Share link revoked successfully",
+	})
+}
+
+func (c *ShareLinkController) GetShareLinkAccesses(ctx *gin.Context) {
+	taskBoardID, linkID, ok := parseShareLinkParams(ctx)
+	if !ok {
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.ShareLinkAccessPages)
+	if !ok {
+		return
+	}
+
+	accesses, page, err := c.shareLinkService.GetShareLinkAccesses(taskBoardID, linkID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch share link accesses", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Share link accesses retrieved successfully", accesses, params, page)
+}
+
+// GetPublicBoard takes the same filters as GET /task-boards/:id, except
+// include_archived and include_deleted. A link's password goes in the
+// X-Share-Password header.
+func (c *ShareLinkController) GetPublicBoard(ctx *gin.Context) {
+	query := dto.TaskBoardQuery{
+		Status:       ctx.QueryArray("status"),
+		Priority:     ctx.QueryArray("priority"),
+		CustomFields: ctx.QueryMap("cf"),
+		Sort:         ctx.Query("sort"),
+		Limit:        ctx.Query("limit"),
+		Cursor:       ctx.Query("cursor"),
+	}
+
+	taskBoard, page, err := c.shareLinkService.GetPublicBoard(
+		ctx.Param("token"),
+		ctx.GetHeader("X-Share-Password"),
+		&query,
+		ctx.ClientIP(),
+		ctx.Request.UserAgent(),
+	)
+	if err != nil {
+		c.respondError(ctx, "Failed to open shared board", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "TaskBoard retrieved successfully",
+		Data:    taskBoard,
+		Page:    page,
+	})
+}
+
+func parseShareLinkParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	linkID, err := uuid.Parse(ctx.Param("link_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid share link ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return taskBoardID, linkID, true
+}
+
+func (c *ShareLinkController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := taskErrorStatus(err)
+	if refused, ok := err.(*services.ShareAccessRefusedError); ok {
+		switch refused.Outcome {
+		case models.ShareAccessRevoked, models.ShareAccessExpired:
+			statusCode = http.StatusGone
+		case models.ShareAccessLocked:
+			statusCode = http.StatusTooManyRequests
+		default:
+			statusCode = http.StatusUnauthorized
+		}
+	} else if err.Error() == "share link not found" {
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
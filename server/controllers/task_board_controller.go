@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"server/dto"
 	"server/helpers"
+	"server/repositories"
 	"server/services"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -38,18 +40,23 @@ func (controller *TaskBoardController) CreateTaskBoard(ctx *gin.Context) {
 		return
 	}
 
-	if _, err := uuid.Parse(taskBoardDTO.User); err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid UUID format for user",
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
 		})
 		return
 	}
 
-	taskBoard, err := controller.taskBoardService.CreateTaskBoard(&taskBoardDTO)
+	taskBoard, err := controller.taskBoardService.CreateTaskBoard(&taskBoardDTO, actorID, helpers.RequestMeta(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
-			Code:    http.StatusInternalServerError,
+		statusCode := taskErrorStatus(err)
+		if err.Error() == "workspace not found" {
+			statusCode = http.StatusNotFound
+		}
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
 			Message: "Failed to create TaskBoard",
 			Details: map[string]string{"error": err.Error()},
 		})
@@ -73,11 +80,46 @@ func (controller *TaskBoardController) GetTaskBoardByID(ctx *gin.Context) {
 		return
 	}
 
-	status := ctx.QueryArray("status")       
-	priorities := ctx.QueryArray("priority")
+	query := dto.TaskBoardQuery{
+		Status:       ctx.QueryArray("status"),
+		Priority:     ctx.QueryArray("priority"),
+		Labels:       ctx.QueryArray("label"),
+		Assignees:    ctx.QueryArray("assignee"),
+		CustomFields: ctx.QueryMap("cf"),
+		Sort:         ctx.Query("sort"),
+		Limit:        ctx.Query("limit"),
+		Cursor:       ctx.Query("cursor"),
+		IncludeArchived: ctx.Query("include_archived") == "true",
+		IncludeDeleted:  ctx.Query("include_deleted") == "true",
+		View:         ctx.Query("view"),
+	}
 
-	taskBoard, err := controller.taskBoardService.FindTaskBoardByIDExtendTasks(id, status, priorities)
+	actorID, err := helpers.CurrentUserID(ctx)
 	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	taskBoard, page, err := controller.taskBoardService.FindTaskBoardByIDExtendTasks(id, &query, actorID)
+	if err != nil {
+		if _, ok := err.(*services.ValidationError); ok {
+			ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid task filter",
+				Details: map[string]string{"error": err.Error()},
+			})
+			return
+		}
+		if err.Error() == "saved view not found" {
+			ctx.JSON(http.StatusNotFound, helpers.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Saved view not found",
+			})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, helpers.ErrorResponse{
 			Code:    http.StatusNotFound,
 			Message: "TaskBoard not found",
@@ -89,6 +131,7 @@ func (controller *TaskBoardController) GetTaskBoardByID(ctx *gin.Context) {
 		Code:    http.StatusOK,
 		Message: "TaskBoard retrieved successfully",
 		Data:    taskBoard,
+		Page:    page,
 	})
 }
 
@@ -104,7 +147,15 @@ func (controller *TaskBoardController) GetTaskBoardsByUserID(ctx *gin.Context) {
 		return
 	}
 
-	taskBoards, err := controller.taskBoardService.FindTaskBoardByUserID(userID)
+	params, ok := helpers.ParsePage(ctx, repositories.TaskBoardPages)
+	if !ok {
+		return
+	}
+
+	taskBoards, page, err := controller.taskBoardService.FindTaskBoardByUserID(userID, repositories.ListOptions{
+		IncludeArchived: ctx.Query("include_archived") == "true",
+		IncludeDeleted:  ctx.Query("include_deleted") == "true",
+	}, params)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
 			Code:    http.StatusInternalServerError,
@@ -114,11 +165,7 @@ func (controller *TaskBoardController) GetTaskBoardsByUserID(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "TaskBoards retrieved successfully",
-		Data:    taskBoards,
-	})
+	helpers.RespondPage(ctx, "TaskBoards retrieved successfully", taskBoards, params, page)
 }
 
 // Update TaskBoard
@@ -144,8 +191,25 @@ func (controller *TaskBoardController) UpdateTaskBoard(ctx *gin.Context) {
 		return
 	}
 
-	updatedTaskBoard, err := controller.taskBoardService.UpdateTaskBoard(id, &taskBoardDTO)
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	updatedTaskBoard, err := controller.taskBoardService.UpdateTaskBoard(id, &taskBoardDTO, actorID, helpers.RequestMeta(ctx))
 	if err != nil {
+		if _, ok := err.(*services.ConflictError); ok {
+			ctx.JSON(http.StatusConflict, helpers.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Failed to update TaskBoard",
+				Details: map[string]string{"error": err.Error()},
+			})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
 			Code:    http.StatusInternalServerError,
 			Message: "Failed to update TaskBoard",
@@ -171,7 +235,16 @@ func (controller *TaskBoardController) DeleteTaskBoard(ctx *gin.Context) {
 		return
 	}
 
-	if err := controller.taskBoardService.DeleteTaskBoard(id); err != nil {
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	if err := controller.taskBoardService.DeleteTaskBoard(id, actorID, helpers.RequestMeta(ctx)); err != nil {
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
 			Code:    http.StatusInternalServerError,
 			Message: "Failed to delete TaskBoard",
@@ -198,14 +271,44 @@ func (c *TaskBoardController) AddCollaborator(ctx *gin.Context) {
 		return
 	}
 
-	userTaskBoard, err := c.taskBoardService.AddCollaboratorOnTaskBoard(addCollaboratorDTO)
+	// The route authorizes the board in the path, so that one is used even
+	// when the body names another.
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+	addCollaboratorDTO.TaskBoardID = taskBoardID
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	userTaskBoard, err := c.taskBoardService.AddCollaboratorOnTaskBoard(addCollaboratorDTO, actorID, helpers.RequestMeta(ctx))
 	if err != nil {
 		c.logger.Error("Failed to add collaborator", zap.Error(err))
 		var statusCode int
 		var message string
-		if err.Error() == "user not found" {
+		if _, ok := err.(*services.ForbiddenError); ok {
+			statusCode = http.StatusForbidden
+			message = "Failed to add collaborator"
+		} else if _, ok := err.(*services.ValidationError); ok {
+			statusCode = http.StatusBadRequest
+			message = "Failed to add collaborator"
+		} else if err.Error() == "role not found" {
+			statusCode = http.StatusNotFound
+			message = "Role not found"
+		} else if err.Error() == "user not found" {
 			statusCode = http.StatusNotFound
-			message = "User not found"
+			message = "User not found; send them an invitation instead"
 		} else if err.Error() == "task board not found" {
 			statusCode = http.StatusNotFound
 			message = "Task board not found"
@@ -280,7 +383,16 @@ func (c *TaskBoardController) GetCollaboratorOnTaskBoard(ctx *gin.Context) {
 		return
 	}
 
-	collaborator, err := c.taskBoardService.GetCollaboratorOnTaskBoard(taskBoardID)
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	collaborator, err := c.taskBoardService.GetCollaboratorOnTaskBoard(taskBoardID, actorID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
 			Code:    http.StatusInternalServerError,
@@ -295,3 +407,382 @@ func (c *TaskBoardController) GetCollaboratorOnTaskBoard(ctx *gin.Context) {
 		Data:    collaborator,
 	})
 }
+
+func (c *TaskBoardController) SetWIPLimits(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	var limitsDTO dto.SetWIPLimitsRequest
+	if err := ctx.ShouldBindJSON(&limitsDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	columns, err := c.taskBoardService.SetWIPLimits(taskBoardID, &limitsDTO, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		c.logger.Error("Failed to set WIP limits", zap.Error(err))
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "task board not found" {
+			statusCode = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "duplicate limit") {
+			statusCode = http.StatusBadRequest
+		}
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to set WIP limits",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "WIP limits updated successfully",
+		Data:    columns,
+	})
+}
+
+func (controller *TaskBoardController) ArchiveTaskBoard(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid UUID format",
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	taskBoard, err := controller.taskBoardService.ArchiveTaskBoard(id, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		controller.respondTrashError(ctx, "Failed to archive TaskBoard", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "TaskBoard archived successfully",
+		Data:    taskBoard,
+	})
+}
+
+func (controller *TaskBoardController) RestoreTaskBoard(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid UUID format",
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	taskBoard, err := controller.taskBoardService.RestoreTaskBoard(id, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		controller.respondTrashError(ctx, "Failed to restore TaskBoard", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "TaskBoard restored successfully",
+		Data:    taskBoard,
+	})
+}
+
+func (controller *TaskBoardController) GetTrash(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TaskBoardTrashPages)
+	if !ok {
+		return
+	}
+
+	taskBoards, page, err := controller.taskBoardService.GetTrash(userID, params)
+	if err != nil {
+		controller.respondTrashError(ctx, "Failed to fetch trash", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Trash retrieved successfully", taskBoards, params, page)
+}
+
+func (controller *TaskBoardController) GetTaskTrash(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid UUID format",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TaskTrashPages)
+	if !ok {
+		return
+	}
+
+	tasks, page, err := controller.taskBoardService.GetTaskTrash(id, params)
+	if err != nil {
+		controller.respondTrashError(ctx, "Failed to fetch trash", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Trash retrieved successfully", tasks, params, page)
+}
+
+func (controller *TaskBoardController) respondTrashError(ctx *gin.Context, message string, err error) {
+	controller.logger.Error(message, zap.Error(err))
+
+	statusCode := http.StatusInternalServerError
+	if err.Error() == "task board not found" {
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
+
+func (c *TaskBoardController) UpdateCollaboratorRole(ctx *gin.Context) {
+	taskBoardID, userID, actorID, ok := parseCollaboratorParams(ctx)
+	if !ok {
+		return
+	}
+
+	var roleDTO dto.UpdateCollaboratorRequest
+	if err := ctx.ShouldBindJSON(&roleDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	collaborator, err := c.taskBoardService.UpdateCollaboratorRole(taskBoardID, userID, &roleDTO, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		c.respondCollaboratorError(ctx, "Failed to update collaborator", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Collaborator updated successfully",
+		Data:    collaborator,
+	})
+}
+
+func (c *TaskBoardController) RemoveCollaborator(ctx *gin.Context) {
+	taskBoardID, userID, actorID, ok := parseCollaboratorParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.taskBoardService.RemoveCollaborator(taskBoardID, userID, actorID, helpers.RequestMeta(ctx)); err != nil {
+		c.respondCollaboratorError(ctx, "Failed to remove collaborator", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Collaborator removed successfully",
+	})
+}
+
+func (c *TaskBoardController) TransferOwnership(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var transferDTO dto.TransferOwnershipRequest
+	if err := ctx.ShouldBindJSON(&transferDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	collaborators, err := c.taskBoardService.TransferOwnership(taskBoardID, &transferDTO, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		c.respondCollaboratorError(ctx, "Failed to transfer ownership", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Ownership transferred successfully",
+		Data:    collaborators,
+	})
+}
+
+func parseCollaboratorParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	return taskBoardID, userID, actorID, true
+}
+
+func (c *TaskBoardController) respondCollaboratorError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := http.StatusInternalServerError
+	switch err.(type) {
+	case *services.ValidationError:
+		statusCode = http.StatusBadRequest
+	case *services.ForbiddenError:
+		statusCode = http.StatusForbidden
+	case *services.ConflictError:
+		statusCode = http.StatusConflict
+	default:
+		if err.Error() == "collaborator not found" || err.Error() == "role not found" {
+			statusCode = http.StatusNotFound
+		}
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
+
+// GetPermissions tells the client what the current user may do on the board,
+// or on one of its tasks when task_id is passed.
+func (c *TaskBoardController) GetPermissions(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var taskID *uuid.UUID
+	if raw := ctx.Query("task_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid task ID",
+			})
+			return
+		}
+		taskID = &parsed
+	}
+
+	permissions, err := c.taskBoardService.GetPermissions(taskBoardID, actorID, taskID)
+	if err != nil {
+		c.logger.Error("Failed to get permissions", zap.Error(err))
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			statusCode = http.StatusNotFound
+		}
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to get permissions",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Permissions retrieved successfully",
+		Data:    permissions,
+	})
+}
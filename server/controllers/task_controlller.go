@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"server/dto"
 	"server/helpers"
+	"server/models"
 	"server/services"
 
 	"github.com/gin-gonic/gin"
@@ -37,11 +38,15 @@ func (c *TaskController) CreateTask(ctx *gin.Context) {
 		return
 	}
 
-	task, err := c.taskService.CreateTask(&taskDTO)
+	// Routes without AuthMiddleware have no actor; uuid.Nil can never override a WIP limit.
+	actorID, _ := helpers.CurrentUserID(ctx)
+
+	task, err := c.taskService.CreateTask(&taskDTO, actorID, helpers.RequestMeta(ctx))
 	if err != nil {
 		c.logger.Error("Failed to create task", zap.Error(err))
-		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
-			Code:    http.StatusInternalServerError,
+		statusCode := taskErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
 			Message: "Failed to create task",
 			Details: map[string]string{"error": err.Error()},
 		})
@@ -110,11 +115,14 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 		return
 	}
 
-	updatedTask, err := c.taskService.UpdateTask(taskID, &taskDTO)
+	actorID, _ := helpers.CurrentUserID(ctx)
+
+	updatedTask, err := c.taskService.UpdateTask(taskID, &taskDTO, actorID, helpers.RequestMeta(ctx))
 	if err != nil {
 		c.logger.Error("Failed to update task", zap.Error(err))
-		ctx.JSON(http.StatusInternalServerError, helpers.ErrorResponse{
-			Code:    http.StatusInternalServerError,
+		statusCode := taskErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
 			Message: "Failed to update task",
 			Details: map[string]string{"error": err.Error()},
 		})
@@ -142,7 +150,16 @@ func (c *TaskController) DeleteTask(ctx *gin.Context) {
 		return
 	}
 
-	err = c.taskService.DeleteTask(taskID)
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	err = c.taskService.DeleteTask(taskID, actorID, helpers.RequestMeta(ctx))
 	if err != nil {
 		c.logger.Error("Failed to delete task", zap.Error(err))
 		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
@@ -158,3 +175,177 @@ func (c *TaskController) DeleteTask(ctx *gin.Context) {
 		Message: "Task deleted successfully",
 	})
 }
+
+func (c *TaskController) MoveTask(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var moveDTO dto.MoveTaskRequest
+	if err := ctx.ShouldBindJSON(&moveDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	task, dropped, err := c.taskService.MoveTask(taskID, &moveDTO, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		c.logger.Error("Failed to move task", zap.Error(err))
+		statusCode := taskErrorStatus(err)
+		if err.Error() == "task not found" {
+			statusCode = http.StatusNotFound
+		}
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to move task",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	if dropped == nil {
+		dropped = []string{}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Task moved successfully",
+		Data:    gin.H{"task": task, "dropped_custom_fields": dropped},
+	})
+}
+
+func (c *TaskController) BulkUpdateTasks(ctx *gin.Context) {
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var bulkDTO dto.BulkTaskRequest
+	if err := ctx.ShouldBindJSON(&bulkDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	result, err := c.taskService.BulkUpdateTasks(&bulkDTO, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		c.logger.Error("Failed to run bulk task operation", zap.Error(err))
+		statusCode := taskErrorStatus(err)
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to run bulk task operation",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	// A rolled back atomic operation still reports every item, so callers
+	// can see which tasks failed.
+	if !result.Committed {
+		ctx.JSON(http.StatusUnprocessableEntity, helpers.SuccessResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "Bulk task operation rolled back",
+			Data:    result,
+		})
+		return
+	}
+
+	message := "Bulk task operation completed successfully"
+	if result.Failed > 0 {
+		message = "Bulk task operation completed with failures"
+	}
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: message,
+		Data:    result,
+	})
+}
+
+func (c *TaskController) ArchiveTask(ctx *gin.Context) {
+	c.changeTaskState(ctx, "archive", c.taskService.ArchiveTask)
+}
+
+func (c *TaskController) RestoreTask(ctx *gin.Context) {
+	c.changeTaskState(ctx, "restore", c.taskService.RestoreTask)
+}
+
+// changeTaskState runs an archive or restore for the task in the path.
+func (c *TaskController) changeTaskState(ctx *gin.Context, action string, change func(taskID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error)) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	task, err := change(taskID, actorID, helpers.RequestMeta(ctx))
+	if err != nil {
+		c.logger.Error("Failed to "+action+" task", zap.Error(err))
+		statusCode := taskErrorStatus(err)
+		if err.Error() == "task not found" {
+			statusCode = http.StatusNotFound
+		}
+		ctx.JSON(statusCode, helpers.ErrorResponse{
+			Code:    statusCode,
+			Message: "Failed to " + action + " task",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Task " + action + "d successfully",
+		Data:    task,
+	})
+}
+
+func taskErrorStatus(err error) int {
+	switch err.(type) {
+	case *services.ConflictError:
+		return http.StatusConflict
+	case *services.ForbiddenError:
+		return http.StatusForbidden
+	case *services.ValidationError:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
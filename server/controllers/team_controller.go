@@ -0,0 +1,370 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type TeamController struct {
+	teamService services.TeamService
+	logger      *zap.Logger
+}
+
+func NewTeamController(teamService services.TeamService, logger *zap.Logger) *TeamController {
+	return &TeamController{
+		teamService: teamService,
+		logger:      logger,
+	}
+}
+
+func (c *TeamController) GetTeams(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TeamPages)
+	if !ok {
+		return
+	}
+
+	teams, page, err := c.teamService.GetTeams(workspaceID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch teams", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Teams retrieved successfully", teams, params, page)
+}
+
+func (c *TeamController) CreateTeam(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	var teamDTO dto.TeamRequest
+	if err := ctx.ShouldBindJSON(&teamDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	team, err := c.teamService.CreateTeam(workspaceID, &teamDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to create team", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Team created successfully",
+		Data:    team,
+	})
+}
+
+func (c *TeamController) UpdateTeam(ctx *gin.Context) {
+	workspaceID, teamID, ok := parseTeamParams(ctx)
+	if !ok {
+		return
+	}
+
+	var teamDTO dto.TeamRequest
+	if err := ctx.ShouldBindJSON(&teamDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	team, err := c.teamService.UpdateTeam(workspaceID, teamID, &teamDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to update team", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Team updated successfully",
+		Data:    team,
+	})
+}
+
+func (c *TeamController) DeleteTeam(ctx *gin.Context) {
+	workspaceID, teamID, ok := parseTeamParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.teamService.DeleteTeam(workspaceID, teamID); err != nil {
+		c.respondError(ctx, "Failed to delete team", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Team deleted successfully",
+	})
+}
+
+func (c *TeamController) GetTeamMembers(ctx *gin.Context) {
+	workspaceID, teamID, ok := parseTeamParams(ctx)
+	if !ok {
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TeamMemberPages)
+	if !ok {
+		return
+	}
+
+	members, page, err := c.teamService.GetTeamMembers(workspaceID, teamID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch team members", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Team members retrieved successfully", members, params, page)
+}
+
+func (c *TeamController) AddTeamMember(ctx *gin.Context) {
+	workspaceID, teamID, ok := parseTeamParams(ctx)
+	if !ok {
+		return
+	}
+
+	var memberDTO dto.TeamMemberRequest
+	if err := ctx.ShouldBindJSON(&memberDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	member, err := c.teamService.AddTeamMember(workspaceID, teamID, &memberDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to add team member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Team member added successfully",
+		Data:    member,
+	})
+}
+
+func (c *TeamController) RemoveTeamMember(ctx *gin.Context) {
+	workspaceID, teamID, ok := parseTeamParams(ctx)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	if err := c.teamService.RemoveTeamMember(workspaceID, teamID, userID); err != nil {
+		c.respondError(ctx, "Failed to remove team member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Team member removed successfully",
+	})
+}
+
+func (c *TeamController) GetBoardTeams(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.BoardTeamPages)
+	if !ok {
+		return
+	}
+
+	grants, page, err := c.teamService.GetBoardTeams(taskBoardID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch teams", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Teams retrieved successfully", grants, params, page)
+}
+
+func (c *TeamController) GrantTeam(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var grantDTO dto.BoardTeamRequest
+	if err := ctx.ShouldBindJSON(&grantDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	grant, err := c.teamService.GrantTeam(taskBoardID, &grantDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to grant team access", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Team access granted successfully",
+		Data:    grant,
+	})
+}
+
+func (c *TeamController) UpdateTeamGrant(ctx *gin.Context) {
+	taskBoardID, teamID, actorID, ok := parseBoardTeamParams(ctx)
+	if !ok {
+		return
+	}
+
+	var roleDTO dto.UpdateCollaboratorRequest
+	if err := ctx.ShouldBindJSON(&roleDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	grant, err := c.teamService.UpdateTeamGrant(taskBoardID, teamID, &roleDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to update team role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Team role updated successfully",
+		Data:    grant,
+	})
+}
+
+func (c *TeamController) RevokeTeam(ctx *gin.Context) {
+	taskBoardID, teamID, actorID, ok := parseBoardTeamParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.teamService.RevokeTeam(taskBoardID, teamID, actorID); err != nil {
+		c.respondError(ctx, "Failed to revoke team access", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Team access revoked successfully",
+	})
+}
+
+func parseTeamParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	teamID, err := uuid.Parse(ctx.Param("team_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid team ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return workspaceID, teamID, true
+}
+
+func parseBoardTeamParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	teamID, err := uuid.Parse(ctx.Param("team_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid team ID",
+		})
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	return taskBoardID, teamID, actorID, true
+}
+
+func (c *TeamController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := taskErrorStatus(err)
+	switch err.Error() {
+	case "team not found", "task board not found", "role not found":
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
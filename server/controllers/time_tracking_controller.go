@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/models"
+	"server/repositories"
+	"server/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type TimeTrackingController struct {
+	timeTrackingService services.TimeTrackingService
+	logger              *zap.Logger
+}
+
+func NewTimeTrackingController(timeTrackingService services.TimeTrackingService, logger *zap.Logger) *TimeTrackingController {
+	return &TimeTrackingController{
+		timeTrackingService: timeTrackingService,
+		logger:              logger,
+	}
+}
+
+func (c *TimeTrackingController) LogTime(ctx *gin.Context) {
+	taskID, actorID, ok := parseTaskAndActor(ctx)
+	if !ok {
+		return
+	}
+
+	var logDTO dto.LogTimeRequest
+	if err := ctx.ShouldBindJSON(&logDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	entry, err := c.timeTrackingService.LogTime(taskID, actorID, &logDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to log time", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Time logged successfully",
+		Data:    entry,
+	})
+}
+
+func (c *TimeTrackingController) GetTaskEntries(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.TimeEntryPages)
+	if !ok {
+		return
+	}
+
+	entries, page, err := c.timeTrackingService.GetTaskEntries(taskID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch time entries", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Time entries retrieved successfully", entries, params, page)
+}
+
+func (c *TimeTrackingController) DeleteEntry(ctx *gin.Context) {
+	entryID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid time entry ID",
+		})
+		return
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	if err := c.timeTrackingService.DeleteEntry(entryID, actorID); err != nil {
+		c.respondError(ctx, "Failed to delete time entry", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Time entry deleted successfully",
+	})
+}
+
+func (c *TimeTrackingController) StartTimer(ctx *gin.Context) {
+	taskID, actorID, ok := parseTaskAndActor(ctx)
+	if !ok {
+		return
+	}
+
+	var timerDTO dto.StartTimerRequest
+	if err := ctx.ShouldBindJSON(&timerDTO); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	entry, err := c.timeTrackingService.StartTimer(taskID, actorID, &timerDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to start timer", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Timer started successfully",
+		Data:    entry,
+	})
+}
+
+func (c *TimeTrackingController) StopTimer(ctx *gin.Context) {
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	entry, err := c.timeTrackingService.StopTimer(actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to stop timer", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Timer stopped successfully",
+		Data:    entry,
+	})
+}
+
+func (c *TimeTrackingController) GetRunningTimer(ctx *gin.Context) {
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	entry, err := c.timeTrackingService.GetRunningTimer(actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to get timer", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Timer retrieved successfully",
+		Data:    entry,
+	})
+}
+
+func (c *TimeTrackingController) TaskReport(ctx *gin.Context) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return
+	}
+	c.report(ctx, repositories.TimeEntryFilter{TaskID: &taskID}, "task-"+taskID.String())
+}
+
+func (c *TimeTrackingController) TaskBoardReport(ctx *gin.Context) {
+	taskBoardID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task board ID",
+		})
+		return
+	}
+	c.report(ctx, repositories.TimeEntryFilter{TaskBoardID: &taskBoardID}, "board-"+taskBoardID.String())
+}
+
+func (c *TimeTrackingController) UserReport(ctx *gin.Context) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+	c.report(ctx, repositories.TimeEntryFilter{UserID: &userID}, "user-"+userID.String())
+}
+
+// report answers with a JSON summary, or with the matching entries as CSV
+// when format=csv is requested.
+func (c *TimeTrackingController) report(ctx *gin.Context, filter repositories.TimeEntryFilter, name string) {
+	var query dto.TimeReportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	if query.Format == "csv" {
+		entries, err := c.timeTrackingService.ExportEntries(filter, &query)
+		if err != nil {
+			c.respondError(ctx, "Failed to export time entries", err)
+			return
+		}
+		writeTimeEntriesCSV(ctx, fmt.Sprintf("time-%s.csv", name), entries)
+		return
+	}
+
+	summary, err := c.timeTrackingService.Summarize(filter, &query)
+	if err != nil {
+		c.respondError(ctx, "Failed to summarize time", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Time report retrieved successfully",
+		Data:    summary,
+	})
+}
+
+func writeTimeEntriesCSV(ctx *gin.Context, filename string, entries []models.TimeEntry) {
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+
+	writer := csv.NewWriter(ctx.Writer)
+	writer.Write([]string{"date", "user", "email", "board", "task", "hours", "note"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.Date.Format("2006-01-02"),
+			entry.User.Name,
+			entry.User.Email,
+			entry.TaskBoard.Title,
+			entry.Task.Title,
+			strconv.FormatFloat(time.Duration(entry.DurationSeconds*int64(time.Second)).Hours(), 'f', 2, 64),
+			entry.Note,
+		})
+	}
+	writer.Flush()
+}
+
+func (c *TimeTrackingController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := http.StatusInternalServerError
+	switch err.(type) {
+	case *services.ValidationError:
+		statusCode = http.StatusBadRequest
+	case *services.ForbiddenError:
+		statusCode = http.StatusForbidden
+	case *services.ConflictError:
+		statusCode = http.StatusConflict
+	default:
+		switch err.Error() {
+		case "task not found", "time entry not found", "no timer is running":
+			statusCode = http.StatusNotFound
+		}
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
+
+func parseTaskAndActor(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	taskID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid task ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	actorID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return taskID, actorID, true
+}
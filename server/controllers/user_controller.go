@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"server/dto"
 	"server/helpers"
+	"server/repositories"
 	"server/services"
 
 	"github.com/gin-gonic/gin"
@@ -35,13 +36,15 @@ func (controller *UserController) CreateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := controller.userService.CreateUser(&userDTO)
+	user, err := controller.userService.CreateUser(&userDTO, helpers.RequestMeta(c))
 	if err != nil {
 		controller.logger.Error("Error creating user", zap.Error(err))
 		
 		statusCode := http.StatusBadRequest
 		if _, ok := err.(*services.ConflictError); ok {
 			statusCode = http.StatusConflict
+		} else if _, ok := err.(*services.ForbiddenError); ok {
+			statusCode = http.StatusForbidden
 		}
 		
 		c.JSON(statusCode, helpers.ErrorResponse{
@@ -59,7 +62,12 @@ func (controller *UserController) CreateUser(c *gin.Context) {
 }
 
 func (controller *UserController) GetAllUsers(c *gin.Context) {
-	users, err := controller.userService.FindAllUsers()
+	params, ok := helpers.ParsePage(c, repositories.UserPages)
+	if !ok {
+		return
+	}
+
+	users, page, err := controller.userService.FindAllUsers(params)
 	if err != nil {
 		controller.logger.Error("Error fetching users", zap.Error(err))
 		
@@ -76,13 +84,10 @@ func (controller *UserController) GetAllUsers(c *gin.Context) {
 			Code: 404,
 			Message: "No users found",
 			Data:    []interface{}{},
+			Page:    page,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, helpers.SuccessResponse{
-		Code: 200,
-		Message: "Users fetched successfully",
-		Data:    users,
-	})
+	helpers.RespondPage(c, "Users fetched successfully", users, params, page)
 }
\ No newline at end of file
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"net/http"
+	"server/helpers"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type WatcherController struct {
+	watcherService services.WatcherService
+	logger         *zap.Logger
+}
+
+func NewWatcherController(watcherService services.WatcherService, logger *zap.Logger) *WatcherController {
+	return &WatcherController{
+		watcherService: watcherService,
+		logger:         logger,
+	}
+}
+
+func (c *WatcherController) GetTaskWatchers(ctx *gin.Context) {
+	taskID, ok := c.parseID(ctx, "Invalid task ID")
+	if !ok {
+		return
+	}
+
+	watchers, err := c.watcherService.GetTaskWatchers(taskID)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch watchers", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Watchers retrieved successfully",
+		Data:    watchers,
+	})
+}
+
+func (c *WatcherController) WatchTask(ctx *gin.Context) {
+	c.setWatching(ctx, "Invalid task ID", "Failed to watch task", "Watching task", true, c.watcherService.WatchTask)
+}
+
+func (c *WatcherController) UnwatchTask(ctx *gin.Context) {
+	c.setWatching(ctx, "Invalid task ID", "Failed to unwatch task", "Stopped watching task", false, c.watcherService.UnwatchTask)
+}
+
+func (c *WatcherController) GetTaskBoardWatch(ctx *gin.Context) {
+	taskBoardID, ok := c.parseID(ctx, "Invalid task board ID")
+	if !ok {
+		return
+	}
+	userID, ok := c.currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	watching, err := c.watcherService.IsWatchingTaskBoard(taskBoardID, userID)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch board watch", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Board watch retrieved successfully",
+		Data:    map[string]bool{"watching": watching},
+	})
+}
+
+func (c *WatcherController) WatchTaskBoard(ctx *gin.Context) {
+	c.setWatching(ctx, "Invalid task board ID", "Failed to watch board", "Watching board", true, c.watcherService.WatchTaskBoard)
+}
+
+func (c *WatcherController) UnwatchTaskBoard(ctx *gin.Context) {
+	c.setWatching(ctx, "Invalid task board ID", "Failed to unwatch board", "Stopped watching board", false, c.watcherService.UnwatchTaskBoard)
+}
+
+// setWatching applies set to the task or board in the path for the current
+// user and reports whether they now watch it.
+func (c *WatcherController) setWatching(ctx *gin.Context, invalidID string, failure string, success string, watching bool, set func(id uuid.UUID, userID uuid.UUID) error) {
+	id, ok := c.parseID(ctx, invalidID)
+	if !ok {
+		return
+	}
+	userID, ok := c.currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := set(id, userID); err != nil {
+		c.respondError(ctx, failure, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: success,
+		Data:    map[string]bool{"watching": watching},
+	})
+}
+
+func (c *WatcherController) parseID(ctx *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (c *WatcherController) currentUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (c *WatcherController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := http.StatusInternalServerError
+	if err.Error() == "task not found" {
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
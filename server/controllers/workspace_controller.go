@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"net/http"
+	"server/dto"
+	"server/helpers"
+	"server/repositories"
+	"server/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type WorkspaceController struct {
+	workspaceService services.WorkspaceService
+	logger           *zap.Logger
+}
+
+func NewWorkspaceController(workspaceService services.WorkspaceService, logger *zap.Logger) *WorkspaceController {
+	return &WorkspaceController{
+		workspaceService: workspaceService,
+		logger:           logger,
+	}
+}
+
+func (c *WorkspaceController) CreateWorkspace(ctx *gin.Context) {
+	actorID, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var workspaceDTO dto.WorkspaceRequest
+	if err := ctx.ShouldBindJSON(&workspaceDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	workspace, err := c.workspaceService.CreateWorkspace(&workspaceDTO, actorID)
+	if err != nil {
+		c.respondError(ctx, "Failed to create workspace", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Workspace created successfully",
+		Data:    workspace,
+	})
+}
+
+func (c *WorkspaceController) GetWorkspaces(ctx *gin.Context) {
+	userID, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.WorkspacePages)
+	if !ok {
+		return
+	}
+
+	workspaces, page, err := c.workspaceService.GetWorkspaces(userID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch workspaces", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Workspaces retrieved successfully", workspaces, params, page)
+}
+
+func (c *WorkspaceController) GetWorkspace(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	workspace, err := c.workspaceService.GetWorkspace(workspaceID, userID)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch workspace", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Workspace retrieved successfully",
+		Data:    workspace,
+	})
+}
+
+func (c *WorkspaceController) UpdateWorkspace(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	var workspaceDTO dto.WorkspaceRequest
+	if err := ctx.ShouldBindJSON(&workspaceDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	workspace, err := c.workspaceService.UpdateWorkspace(workspaceID, &workspaceDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to update workspace", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Workspace updated successfully",
+		Data:    workspace,
+	})
+}
+
+func (c *WorkspaceController) DeleteWorkspace(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.workspaceService.DeleteWorkspace(workspaceID); err != nil {
+		c.respondError(ctx, "Failed to delete workspace", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Workspace deleted successfully",
+	})
+}
+
+func (c *WorkspaceController) GetMembers(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	params, ok := helpers.ParsePage(ctx, repositories.WorkspaceMemberPages)
+	if !ok {
+		return
+	}
+
+	members, page, err := c.workspaceService.GetMembers(workspaceID, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch members", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Members retrieved successfully", members, params, page)
+}
+
+func (c *WorkspaceController) AddMember(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+
+	var memberDTO dto.WorkspaceMemberRequest
+	if err := ctx.ShouldBindJSON(&memberDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	member, err := c.workspaceService.AddMember(workspaceID, &memberDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to add member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Member added successfully",
+		Data:    member,
+	})
+}
+
+func (c *WorkspaceController) UpdateMemberRole(ctx *gin.Context) {
+	workspaceID, userID, ok := parseWorkspaceMemberParams(ctx)
+	if !ok {
+		return
+	}
+
+	var roleDTO dto.UpdateWorkspaceMemberRequest
+	if err := ctx.ShouldBindJSON(&roleDTO); err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Details: helpers.FormatValidationError(err),
+		})
+		return
+	}
+
+	member, err := c.workspaceService.UpdateMemberRole(workspaceID, userID, &roleDTO)
+	if err != nil {
+		c.respondError(ctx, "Failed to update member role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Member role updated successfully",
+		Data:    member,
+	})
+}
+
+func (c *WorkspaceController) RemoveMember(ctx *gin.Context) {
+	workspaceID, userID, ok := parseWorkspaceMemberParams(ctx)
+	if !ok {
+		return
+	}
+	actorID, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.workspaceService.RemoveMember(workspaceID, userID, actorID); err != nil {
+		c.respondError(ctx, "Failed to remove member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Member removed successfully",
+	})
+}
+
+func (c *WorkspaceController) GetBoards(ctx *gin.Context) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	options := repositories.ListOptions{
+		IncludeArchived: ctx.Query("include_archived") == "true",
+		IncludeDeleted:  ctx.Query("include_deleted") == "true",
+	}
+	params, ok := helpers.ParsePage(ctx, repositories.TaskBoardPages)
+	if !ok {
+		return
+	}
+
+	taskBoards, page, err := c.workspaceService.GetBoards(workspaceID, userID, options, params)
+	if err != nil {
+		c.respondError(ctx, "Failed to fetch task boards", err)
+		return
+	}
+
+	helpers.RespondPage(ctx, "Task boards retrieved successfully", taskBoards, params, page)
+}
+
+func (c *WorkspaceController) currentUser(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := helpers.CurrentUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helpers.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func parseWorkspaceID(ctx *gin.Context) (uuid.UUID, bool) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid workspace ID",
+		})
+		return uuid.Nil, false
+	}
+	return workspaceID, true
+}
+
+func parseWorkspaceMemberParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	workspaceID, ok := parseWorkspaceID(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helpers.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid user ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return workspaceID, userID, true
+}
+
+func (c *WorkspaceController) respondError(ctx *gin.Context, message string, err error) {
+	c.logger.Error(message, zap.Error(err))
+
+	statusCode := taskErrorStatus(err)
+	switch err.Error() {
+	case "workspace not found", "member not found", "user not found":
+		statusCode = http.StatusNotFound
+	}
+
+	ctx.JSON(statusCode, helpers.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Details: map[string]string{"error": err.Error()},
+	})
+}
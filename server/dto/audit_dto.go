@@ -0,0 +1,14 @@
+package dto
+
+// AuditLogQuery filters an audit log listing. Action takes a comma-separated
+// list of actions; From and To bound the entry time as RFC 3339 timestamps,
+// From inclusive and To exclusive.
+type AuditLogQuery struct {
+	ActorID     string `form:"actor_id" binding:"omitempty,uuid"`
+	Action      string `form:"action" binding:"max=500"`
+	EntityType  string `form:"entity_type" binding:"omitempty,oneof=task task_board collaborator user"`
+	EntityID    string `form:"entity_id" binding:"omitempty,uuid"`
+	TaskBoardID string `form:"task_board_id" binding:"omitempty,uuid"`
+	From        string `form:"from" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To          string `form:"to" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CustomFieldRequest struct {
+	Name     string   `json:"name" binding:"required,max=255"`
+	Type     string   `json:"type" binding:"required,oneof=text number date single_select multi_select user url"`
+	Options  []string `json:"options" binding:"dive,required,max=255"`
+	Required bool     `json:"required"`
+}
+
+// TaskBoardQuery carries the task filters accepted by GET /task-boards/:id.
+// Labels are label IDs and Assignees user IDs, "me" or "none". CustomFields
+// maps a field ID to a comma-separated list of values, or to a "min..max"
+// range for number and date fields. Sort lists task columns or
+// "cf:<field id>", each prefixed with "-" for descending order; Limit and
+// Cursor page the tasks. Archived and deleted tasks are left out unless
+// IncludeArchived or IncludeDeleted is set. View names a saved view whose
+// settings fill in whatever the query leaves out.
+type TaskBoardQuery struct {
+	Status          []string
+	Priority        []string
+	Labels          []string
+	Assignees       []string
+	CustomFields    map[string]string
+	Sort            string
+	Limit           string
+	Cursor          string
+	IncludeArchived bool
+	IncludeDeleted  bool
+	View            string
+}
+
+// SavedViewRequest creates or replaces a saved view. Filter takes the same
+// values as the board query; Columns lists task columns or "cf:<field id>".
+type SavedViewRequest struct {
+	Name       string          `json:"name" binding:"required,max=100"`
+	Visibility string          `json:"visibility" binding:"omitempty,oneof=private shared"`
+	Filter     SavedViewFilter `json:"filter"`
+	Sort       string          `json:"sort" binding:"max=300"`
+	GroupBy    string          `json:"group_by" binding:"max=300"`
+	Columns    []string        `json:"columns" binding:"max=50,dive,required,max=300"`
+}
+
+type SavedViewFilter struct {
+	Status       []string          `json:"status" binding:"max=20"`
+	Priority     []string          `json:"priority" binding:"max=20"`
+	Labels       []uuid.UUID       `json:"labels" binding:"max=50"`
+	Assignees    []string          `json:"assignees" binding:"max=50"`
+	CustomFields map[string]string `json:"custom_fields" binding:"max=50"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// ReminderSettingRequest sets how many minutes before a task's end date
+// reminders go out (at most one week), and the channels used besides the
+// in-app reminder list.
+type ReminderSettingRequest struct {
+	OffsetsMinutes []int    `json:"offsets_minutes" binding:"required,min=1,max=5,dive,min=1,max=10080"`
+	Channels       []string `json:"channels" binding:"dive,oneof=websocket email"`
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +16,11 @@ type AssignTask struct {
     Priority    string    `json:"priority" binding:"required,oneof=low medium high"`
     StartDate   time.Time `json:"start_date" binding:"required"`
     EndDate     time.Time `json:"end_date" binding:"required"`
+    EstimateMinutes *int  `json:"estimate_minutes" binding:"omitempty,min=0"`
+    OverrideWIPLimit bool `json:"override_wip_limit"`
+    CustomFields map[string]json.RawMessage `json:"custom_fields"`
+    AssigneeID  *uuid.UUID  `json:"assignee_id"`
+    LabelIDs    []uuid.UUID `json:"label_ids"`
 }
 
 type TaskBoardFind struct {
@@ -34,23 +40,165 @@ type UpdateTaskRequest struct {
     Priority    string    `json:"priority" binding:"oneof=low medium high"`
     StartDate   time.Time `json:"start_date"`
     EndDate     time.Time `json:"end_date"`
+    EstimateMinutes *int  `json:"estimate_minutes" binding:"omitempty,min=0"`
+    // ClearEstimate removes the task's estimate; it cannot be combined with EstimateMinutes.
+    ClearEstimate bool    `json:"clear_estimate"`
+    OverrideWIPLimit bool `json:"override_wip_limit"`
+    // CustomFields replaces every custom field value on the task when present.
+    CustomFields map[string]json.RawMessage `json:"custom_fields"`
+    // AssigneeID reassigns the task when present.
+    AssigneeID  *uuid.UUID  `json:"assignee_id"`
+    // Unassign clears the task's assignee; it cannot be combined with AssigneeID.
+    Unassign    bool        `json:"unassign"`
+    // LabelIDs replaces the task's labels when present.
+    LabelIDs    []uuid.UUID `json:"label_ids"`
 }
 
+// TaskBoardRequest creates or updates a board. WorkspaceID picks the
+// workspace a new board goes into, the creator's personal one by default; it
+// is ignored on update.
 type TaskBoardRequest struct {
 	Title       string     `json:"title" binding:"required,max=255"`
 	Description string     `json:"description" binding:"max=255"`
-	User        string     `json:"user" binding:"required,uuid"`
-    Role        string     `gorm:"size:50;not null" json:"role" binding:"required,oneof=owner editor viewer"`
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
 }
 
+// AddCollaborator names a preset role, or "custom" together with the ID of
+// one of the board's custom roles.
 type AddCollaborator struct {
 	Email       string `json:"email" binding:"required"`
-	TaskBoardID uuid.UUID `json:"task_board_id" binding:"required"`
-	Role        string `json:"role" binding:"required,oneof=owner editor viewer"`
+	TaskBoardID uuid.UUID `json:"task_board_id"`
+	Role        string `json:"role" binding:"required,oneof=owner editor viewer custom"`
+	RoleID      *uuid.UUID `json:"role_id" binding:"required_if=Role custom"`
+}
+
+// InvitationRequest invites an email to the board, with the role named the
+// same way as for AddCollaborator. The email need not have an account yet.
+type InvitationRequest struct {
+	Email  string     `json:"email" binding:"required,email"`
+	Role   string     `json:"role" binding:"required,oneof=owner editor viewer custom"`
+	RoleID *uuid.UUID `json:"role_id" binding:"required_if=Role custom"`
+}
+
+// ShareLinkRequest creates a public read-only link to a board. Without
+// ExpiresAt it works until revoked; with Password, visitors must send it in
+// the X-Share-Password header.
+type ShareLinkRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+	Password  string     `json:"password" binding:"omitempty,min=6,max=72"`
+}
+
+// BoardTeamRequest grants a team of the board's workspace a role on the
+// board, named the same way as for AddCollaborator.
+type BoardTeamRequest struct {
+	TeamID uuid.UUID  `json:"team_id" binding:"required"`
+	Role   string     `json:"role" binding:"required,oneof=owner editor viewer custom"`
+	RoleID *uuid.UUID `json:"role_id" binding:"required_if=Role custom"`
+}
+
+type UpdateCollaboratorRequest struct {
+	Role   string     `json:"role" binding:"required,oneof=owner editor viewer custom"`
+	RoleID *uuid.UUID `json:"role_id" binding:"required_if=Role custom"`
+}
+
+// TransferOwnershipRequest hands the board to another collaborator. The
+// current owner keeps PreviousOwnerRole afterwards, editor by default.
+type TransferOwnershipRequest struct {
+	UserID            uuid.UUID `json:"user_id" binding:"required"`
+	PreviousOwnerRole string    `json:"previous_owner_role" binding:"omitempty,oneof=editor viewer"`
 }
 
 type SortByPriorityAndStatus struct {
     TaskID      uuid.UUID `json:"task_id" binding:"required"`
     Status      string    `json:"status" binding:"oneof=todo in_progress done"`
     Priority    string    `json:"priority" binding:"oneof=low medium high"`
-}
\ No newline at end of file
+}
+
+type WIPLimit struct {
+    Status string `json:"status" binding:"required,oneof=todo in_progress done"`
+    Limit  int    `json:"limit" binding:"required,min=1"`
+}
+
+// SetWIPLimitsRequest replaces a board's WIP limits; statuses left out become unlimited.
+type SetWIPLimitsRequest struct {
+    Limits []WIPLimit `json:"limits" binding:"dive"`
+}
+
+// RecurrenceRequest sets an RRULE subset such as "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10".
+type RecurrenceRequest struct {
+    Rule string `json:"rule" binding:"required,max=255"`
+}
+
+// MoveTaskRequest moves a task to another board. Status defaults to the
+// task's current status; CustomFields supplies or overrides values for the
+// target board's fields, keyed by field ID.
+type MoveTaskRequest struct {
+    TaskBoardID      uuid.UUID                  `json:"task_board_id" binding:"required"`
+    Status           string                     `json:"status" binding:"omitempty,oneof=todo in_progress done"`
+    OverrideWIPLimit bool                       `json:"override_wip_limit"`
+    CustomFields     map[string]json.RawMessage `json:"custom_fields"`
+}
+
+type LabelRequest struct {
+    Name  string `json:"name" binding:"required,max=100"`
+    Color string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+// BoardRoleRequest defines a custom role by picking permissions from
+// policy.Catalogue.
+type BoardRoleRequest struct {
+    Name        string   `json:"name" binding:"required,max=50"`
+    Permissions []string `json:"permissions" binding:"required,min=1,dive,required"`
+}
+
+type CommentRequest struct {
+    Body string `json:"body" binding:"required,max=5000"`
+}
+
+// BulkTaskRequest applies one operation to the tasks listed in TaskIDs or
+// matched by Filter. Operations and the fields they read:
+//   update: Status and/or Priority
+//   assign: AssigneeID, or null to unassign
+//   label:  AddLabelIDs and/or RemoveLabelIDs
+//   move:   TaskBoardID, optionally Status
+//   delete: nothing
+// Mode "atomic" (the default) rolls every change back when any task fails;
+// "best_effort" keeps the tasks that succeeded.
+type BulkTaskRequest struct {
+    TaskIDs          []uuid.UUID     `json:"task_ids"`
+    Filter           *BulkTaskFilter `json:"filter"`
+    Operation        string          `json:"operation" binding:"required,oneof=update assign label move delete"`
+    Mode             string          `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+    Status           string          `json:"status" binding:"omitempty,oneof=todo in_progress done"`
+    Priority         string          `json:"priority" binding:"omitempty,oneof=low medium high"`
+    AssigneeID       *uuid.UUID      `json:"assignee_id"`
+    AddLabelIDs      []uuid.UUID     `json:"add_label_ids"`
+    RemoveLabelIDs   []uuid.UUID     `json:"remove_label_ids"`
+    TaskBoardID      uuid.UUID       `json:"task_board_id"`
+    OverrideWIPLimit bool            `json:"override_wip_limit"`
+}
+
+// BulkTaskFilter selects a board's tasks with the filters accepted by
+// GET /task-boards/:id; CustomFields uses the same expressions as cf[<id>].
+type BulkTaskFilter struct {
+    TaskBoardID  uuid.UUID         `json:"task_board_id" binding:"required"`
+    Status       []string          `json:"status" binding:"dive,oneof=todo in_progress done"`
+    Priority     []string          `json:"priority" binding:"dive,oneof=low medium high"`
+    CustomFields map[string]string `json:"custom_fields"`
+}
+
+// CloneTaskBoardRequest copies a board or template into a new board owned by
+// the caller. WIP limits, labels and custom fields are always copied;
+// IncludeCollaborators also copies team grants when the copy stays in the
+// source's workspace. StartOn shifts copied task dates so the earliest task
+// starts on that day, keeping the gaps between tasks. AsTemplate saves the
+// copy as a template. WorkspaceID picks the workspace of the copy, the
+// caller's personal one by default.
+type CloneTaskBoardRequest struct {
+    Title                string `json:"title" binding:"max=255"`
+    IncludeTasks         bool   `json:"include_tasks"`
+    IncludeCollaborators bool   `json:"include_collaborators"`
+    StartOn              string `json:"start_on" binding:"omitempty,datetime=2006-01-02"`
+    AsTemplate           bool   `json:"as_template"`
+    WorkspaceID          *uuid.UUID `json:"workspace_id"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type LogTimeRequest struct {
+	DurationMinutes int    `json:"duration_minutes" binding:"required,min=1,max=1440"`
+	Date            string `json:"date" binding:"required,datetime=2006-01-02"`
+	Note            string `json:"note" binding:"max=1000"`
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+// TimeReportQuery scopes a time report to an inclusive date range and picks
+// the dimension it is broken down by. Format "csv" exports the entries instead.
+type TimeReportQuery struct {
+	From    string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To      string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	GroupBy string `form:"group_by" binding:"omitempty,oneof=user task board date"`
+	Format  string `form:"format" binding:"omitempty,oneof=json csv"`
+}
@@ -6,6 +6,9 @@ type CreateUserRequest struct {
     Name  string `json:"name" binding:"required"`
     Email string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	// InvitationToken, from an invitation link, joins the board the
+	// invitation is for once the account is created.
+	InvitationToken string `json:"invitation_token"`
 }
 
 type UserResponse struct {
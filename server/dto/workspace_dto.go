@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/google/uuid"
+
+// WorkspaceRequest creates or renames a workspace. Settings left out keep
+// their current value, or the default on creation: members view boards they
+// were not added to, and may create boards.
+type WorkspaceRequest struct {
+	Name                  string `json:"name" binding:"required,max=255"`
+	DefaultBoardRole      string `json:"default_board_role" binding:"omitempty,oneof=editor viewer none"`
+	RestrictBoardCreation *bool  `json:"restrict_board_creation"`
+}
+
+// WorkspaceMemberRequest adds an existing user to a workspace by email.
+type WorkspaceMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=admin member guest"`
+}
+
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member guest"`
+}
+
+type TeamRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// TeamMemberRequest adds a workspace member to one of its teams.
+type TeamMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
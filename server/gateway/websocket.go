@@ -3,13 +3,25 @@ package gateway
 import (
 	"log"
 	"net/http"
+	"server/utils"
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
+// client is one open connection. userID is set when the connection presented
+// a valid token; boards lists the board channels it subscribed to.
+type client struct {
+	userID string
+	boards map[string]bool
+}
+
+// BoardAccess reports whether the user may receive the task events of a board.
+type BoardAccess func(userID uuid.UUID, taskBoardID uuid.UUID) bool
+
 type WebSocketService struct {
-	clients   map[*websocket.Conn]bool
+	clients   map[*websocket.Conn]*client
 	mutex     sync.Mutex
 }
 
@@ -19,11 +31,44 @@ var upgrader = websocket.Upgrader{
 
 func NewWebSocketService() *WebSocketService {
 	return &WebSocketService{
-		clients: make(map[*websocket.Conn]bool),
+		clients: make(map[*websocket.Conn]*client),
 	}
 }
 
-func (ws *WebSocketService) HandleConnections(w http.ResponseWriter, r *http.Request) {
+// HandleConnections upgrades the request to a WebSocket. Connections that pass
+// a valid JWT in the "token" query parameter receive events addressed to that
+// user via SendToUser. Each "board_id" query parameter subscribes to that
+// board's channel; it needs a token, and canView must grant the user the board.
+func (ws *WebSocketService) HandleConnections(w http.ResponseWriter, r *http.Request, canView BoardAccess) {
+	c := &client{boards: make(map[string]bool)}
+	if token := r.URL.Query().Get("token"); token != "" {
+		claims, err := utils.ValidateJWTToken(token)
+		if err != nil {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+		c.userID = claims.ID
+	}
+
+	boardIDs := r.URL.Query()["board_id"]
+	if len(boardIDs) > 0 && c.userID == "" {
+		http.Error(w, "Authorization token required to subscribe to boards", http.StatusUnauthorized)
+		return
+	}
+	for _, boardID := range boardIDs {
+		taskBoardUUID, err := uuid.Parse(boardID)
+		if err != nil {
+			http.Error(w, "Invalid task board ID", http.StatusBadRequest)
+			return
+		}
+		userUUID, err := uuid.Parse(c.userID)
+		if err != nil || !canView(userUUID, taskBoardUUID) {
+			http.Error(w, "Insufficient permissions", http.StatusForbidden)
+			return
+		}
+		c.boards[taskBoardUUID.String()] = true
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println("WebSocket upgrade failed:", err)
@@ -32,7 +77,7 @@ func (ws *WebSocketService) HandleConnections(w http.ResponseWriter, r *http.Req
 	defer conn.Close()
 
 	ws.mutex.Lock()
-	ws.clients[conn] = true
+	ws.clients[conn] = c
 	ws.mutex.Unlock()
 
 	for {
@@ -46,7 +91,22 @@ func (ws *WebSocketService) HandleConnections(w http.ResponseWriter, r *http.Req
 	}
 }
 
-func (ws *WebSocketService) Broadcast(eventType string, data interface{}) {
+// BroadcastToBoard delivers an event on a board's channel, to the connections
+// subscribed to that board only.
+func (ws *WebSocketService) BroadcastToBoard(boardID string, eventType string, data interface{}) {
+	ws.send(eventType, data, func(c *client) bool {
+		return c.boards[boardID]
+	})
+}
+
+// SendToUser delivers an event only to the connections authenticated as userID.
+func (ws *WebSocketService) SendToUser(userID string, eventType string, data interface{}) {
+	ws.send(eventType, data, func(c *client) bool {
+		return c.userID != "" && c.userID == userID
+	})
+}
+
+func (ws *WebSocketService) send(eventType string, data interface{}, match func(*client) bool) {
 	message := map[string]interface{}{
 		"type": eventType,
 		"data": data,
@@ -55,12 +115,14 @@ func (ws *WebSocketService) Broadcast(eventType string, data interface{}) {
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
 
-	for client := range ws.clients {
-		err := client.WriteJSON(message)
-		if err != nil {
+	for conn, c := range ws.clients {
+		if !match(c) {
+			continue
+		}
+		if err := conn.WriteJSON(message); err != nil {
 			log.Println("Error broadcasting message:", err)
-			client.Close()
-			delete(ws.clients, client)
+			conn.Close()
+			delete(ws.clients, conn)
 		}
 	}
 }
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"server/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+func TestBoardChannels(t *testing.T) {
+	t.Setenv("JWT_SECRET", "websocket-test")
+	memberID, outsiderID := uuid.New(), uuid.New()
+	taskBoardID, otherBoardID := uuid.New(), uuid.New()
+	canView := func(userID uuid.UUID, boardID uuid.UUID) bool {
+		return userID == memberID && boardID == taskBoardID
+	}
+
+	ws := NewWebSocketService()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws.HandleConnections(w, r, canView)
+	}))
+	defer server.Close()
+
+	token := func(userID uuid.UUID) string {
+		token, err := utils.CreateToken(utils.User{ID: userID.String()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	dial := func(query url.Values) (*websocket.Conn, int) {
+		t.Helper()
+		conn, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?"+query.Encode(), nil)
+		if err != nil {
+			if response == nil {
+				t.Fatalf("dial: %v", err)
+			}
+			return nil, response.StatusCode
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn, http.StatusSwitchingProtocols
+	}
+
+	rejected := []struct {
+		name  string
+		query url.Values
+		want  int
+	}{
+		{"board without a token", url.Values{"board_id": {taskBoardID.String()}}, http.StatusUnauthorized},
+		{"invalid token", url.Values{"token": {"nope"}}, http.StatusUnauthorized},
+		{"invalid board ID", url.Values{"token": {token(memberID)}, "board_id": {"roadmap"}}, http.StatusBadRequest},
+		{"board the user cannot view", url.Values{"token": {token(outsiderID)}, "board_id": {taskBoardID.String()}}, http.StatusForbidden},
+		{"one of several boards denied", url.Values{"token": {token(memberID)}, "board_id": {taskBoardID.String(), otherBoardID.String()}}, http.StatusForbidden},
+	}
+	for _, test := range rejected {
+		t.Run(test.name, func(t *testing.T) {
+			if _, status := dial(test.query); status != test.want {
+				t.Errorf("status = %d, want %d", status, test.want)
+			}
+		})
+	}
+
+	subscribed, _ := dial(url.Values{"token": {token(memberID)}, "board_id": {taskBoardID.String()}})
+	unsubscribed, _ := dial(url.Values{"token": {token(memberID)}})
+	anonymous, _ := dial(url.Values{})
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		ws.mutex.Lock()
+		connected := len(ws.clients)
+		ws.mutex.Unlock()
+		if connected == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d connections registered, want 3", connected)
+		}
+	}
+
+	ws.BroadcastToBoard(otherBoardID.String(), "update", "other board")
+	ws.BroadcastToBoard(taskBoardID.String(), "update", "task")
+
+	var message struct {
+		Type string `json:"type"`
+		Data string `json:"data"`
+	}
+	subscribed.SetReadDeadline(time.Now().Add(time.Second))
+	if err := subscribed.ReadJSON(&message); err != nil {
+		t.Fatalf("subscribed connection: %v", err)
+	}
+	if message.Data != "task" {
+		t.Errorf("subscribed connection got %q first, want the event of its own board", message.Data)
+	}
+	for name, conn := range map[string]*websocket.Conn{"unsubscribed": unsubscribed, "anonymous": anonymous} {
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		if err := conn.ReadJSON(&message); err == nil {
+			t.Errorf("%s connection received %+v", name, message)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	"server/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CurrentUserID returns the ID of the user authenticated by AuthMiddleware.
+func CurrentUserID(ctx *gin.Context) (uuid.UUID, error) {
+	return uuid.Parse(ctx.GetString("userID"))
+}
+
+// RequestMeta describes the request for the audit log; the request ID is the
+// one set by the RequestID middleware.
+func RequestMeta(ctx *gin.Context) models.RequestMeta {
+	return models.RequestMeta{
+		IPAddress: ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		RequestID: ctx.GetString("requestID"),
+	}
+}
@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"net/http"
+	"server/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParsePage reads the limit, cursor, sort and fields query parameters of a
+// list request. It answers 400 itself and returns false when they are
+// invalid.
+func ParsePage(ctx *gin.Context, spec pagination.Spec) (*pagination.Params, bool) {
+	params, err := pagination.Parse(pagination.Query{
+		Limit:  ctx.Query("limit"),
+		Cursor: ctx.Query("cursor"),
+		Sort:   ctx.Query("sort"),
+		Fields: ctx.Query("fields"),
+	}, spec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid page request",
+			Details: map[string]string{"error": err.Error()},
+		})
+		return nil, false
+	}
+	return params, true
+}
+
+// RespondPage answers 200 with one page of rows, trimmed to the fields the
+// request asked for, and the page's metadata.
+func RespondPage[T any](ctx *gin.Context, message string, rows []T, params *pagination.Params, page *pagination.Page) {
+	var data interface{} = rows
+	if params.Fields != nil {
+		selected, err := pagination.Select(rows, params.Fields)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: message,
+				Details: map[string]string{"error": err.Error()},
+			})
+			return
+		}
+		data = selected
+	}
+
+	ctx.JSON(http.StatusOK, SuccessResponse{
+		Code:    http.StatusOK,
+		Message: message,
+		Data:    data,
+		Page:    page,
+	})
+}
@@ -1,5 +1,7 @@
 package helpers
 
+import "server/pagination"
+
 type ErrorResponse struct {
     Code    int    `json:"code"`
     Message string `json:"message"`
@@ -10,4 +12,6 @@ type SuccessResponse struct {
 	Code    int         `json:"code"`
     Message string      `json:"message"`
     Data    interface{} `json:"data,omitempty"`
+	// Page describes the page of a list response.
+	Page *pagination.Page `json:"page,omitempty"`
 }
@@ -1,20 +1,40 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	config "server/configs"
 	"server/gateway"
+	"server/middlewares"
+	"server/migrations"
+	"server/policy"
 	"server/routes"
+	"server/utils"
+	"server/workers"
 	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		connect := func() *gorm.DB {
+			config.Connect()
+			return config.DB
+		}
+		if err := migrations.Run(os.Args[2:], connect, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetLevel(logrus.InfoLevel)
@@ -26,22 +46,38 @@ func main() {
 	defer zapLogger.Sync()
 
 	config.Connect()
-	config.AutoMigrate()
+	config.Migrate()
 
 	r := gin.Default()
 	r.Use(gin.Recovery())
+	r.Use(middlewares.RequestID())
 	r.SetTrustedProxies(nil)
 
 	wsService := gateway.NewWebSocketService()
+	mailer := utils.NewMailerFromEnv()
+	engine, err := policy.NewEngineFromEnv()
+	if err != nil {
+		logger.Fatal("Failed to load authorization policy: ", err)
+	}
 
 	apiGroup := r.Group("/api")
 	{
-		routes.UserRoutes(apiGroup, config.DB, zapLogger)
+		routes.UserRoutes(apiGroup, config.DB, zapLogger, wsService, mailer)
 		routes.AuthRoutes(apiGroup, config.DB, zapLogger)
-		routes.TaskBoardRoutes(apiGroup, config.DB, zapLogger)
-		routes.TaskRoutes(apiGroup, config.DB, zapLogger, wsService)
+		routes.TaskBoardRoutes(apiGroup, config.DB, zapLogger, wsService, engine, mailer)
+		routes.TaskRoutes(apiGroup, config.DB, zapLogger, wsService, engine)
+		routes.TimeEntryRoutes(apiGroup, config.DB, zapLogger, wsService, engine)
+		routes.WorkspaceRoutes(apiGroup, config.DB, zapLogger)
+		routes.InvitationRoutes(apiGroup, config.DB, zapLogger, mailer)
+		routes.PublicRoutes(apiGroup, config.DB, zapLogger)
 	}
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	workers.StartRecurringTaskScheduler(workerCtx, config.DB, wsService, engine, zapLogger)
+	workers.StartReminderWorker(workerCtx, config.DB, wsService, mailer, zapLogger)
+	workers.StartTrashPurger(workerCtx, config.DB, zapLogger)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -64,6 +100,7 @@ func main() {
 	<-sigChan
 
 	logger.Info("Shutting down server...")
+	stopWorkers()
 
 	logger.Info("Server stopped.")
 }
\ No newline at end of file
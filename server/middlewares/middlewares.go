@@ -1,11 +1,12 @@
 package middlewares
 
 import (
-	"fmt"
 	"net/http"
+	"server/policy"
 	"server/services"
 	"server/utils"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,27 @@ import (
 
 type Middleware = gin.HandlerFunc
 
+// maxRequestIDLength bounds a request ID taken from the client.
+const maxRequestIDLength = 64
+
+// RequestID tags the request with the X-Request-ID header the client or a
+// proxy sent, or with a new ID, and echoes it in the response. It is stored
+// in the context under "requestID".
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength || strings.ContainsFunc(requestID, func(r rune) bool { return r < 0x21 || r > 0x7e }) {
+			requestID = uuid.NewString()
+		}
+		c.Set("requestID", requestID)
+		c.Header("X-Request-ID", requestID)
+		c.Next()
+	}
+}
+
+// RequestLogger logs every request once it is served. Routes with a :token
+// parameter, such as share links and invitations, are logged by their route
+// pattern so that the secret never reaches the logs.
 func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -24,12 +46,18 @@ func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
 		
 		duration := time.Since(start)
 		
+		path := c.Request.URL.Path
+		if c.Param("token") != "" {
+			path = c.FullPath()
+		}
+
 		logger.Info("request",
 			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
+			zap.String("path", path),
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("duration", duration),
 			zap.String("client_ip", c.ClientIP()),
+			zap.String("request_id", c.GetString("requestID")),
 		)
 	}
 }
@@ -56,12 +84,11 @@ func AuthMiddleware(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
-func HasPermission(permission string, taskBoardService services.TaskBoardService, logger *zap.Logger) gin.HandlerFunc {
+// HasPermission requires the policy to let the current user perform action on
+// the task board named by the :id route parameter.
+func HasPermission(action policy.Action, engine *policy.Engine, taskBoardService services.TaskBoardService, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		taskBoardID := c.Param("id")
-		userID := c.GetString("userID") 
-		fmt.Println("userID", userID)
-		taskBoardUUID, err := uuid.Parse(taskBoardID)
+		taskBoardUUID, err := uuid.Parse(c.Param("id"))
 		if err != nil {
 			logger.Error("Invalid task board ID", zap.Error(err))
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task board ID"})
@@ -69,23 +96,82 @@ func HasPermission(permission string, taskBoardService services.TaskBoardService
 			return
 		}
 
-		userUUID, err := uuid.Parse(userID)
+		if !authorize(c, action, policy.Resource{TaskBoardID: taskBoardUUID}, engine, taskBoardService, logger) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// HasTaskPermission requires the policy to let the current user perform
+// action on the task named by the :id route parameter. The task's board ID is
+// stored in the context under "taskBoardID".
+func HasTaskPermission(action policy.Action, engine *policy.Engine, taskBoardService services.TaskBoardService, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		taskUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			logger.Error("Invalid task ID", zap.Error(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+			c.Abort()
+			return
+		}
+
+		resource, err := taskBoardService.FindTaskResource(taskUUID)
 		if err != nil {
-			logger.Error("Invalid user ID", zap.Error(err))
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		if !authorize(c, action, *resource, engine, taskBoardService, logger) {
+			return
+		}
+
+		c.Set("taskBoardID", resource.TaskBoardID.String())
+		c.Next()
+	}
+}
+
+// HasWorkspaceRole requires the current user to hold minimum, or a more
+// privileged role, in the workspace named by the :id route parameter. The
+// user's role is stored as "workspaceRole" in the context.
+func HasWorkspaceRole(minimum string, workspaceService services.WorkspaceService, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workspaceUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			logger.Error("Invalid workspace ID", zap.Error(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
 			c.Abort()
 			return
 		}
 
-		userTaskBoard, err := taskBoardService.CheckUserRole(taskBoardUUID, userUUID)
+		userUUID, err := uuid.Parse(c.GetString("userID"))
 		if err != nil {
-			logger.Error("Error fetching user role", zap.Error(err))
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			logger.Error("Invalid user ID", zap.Error(err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
 			c.Abort()
 			return
 		}
 
-		if !hasRequiredPermission(userTaskBoard.Role, permission) {
+		member, err := workspaceService.FindMember(workspaceUUID, userUUID)
+		if err != nil || !policy.WorkspaceRoleAtLeast(member.Role, minimum) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Set("workspaceRole", member.Role)
+		c.Next()
+	}
+}
+
+// IsCurrentUser only lets users reach routes about themselves, as named by the
+// given route parameter.
+func IsCurrentUser(param string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param(param) != c.GetString("userID") {
+			logger.Warn("Access to another user's resources denied", zap.String("userID", c.GetString("userID")))
 			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
 			c.Abort()
 			return
@@ -95,49 +181,66 @@ func HasPermission(permission string, taskBoardService services.TaskBoardService
 	}
 }
 
-func hasRequiredPermission(userRole, requiredRole string) bool {
-	roleHierarchy := map[string]int{
-		"viewer": 1,
-		"editor": 2,
-		"owner":  3,
+// authorize aborts with 403 unless the policy grants the action to the
+// current user, given the access they hold on the resource's board directly,
+// through a team or through their workspace. Users without access get the
+// same answer as users whose role is too low.
+func authorize(c *gin.Context, action policy.Action, resource policy.Resource, engine *policy.Engine, taskBoardService services.TaskBoardService, logger *zap.Logger) bool {
+	userUUID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		logger.Error("Invalid user ID", zap.Error(err))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		c.Abort()
+		return false
+	}
+
+	subject := policy.Subject{UserID: userUUID}
+	if userTaskBoard, err := taskBoardService.CheckUserRole(resource.TaskBoardID, userUUID); err == nil {
+		subject = policy.NewSubject(userUUID, userTaskBoard.Role, userTaskBoard.CustomPermissions())
 	}
 
-	return roleHierarchy[userRole] >= roleHierarchy[requiredRole]
+	if !engine.Allows(subject, action, resource) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "action": action})
+		c.Abort()
+		return false
+	}
+
+	return true
 }
 
+// RateLimiter lets each client IP make limit requests per window. Requests
+// are served concurrently, so the counts are guarded by a mutex.
 func RateLimiter(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
 	limiter := make(map[string]struct {
 		count    int
 		lastSeen time.Time
 	})
-	
+
 	return func(c *gin.Context) {
 		ip := c.ClientIP()
-		
+
+		mu.Lock()
 		// Clean up old entries
 		for k, v := range limiter {
 			if time.Since(v.lastSeen) > window {
 				delete(limiter, k)
 			}
 		}
-		
-		if entry, exists := limiter[ip]; exists {
-			if entry.count >= limit {
-				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-					"error": "Too many requests",
-				})
-				return
-			}
-			entry.count++
-			entry.lastSeen = time.Now()
-			limiter[ip] = entry
-		} else {
-			limiter[ip] = struct {
-				count    int
-				lastSeen time.Time
-			}{count: 1, lastSeen: time.Now()}
+
+		entry, exists := limiter[ip]
+		if exists && entry.count >= limit {
+			mu.Unlock()
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": "Too many requests",
+			})
+			return
 		}
-		
+		entry.count++
+		entry.lastSeen = time.Now()
+		limiter[ip] = entry
+		mu.Unlock()
+
 		c.Next()
 	}
 }
\ No newline at end of file
@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"server/models"
+	"server/policy"
+	"server/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// memberTaskBoardService answers access checks from a fixed board membership
+// and task list.
+type memberTaskBoardService struct {
+	services.TaskBoardService
+	roles map[uuid.UUID]string
+	tasks map[uuid.UUID]policy.Resource
+}
+
+func (service *memberTaskBoardService) CheckUserRole(taskBoardID uuid.UUID, userID uuid.UUID) (*models.UserTaskBoard, error) {
+	role, ok := service.roles[userID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &models.UserTaskBoard{UserID: userID, TaskBoardID: taskBoardID, Role: role}, nil
+}
+
+func (service *memberTaskBoardService) FindTaskResource(taskID uuid.UUID) (*policy.Resource, error) {
+	resource, ok := service.tasks[taskID]
+	if !ok {
+		return nil, errors.New("task not found")
+	}
+	return &resource, nil
+}
+
+func TestPermissionMiddlewares(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ownerID, editorID, viewerID, outsiderID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	taskBoardID, taskID := uuid.New(), uuid.New()
+	service := &memberTaskBoardService{
+		roles: map[uuid.UUID]string{ownerID: policy.RoleOwner, editorID: policy.RoleEditor, viewerID: policy.RoleViewer},
+		tasks: map[uuid.UUID]policy.Resource{taskID: {TaskBoardID: taskBoardID, OwnerID: &ownerID}},
+	}
+	engine := policy.Default()
+	logger := zap.NewNop()
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-User"))
+	})
+	ok := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"taskBoardID": c.GetString("taskBoardID")})
+	}
+	router.GET("/task-boards/:id", HasPermission(policy.BoardView, engine, service, logger), ok)
+	router.PUT("/task-boards/:id", HasPermission(policy.BoardUpdate, engine, service, logger), ok)
+	router.GET("/tasks/:id", HasTaskPermission(policy.TaskView, engine, service, logger), ok)
+	router.PUT("/tasks/:id", HasTaskPermission(policy.TaskUpdate, engine, service, logger), ok)
+	router.DELETE("/tasks/:id", HasTaskPermission(policy.TaskDelete, engine, service, logger), ok)
+
+	board, task := "/task-boards/"+taskBoardID.String(), "/tasks/"+taskID.String()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		userID uuid.UUID
+		want   int
+	}{
+		{"outsider views board", "GET", board, outsiderID, http.StatusForbidden},
+		{"viewer views board", "GET", board, viewerID, http.StatusOK},
+		{"viewer updates board", "PUT", board, viewerID, http.StatusForbidden},
+		{"editor updates board", "PUT", board, editorID, http.StatusForbidden},
+		{"owner updates board", "PUT", board, ownerID, http.StatusOK},
+		{"invalid board ID", "GET", "/task-boards/roadmap", ownerID, http.StatusBadRequest},
+
+		{"outsider views task", "GET", task, outsiderID, http.StatusForbidden},
+		{"viewer views task", "GET", task, viewerID, http.StatusOK},
+		{"viewer updates task", "PUT", task, viewerID, http.StatusForbidden},
+		{"editor updates task", "PUT", task, editorID, http.StatusOK},
+		{"editor deletes the owner's task", "DELETE", task, editorID, http.StatusForbidden},
+		{"owner deletes task", "DELETE", task, ownerID, http.StatusOK},
+		{"missing task", "GET", "/tasks/" + uuid.NewString(), outsiderID, http.StatusNotFound},
+		{"invalid task ID", "GET", "/tasks/first", ownerID, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(test.method, test.path, nil)
+			request.Header.Set("X-User", test.userID.String())
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != test.want {
+				t.Fatalf("status = %d %s, want %d", recorder.Code, recorder.Body, test.want)
+			}
+
+			var body map[string]string
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if test.want == http.StatusForbidden && body["action"] == "" {
+				t.Errorf("403 does not name the action: %s", recorder.Body)
+			}
+			if test.want == http.StatusOK && test.path == task && body["taskBoardID"] != taskBoardID.String() {
+				t.Errorf("taskBoardID = %q, want %s", body["taskBoardID"], taskBoardID)
+			}
+		})
+	}
+}
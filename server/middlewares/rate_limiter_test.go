@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiterUnderConcurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const limit, requests = 50, 200
+
+	router := gin.New()
+	router.GET("/", RateLimiter(limit, time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	var allowed, limited atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = "203.0.113.7:1234"
+			router.ServeHTTP(recorder, request)
+			switch recorder.Code {
+			case http.StatusNoContent:
+				allowed.Add(1)
+			case http.StatusTooManyRequests:
+				limited.Add(1)
+			default:
+				t.Errorf("status = %d", recorder.Code)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed.Load() != limit || limited.Load() != requests-limit {
+		t.Errorf("allowed %d and limited %d requests, want %d and %d", allowed.Load(), limited.Load(), limit, requests-limit)
+	}
+}
+
+func TestRateLimiterCountsEachClientSeparately(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", RateLimiter(1, time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		remoteAddr string
+		want       int
+	}{
+		{"203.0.113.7:1234", http.StatusNoContent},
+		{"203.0.113.7:5678", http.StatusTooManyRequests},
+		{"198.51.100.2:1234", http.StatusNoContent},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.RemoteAddr = test.remoteAddr
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != test.want {
+			t.Errorf("request from %s: status = %d, want %d", test.remoteAddr, recorder.Code, test.want)
+		}
+	}
+}
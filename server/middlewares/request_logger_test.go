@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestLoggerRedactsTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.InfoLevel)
+
+	router := gin.New()
+	router.Use(RequestLogger(zap.New(core)))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/public/boards/:token", ok)
+	router.POST("/invitations/:token/decline", ok)
+	router.GET("/task-boards/:id", ok)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/public/boards/s3cr3t-share-token", "/public/boards/:token"},
+		{"POST", "/invitations/s3cr3t-invite/decline", "/invitations/:token/decline"},
+		{"GET", "/task-boards/42", "/task-boards/42"},
+	}
+	for _, test := range tests {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.path, nil))
+		entries := logs.TakeAll()
+		if len(entries) != 1 {
+			t.Fatalf("%s: %d log entries, want 1", test.path, len(entries))
+		}
+		if got := entries[0].ContextMap()["path"]; got != test.want {
+			t.Errorf("logged path = %v, want %s", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package migrations
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const usage = `usage: server migrate <command>
+
+commands:
+  up [-steps n]        apply pending migrations, all of them by default
+  down [-steps n]      roll back the latest migrations, one by default
+  status               list migrations and when they were applied
+  create [-dir d] name write an empty SQL migration pair to d (default ` + Dir + `)`
+
+// Run carries out the migrate subcommand. connect opens the database and is
+// only called by the commands that need one.
+func Run(args []string, connect func() *gorm.DB, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	flags.SetOutput(out)
+	steps := flags.Int("steps", 0, "number of migrations")
+	dir := flags.String("dir", Dir, "directory for new migrations")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		if flags.NArg() != 1 {
+			return errors.New(usage)
+		}
+		return create(*dir, flags.Arg(0), out)
+	case "up", "down", "status":
+	default:
+		return errors.New(usage)
+	}
+
+	migrator, err := NewMigrator(connect())
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(*steps)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "nothing to apply")
+		}
+		return err
+	case "down":
+		if *steps < 1 {
+			*steps = 1
+		}
+		rolledBack, err := migrator.Down(*steps)
+		for _, migration := range rolledBack {
+			fmt.Fprintf(out, "rolled back %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(rolledBack) == 0 {
+			fmt.Fprintln(out, "nothing to roll back")
+		}
+		return err
+	default:
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			if status.Unknown {
+				state += " (not in this build)"
+			}
+			fmt.Fprintf(out, "%d_%s\t%s\n", status.Version, status.Name, state)
+		}
+		return nil
+	}
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// create writes the up and down files of a new SQL migration, versioned by
+// the current UTC time.
+func create(dir string, name string, out io.Writer) error {
+	name = strings.Trim(unsafeNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return errors.New("migration name must contain letters or digits")
+	}
+	base := time.Now().UTC().Format("20060102150405") + "_" + name
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, base+"."+direction+".sql")
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(file, "-- %s: %s\n", direction, name)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "created", path)
+	}
+	return nil
+}
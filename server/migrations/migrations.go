@@ -0,0 +1,118 @@
+// Package migrations versions the database schema. A migration is a pair of
+// up and down steps, written either as SQL files under sql/ or in Go with
+// Register, and identified by a version that orders it among the others.
+// Applied versions are recorded in schema_migrations, and a Postgres
+// advisory lock keeps concurrent instances from migrating at the same time.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// Dir is where create writes new SQL migrations, relative to the server
+// module.
+const Dir = "migrations/sql"
+
+// Migration is one versioned change to the schema. Up and Down each run in
+// a transaction; Down may be nil for a migration that cannot be rolled back.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+var registered []Migration
+
+// Register adds a Go migration. Call it from an init function in this
+// package, with a version newer than every migration already released.
+func Register(migration Migration) {
+	registered = append(registered, migration)
+}
+
+var sqlFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// All returns every migration, SQL and Go, oldest first.
+func All() ([]Migration, error) {
+	byVersion := make(map[int64]*Migration)
+	var migrations []*Migration
+	add := func(migration Migration) (*Migration, error) {
+		if existing, ok := byVersion[migration.Version]; ok {
+			if existing.Name != migration.Name {
+				return nil, fmt.Errorf("migrations %d_%s and %d_%s share a version", existing.Version, existing.Name, migration.Version, migration.Name)
+			}
+			return existing, nil
+		}
+		added := migration
+		byVersion[migration.Version] = &added
+		migrations = append(migrations, &added)
+		return &added, nil
+	}
+
+	entries, err := fs.ReadDir(sqlFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		match := sqlFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s is not named <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration file %s: %v", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(sqlFiles, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, err := add(Migration{Version: version, Name: match[2]})
+		if err != nil {
+			return nil, err
+		}
+		step := sqlStep(string(data))
+		if match[3] == "up" {
+			migration.Up = step
+		} else {
+			migration.Down = step
+		}
+	}
+
+	for _, migration := range registered {
+		if _, ok := byVersion[migration.Version]; ok {
+			return nil, fmt.Errorf("migration %d_%s shares its version with another migration", migration.Version, migration.Name)
+		}
+		if _, err := add(migration); err != nil {
+			return nil, err
+		}
+	}
+
+	all := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no up step", migration.Version, migration.Name)
+		}
+		all = append(all, *migration)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all, nil
+}
+
+// sqlStep runs a SQL file as a single multi-statement exec.
+func sqlStep(statements string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(statements).Error
+	}
+}
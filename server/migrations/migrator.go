@@ -0,0 +1,180 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockKey is the advisory lock held while migrating. Any constant works as
+// long as nothing else in the database uses it.
+const lockKey int64 = 0x61637569746d7368
+
+const createTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	applied_at timestamptz NOT NULL
+)`
+
+// Applied is a row of schema_migrations.
+type Applied struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (Applied) TableName() string {
+	return "schema_migrations"
+}
+
+// Status is a migration and whether it has been applied. Migrations applied
+// by a newer build are listed with Unknown set.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	Unknown   bool
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies up to steps pending migrations, oldest first, or all of them
+// when steps is 0. It returns the migrations it applied.
+func (migrator *Migrator) Up(steps int) ([]Migration, error) {
+	var done []Migration
+	err := migrator.locked(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrator.migrations {
+			if steps > 0 && len(done) == steps {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&Applied{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %v", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the steps most recently applied migrations, newest first.
+// It refuses to roll back a migration this build does not know or that has
+// no down step.
+func (migrator *Migrator) Down(steps int) ([]Migration, error) {
+	var done []Migration
+	err := migrator.locked(func(conn *gorm.DB) error {
+		var applied []Applied
+		if err := conn.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+			return err
+		}
+		byVersion := make(map[int64]Migration, len(migrator.migrations))
+		for _, migration := range migrator.migrations {
+			byVersion[migration.Version] = migration
+		}
+
+		for _, row := range applied {
+			migration, ok := byVersion[row.Version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s was applied by another build and cannot be rolled back by this one", row.Version, row.Name)
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&Applied{}, "version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %v", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every known migration and every applied one, oldest first.
+func (migrator *Migrator) Status() ([]Status, error) {
+	applied := make(map[int64]Applied)
+	if migrator.db.Migrator().HasTable(&Applied{}) {
+		var err error
+		if applied, err = appliedVersions(migrator.db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(migrator.migrations))
+	for _, migration := range migrator.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// locked runs fn on a single connection that holds the migration lock, so
+// that only one instance migrates at a time; the others wait their turn and
+// then find nothing left to do.
+func (migrator *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return migrator.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", lockKey)
+
+		if err := conn.Exec(createTableSQL).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func appliedVersions(db *gorm.DB) (map[int64]Applied, error) {
+	var rows []Applied
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]Applied, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,24 @@
+DROP TABLE IF EXISTS "time_entries";
+DROP TABLE IF EXISTS "task_reminders";
+DROP TABLE IF EXISTS "reminder_settings";
+DROP TABLE IF EXISTS "task_recurrences";
+DROP TABLE IF EXISTS "task_custom_field_values";
+DROP TABLE IF EXISTS "custom_fields";
+DROP TABLE IF EXISTS "task_board_status_limits";
+DROP TABLE IF EXISTS "task_labels";
+DROP TABLE IF EXISTS "labels";
+DROP TABLE IF EXISTS "comments";
+DROP TABLE IF EXISTS "tasks";
+DROP TABLE IF EXISTS "saved_views";
+DROP TABLE IF EXISTS "share_link_accesses";
+DROP TABLE IF EXISTS "share_links";
+DROP TABLE IF EXISTS "invitations";
+DROP TABLE IF EXISTS "task_board_teams";
+DROP TABLE IF EXISTS "team_members";
+DROP TABLE IF EXISTS "teams";
+DROP TABLE IF EXISTS "user_task_boards";
+DROP TABLE IF EXISTS "board_roles";
+DROP TABLE IF EXISTS "task_boards";
+DROP TABLE IF EXISTS "workspace_members";
+DROP TABLE IF EXISTS "workspaces";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,411 @@
+-- Baseline: the schema the models had when versioned migrations replaced
+-- AutoMigrate. Every statement is guarded, so a database that AutoMigrate
+-- already brought up to date records this version without changes.
+--
+-- An older database has users, task_boards, user_task_boards and tasks
+-- without the columns and foreign keys added to them since; those are added
+-- below each table, before the indexes that need them.
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "name" varchar(255) NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "password" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+
+CREATE TABLE IF NOT EXISTS "workspaces" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "name" varchar(255) NOT NULL,
+    "personal_owner_id" uuid,
+    "setting_default_board_role" varchar(50) NOT NULL DEFAULT 'viewer',
+    "setting_restrict_board_creation" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_workspaces_personal_owner_id" ON "workspaces" ("personal_owner_id");
+
+CREATE TABLE IF NOT EXISTS "workspace_members" (
+    "workspace_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "role" varchar(50) NOT NULL DEFAULT 'member',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("workspace_id","user_id"),
+    CONSTRAINT "fk_workspace_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_workspace_members_workspace" FOREIGN KEY ("workspace_id") REFERENCES "workspaces"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_workspace_members_user_id" ON "workspace_members" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "task_boards" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "title" varchar(255) NOT NULL,
+    "description" varchar(255),
+    "workspace_id" uuid,
+    "is_template" boolean NOT NULL DEFAULT false,
+    "archived_at" timestamptz,
+    "deleted_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_task_boards_workspace" FOREIGN KEY ("workspace_id") REFERENCES "workspaces"("id") ON DELETE RESTRICT
+);
+ALTER TABLE "task_boards"
+    ADD COLUMN IF NOT EXISTS "workspace_id" uuid,
+    ADD COLUMN IF NOT EXISTS "is_template" boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "archived_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"task_boards"'::regclass AND conname = 'fk_task_boards_workspace') THEN
+        ALTER TABLE "task_boards" ADD CONSTRAINT "fk_task_boards_workspace" FOREIGN KEY ("workspace_id") REFERENCES "workspaces"("id") ON DELETE RESTRICT;
+    END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS "idx_task_boards_deleted_at" ON "task_boards" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_task_boards_archived_at" ON "task_boards" ("archived_at");
+CREATE INDEX IF NOT EXISTS "idx_task_boards_workspace_id" ON "task_boards" ("workspace_id");
+
+CREATE TABLE IF NOT EXISTS "board_roles" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "name" varchar(50) NOT NULL,
+    "permissions" jsonb NOT NULL DEFAULT '[]',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_board_roles_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_board_roles_board_name" ON "board_roles" ("task_board_id","name");
+
+CREATE TABLE IF NOT EXISTS "user_task_boards" (
+    "user_id" uuid NOT NULL,
+    "task_board_id" uuid NOT NULL,
+    "role" varchar(50) NOT NULL DEFAULT 'viewer',
+    "board_role_id" uuid,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id","task_board_id"),
+    CONSTRAINT "fk_user_task_boards_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id"),
+    CONSTRAINT "fk_user_task_boards_board_role" FOREIGN KEY ("board_role_id") REFERENCES "board_roles"("id") ON DELETE RESTRICT,
+    CONSTRAINT "fk_user_task_boards_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+ALTER TABLE "user_task_boards"
+    ADD COLUMN IF NOT EXISTS "board_role_id" uuid;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"user_task_boards"'::regclass AND conname = 'fk_user_task_boards_task_board') THEN
+        ALTER TABLE "user_task_boards" ADD CONSTRAINT "fk_user_task_boards_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id");
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"user_task_boards"'::regclass AND conname = 'fk_user_task_boards_board_role') THEN
+        ALTER TABLE "user_task_boards" ADD CONSTRAINT "fk_user_task_boards_board_role" FOREIGN KEY ("board_role_id") REFERENCES "board_roles"("id") ON DELETE RESTRICT;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"user_task_boards"'::regclass AND conname = 'fk_user_task_boards_user') THEN
+        ALTER TABLE "user_task_boards" ADD CONSTRAINT "fk_user_task_boards_user" FOREIGN KEY ("user_id") REFERENCES "users"("id");
+    END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS "idx_user_task_boards_board_role_id" ON "user_task_boards" ("board_role_id");
+
+CREATE TABLE IF NOT EXISTS "teams" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "workspace_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_teams_workspace" FOREIGN KEY ("workspace_id") REFERENCES "workspaces"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_teams_workspace_name" ON "teams" ("workspace_id","name");
+
+CREATE TABLE IF NOT EXISTS "team_members" (
+    "team_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("team_id","user_id"),
+    CONSTRAINT "fk_teams_members" FOREIGN KEY ("team_id") REFERENCES "teams"("id"),
+    CONSTRAINT "fk_team_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_team_members_user_id" ON "team_members" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "task_board_teams" (
+    "task_board_id" uuid NOT NULL,
+    "team_id" uuid NOT NULL,
+    "role" varchar(50) NOT NULL DEFAULT 'viewer',
+    "board_role_id" uuid,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("task_board_id","team_id"),
+    CONSTRAINT "fk_task_board_teams_team" FOREIGN KEY ("team_id") REFERENCES "teams"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_task_board_teams_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_task_board_teams_board_role" FOREIGN KEY ("board_role_id") REFERENCES "board_roles"("id") ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS "idx_task_board_teams_team_id" ON "task_board_teams" ("team_id");
+CREATE INDEX IF NOT EXISTS "idx_task_board_teams_board_role_id" ON "task_board_teams" ("board_role_id");
+
+CREATE TABLE IF NOT EXISTS "invitations" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "role" varchar(50) NOT NULL,
+    "board_role_id" uuid,
+    "invited_by_id" uuid NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "status" varchar(50) NOT NULL DEFAULT 'pending',
+    "expires_at" timestamptz NOT NULL,
+    "responded_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_invitations_invited_by" FOREIGN KEY ("invited_by_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_invitations_board_role" FOREIGN KEY ("board_role_id") REFERENCES "board_roles"("id") ON DELETE SET NULL,
+    CONSTRAINT "fk_invitations_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_invitations_token_hash" ON "invitations" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_invitations_board_role_id" ON "invitations" ("board_role_id");
+CREATE INDEX IF NOT EXISTS "idx_invitations_email" ON "invitations" ("email");
+CREATE INDEX IF NOT EXISTS "idx_invitations_task_board_id" ON "invitations" ("task_board_id");
+
+CREATE TABLE IF NOT EXISTS "share_links" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "password_hash" varchar(255),
+    "expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_by_id" uuid NOT NULL,
+    "last_accessed_at" timestamptz,
+    "access_count" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_share_links_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_share_links_created_by" FOREIGN KEY ("created_by_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_share_links_token_hash" ON "share_links" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_share_links_task_board_id" ON "share_links" ("task_board_id");
+
+CREATE TABLE IF NOT EXISTS "share_link_accesses" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "share_link_id" uuid NOT NULL,
+    "outcome" varchar(50) NOT NULL,
+    "ip_address" varchar(64),
+    "user_agent" varchar(255),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_share_link_accesses_share_link" FOREIGN KEY ("share_link_id") REFERENCES "share_links"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_share_link_accesses_link_time" ON "share_link_accesses" ("share_link_id","created_at");
+
+CREATE TABLE IF NOT EXISTS "saved_views" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "owner_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "visibility" varchar(20) NOT NULL DEFAULT 'private',
+    "filter" jsonb NOT NULL DEFAULT '{}',
+    "sort" varchar(300),
+    "group_by" varchar(300),
+    "columns" jsonb NOT NULL DEFAULT '[]',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_saved_views_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_saved_views_owner" FOREIGN KEY ("owner_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_saved_views_owner_id" ON "saved_views" ("owner_id");
+CREATE INDEX IF NOT EXISTS "idx_saved_views_task_board_id" ON "saved_views" ("task_board_id");
+
+CREATE TABLE IF NOT EXISTS "tasks" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "description" varchar(255),
+    "status" varchar(50) NOT NULL DEFAULT 'todo',
+    "priority" varchar(50) NOT NULL DEFAULT 'medium',
+    "start_date" timestamptz NOT NULL,
+    "end_date" timestamptz NOT NULL,
+    "estimate_minutes" bigint,
+    "assignee_id" uuid,
+    "created_by_id" uuid,
+    "archived_at" timestamptz,
+    "deleted_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_task_boards_tasks" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id"),
+    CONSTRAINT "fk_tasks_assignee" FOREIGN KEY ("assignee_id") REFERENCES "users"("id") ON DELETE SET NULL,
+    CONSTRAINT "fk_tasks_created_by" FOREIGN KEY ("created_by_id") REFERENCES "users"("id") ON DELETE SET NULL
+);
+ALTER TABLE "tasks"
+    ADD COLUMN IF NOT EXISTS "estimate_minutes" bigint,
+    ADD COLUMN IF NOT EXISTS "assignee_id" uuid,
+    ADD COLUMN IF NOT EXISTS "created_by_id" uuid,
+    ADD COLUMN IF NOT EXISTS "archived_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"tasks"'::regclass AND conname = 'fk_task_boards_tasks') THEN
+        ALTER TABLE "tasks" ADD CONSTRAINT "fk_task_boards_tasks" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id");
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"tasks"'::regclass AND conname = 'fk_tasks_assignee') THEN
+        ALTER TABLE "tasks" ADD CONSTRAINT "fk_tasks_assignee" FOREIGN KEY ("assignee_id") REFERENCES "users"("id") ON DELETE SET NULL;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"tasks"'::regclass AND conname = 'fk_tasks_created_by') THEN
+        ALTER TABLE "tasks" ADD CONSTRAINT "fk_tasks_created_by" FOREIGN KEY ("created_by_id") REFERENCES "users"("id") ON DELETE SET NULL;
+    END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS "idx_tasks_deleted_at" ON "tasks" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_tasks_archived_at" ON "tasks" ("archived_at");
+CREATE INDEX IF NOT EXISTS "idx_tasks_created_by_id" ON "tasks" ("created_by_id");
+CREATE INDEX IF NOT EXISTS "idx_tasks_assignee_id" ON "tasks" ("assignee_id");
+CREATE INDEX IF NOT EXISTS "idx_tasks_task_board_id" ON "tasks" ("task_board_id");
+
+CREATE TABLE IF NOT EXISTS "comments" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "body" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_comments_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_comments_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_comments_task_id" ON "comments" ("task_id");
+
+CREATE TABLE IF NOT EXISTS "labels" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "color" varchar(7) NOT NULL DEFAULT '#808080',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_labels_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_labels_board_name" ON "labels" ("task_board_id","name");
+
+CREATE TABLE IF NOT EXISTS "task_labels" (
+    "task_id" uuid DEFAULT uuid_generate_v4(),
+    "label_id" uuid DEFAULT uuid_generate_v4(),
+    PRIMARY KEY ("task_id","label_id"),
+    CONSTRAINT "fk_task_labels_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_task_labels_label" FOREIGN KEY ("label_id") REFERENCES "labels"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "task_board_status_limits" (
+    "task_board_id" uuid NOT NULL,
+    "status" varchar(50) NOT NULL,
+    "wip_limit" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("task_board_id","status")
+);
+
+CREATE TABLE IF NOT EXISTS "custom_fields" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "name" varchar(255) NOT NULL,
+    "type" varchar(50) NOT NULL,
+    "options" jsonb NOT NULL DEFAULT '[]',
+    "required" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_custom_fields_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_custom_fields_board_name" ON "custom_fields" ("task_board_id","name");
+
+CREATE TABLE IF NOT EXISTS "task_custom_field_values" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_id" uuid NOT NULL,
+    "custom_field_id" uuid NOT NULL,
+    "text_value" varchar(2048),
+    "number_value" decimal,
+    "date_value" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_tasks_custom_field_values" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_custom_fields_values" FOREIGN KEY ("custom_field_id") REFERENCES "custom_fields"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_cf_values_date" ON "task_custom_field_values" ("custom_field_id","date_value");
+CREATE INDEX IF NOT EXISTS "idx_cf_values_number" ON "task_custom_field_values" ("custom_field_id","number_value");
+CREATE INDEX IF NOT EXISTS "idx_cf_values_text" ON "task_custom_field_values" ("custom_field_id","text_value");
+CREATE INDEX IF NOT EXISTS "idx_task_custom_field_values_task_id" ON "task_custom_field_values" ("task_id");
+
+CREATE TABLE IF NOT EXISTS "task_recurrences" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_id" uuid NOT NULL,
+    "rule" varchar(255) NOT NULL,
+    "series_start" timestamptz NOT NULL,
+    "occurrences" bigint NOT NULL DEFAULT 1,
+    "next_run_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_task_recurrences_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_task_recurrences_next_run_at" ON "task_recurrences" ("next_run_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_task_recurrences_task_id" ON "task_recurrences" ("task_id");
+
+CREATE TABLE IF NOT EXISTS "reminder_settings" (
+    "user_id" uuid,
+    "offsets_minutes" jsonb NOT NULL,
+    "channels" jsonb NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id"),
+    CONSTRAINT "fk_reminder_settings_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "task_reminders" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "kind" varchar(50) NOT NULL,
+    "offset_minutes" bigint NOT NULL,
+    "due_at" timestamptz NOT NULL,
+    "sent_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_task_reminders_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_task_reminders_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_task_reminders_user_id" ON "task_reminders" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_task_reminders_once" ON "task_reminders" ("task_id","user_id","kind","offset_minutes","due_at");
+
+CREATE TABLE IF NOT EXISTS "time_entries" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_id" uuid NOT NULL,
+    "task_board_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "date" date NOT NULL,
+    "duration_seconds" bigint NOT NULL DEFAULT 0,
+    "started_at" timestamptz,
+    "ended_at" timestamptz,
+    "note" varchar(1000),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_time_entries_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_time_entries_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_time_entries_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_time_entries_date" ON "time_entries" ("date");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_time_entries_running" ON "time_entries" ("user_id") WHERE started_at IS NOT NULL AND ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_time_entries_user_id" ON "time_entries" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_time_entries_task_board_id" ON "time_entries" ("task_board_id");
+CREATE INDEX IF NOT EXISTS "idx_time_entries_task_id" ON "time_entries" ("task_id");
+
+-- Full-text document of each task for search, title weighted above
+-- description. Generated by Postgres, so the Task model leaves it out.
+ALTER TABLE "tasks" ADD COLUMN IF NOT EXISTS "search_vector" tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS "idx_tasks_search_vector" ON "tasks" USING GIN ("search_vector");
@@ -0,0 +1,3 @@
+-- Boards stay in the workspaces they were moved to: after the backfill they
+-- cannot be told apart from boards created in them.
+SELECT 1;
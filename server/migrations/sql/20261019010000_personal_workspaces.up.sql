@@ -0,0 +1,34 @@
+-- Moves boards created before workspaces existed into the personal workspace
+-- of their earliest owner, creating it on the way. Boards already in a
+-- workspace are left alone, so on a database that never had such boards
+-- this changes nothing.
+
+INSERT INTO "workspaces" ("name", "personal_owner_id", "created_at", "updated_at")
+SELECT 'Personal', "users"."id", NOW(), NOW()
+FROM "users"
+WHERE NOT EXISTS (SELECT 1 FROM "workspaces" WHERE "workspaces"."personal_owner_id" = "users"."id")
+AND EXISTS (
+    SELECT 1 FROM "user_task_boards"
+    JOIN "task_boards" ON "task_boards"."id" = "user_task_boards"."task_board_id"
+    WHERE "user_task_boards"."user_id" = "users"."id"
+    AND "user_task_boards"."role" = 'owner'
+    AND "task_boards"."workspace_id" IS NULL
+);
+
+INSERT INTO "workspace_members" ("workspace_id", "user_id", "role", "created_at", "updated_at")
+SELECT "workspaces"."id", "workspaces"."personal_owner_id", 'admin', NOW(), NOW()
+FROM "workspaces"
+WHERE "workspaces"."personal_owner_id" IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+UPDATE "task_boards"
+SET "workspace_id" = "workspaces"."id"
+FROM "workspaces"
+WHERE "task_boards"."workspace_id" IS NULL
+AND "workspaces"."personal_owner_id" = (
+    SELECT "user_task_boards"."user_id" FROM "user_task_boards"
+    WHERE "user_task_boards"."task_board_id" = "task_boards"."id"
+    AND "user_task_boards"."role" = 'owner'
+    ORDER BY "user_task_boards"."created_at"
+    LIMIT 1
+);
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "actor_id" uuid,
+    "action" varchar(100) NOT NULL,
+    "entity_type" varchar(50) NOT NULL,
+    "entity_id" uuid,
+    "task_board_id" uuid,
+    "changes" jsonb NOT NULL DEFAULT '{}',
+    "ip_address" varchar(64),
+    "user_agent" varchar(255),
+    "request_id" varchar(64),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_time" ON "audit_logs" ("actor_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_entity_time" ON "audit_logs" ("entity_type","entity_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_board_time" ON "audit_logs" ("task_board_id","created_at");
+
+-- The log is append-only: entries can be added but never changed or removed.
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON "audit_logs"
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS "task_activities" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_id" uuid NOT NULL,
+    "task_board_id" uuid NOT NULL,
+    "actor_id" uuid,
+    "kind" varchar(20) NOT NULL,
+    "field" varchar(50),
+    "old_value" text,
+    "new_value" text,
+    "comment_id" uuid,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_task_activities_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_task_activities_actor" FOREIGN KEY ("actor_id") REFERENCES "users"("id") ON DELETE SET NULL,
+    CONSTRAINT "fk_task_activities_comment" FOREIGN KEY ("comment_id") REFERENCES "comments"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_task_activities_task_time" ON "task_activities" ("task_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_task_activities_board_time" ON "task_activities" ("task_board_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_task_activities_actor_id" ON "task_activities" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_task_activities_comment_id" ON "task_activities" ("comment_id");
+
+-- Existing tasks and comments start their timelines.
+INSERT INTO "task_activities" ("task_id", "task_board_id", "actor_id", "kind", "created_at")
+SELECT "id", "task_board_id", "created_by_id", 'created', "created_at" FROM "tasks";
+INSERT INTO "task_activities" ("task_id", "task_board_id", "actor_id", "kind", "comment_id", "created_at")
+SELECT "comments"."task_id", "tasks"."task_board_id", "comments"."user_id", 'commented', "comments"."id", "comments"."created_at"
+FROM "comments" JOIN "tasks" ON "tasks"."id" = "comments"."task_id";
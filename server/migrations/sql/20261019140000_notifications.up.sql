@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "user_id" uuid NOT NULL,
+    "actor_id" uuid,
+    "kind" varchar(50) NOT NULL,
+    "task_board_id" uuid,
+    "task_id" uuid,
+    "comment_id" uuid,
+    "fields" jsonb NOT NULL DEFAULT '[]',
+    "count" bigint NOT NULL DEFAULT 1,
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_notifications_actor" FOREIGN KEY ("actor_id") REFERENCES "users"("id") ON DELETE SET NULL,
+    CONSTRAINT "fk_notifications_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_notifications_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_notifications_comment" FOREIGN KEY ("comment_id") REFERENCES "comments"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_user_time" ON "notifications" ("user_id","updated_at");
+CREATE INDEX IF NOT EXISTS "idx_notifications_actor_id" ON "notifications" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_notifications_task_board_id" ON "notifications" ("task_board_id");
+CREATE INDEX IF NOT EXISTS "idx_notifications_task_id" ON "notifications" ("task_id");
+CREATE INDEX IF NOT EXISTS "idx_notifications_comment_id" ON "notifications" ("comment_id");
+CREATE INDEX IF NOT EXISTS "idx_notifications_unread" ON "notifications" ("user_id") WHERE "read_at" IS NULL;
+
+-- A user has at most one unread task_updated notification per task; further
+-- changes collapse into it.
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_notifications_unread_task_updates" ON "notifications" ("user_id","task_id")
+    WHERE "kind" = 'task_updated' AND "read_at" IS NULL;
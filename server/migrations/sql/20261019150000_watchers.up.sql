@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS "task_watchers" (
+    "task_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "watching" boolean NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("task_id","user_id"),
+    CONSTRAINT "fk_task_watchers_task" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_task_watchers_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_task_watchers_user_id" ON "task_watchers" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "task_board_watchers" (
+    "task_board_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("task_board_id","user_id"),
+    CONSTRAINT "fk_task_board_watchers_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_task_board_watchers_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_task_board_watchers_user_id" ON "task_board_watchers" ("user_id");
+
+-- Creators, assignees and commenters of existing tasks watch them.
+INSERT INTO "task_watchers" ("task_id", "user_id", "watching", "created_at", "updated_at")
+SELECT "task_id", "user_id", true, now(), now() FROM (
+    SELECT "id" AS "task_id", "created_by_id" AS "user_id" FROM "tasks" WHERE "created_by_id" IS NOT NULL
+    UNION
+    SELECT "id", "assignee_id" FROM "tasks" WHERE "assignee_id" IS NOT NULL
+    UNION
+    SELECT "task_id", "user_id" FROM "comments"
+) AS "watchers"
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE "task_board_status_limits" DROP CONSTRAINT IF EXISTS "fk_task_board_status_limits_task_board";
@@ -0,0 +1,10 @@
+-- Limits of boards that were deleted before the key existed are dropped.
+DELETE FROM "task_board_status_limits"
+WHERE NOT EXISTS (SELECT 1 FROM "task_boards" WHERE "task_boards"."id" = "task_board_status_limits"."task_board_id");
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = '"task_board_status_limits"'::regclass AND conname = 'fk_task_board_status_limits_task_board') THEN
+        ALTER TABLE "task_board_status_limits" ADD CONSTRAINT "fk_task_board_status_limits_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id") ON DELETE CASCADE;
+    END IF;
+END $$;
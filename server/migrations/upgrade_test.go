@@ -0,0 +1,158 @@
+package migrations
+
+import (
+	"server/models"
+	"server/testdb"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// preSeriesSQL is the schema AutoMigrate gave the original four models,
+// before workspaces, templates, archiving, assignees and the rest.
+const preSeriesSQL = `
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE TABLE "users" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "name" varchar(255) NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "password" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+CREATE TABLE "task_boards" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "title" varchar(255) NOT NULL,
+    "description" varchar(255),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE TABLE "user_task_boards" (
+    "user_id" uuid NOT NULL,
+    "task_board_id" uuid NOT NULL,
+    "role" varchar(50) NOT NULL DEFAULT 'viewer',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id","task_board_id"),
+    CONSTRAINT "fk_user_task_boards_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_user_task_boards_task_board" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id")
+);
+CREATE TABLE "tasks" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "task_board_id" uuid NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "description" varchar(255),
+    "status" varchar(50) NOT NULL DEFAULT 'todo',
+    "priority" varchar(50) NOT NULL DEFAULT 'medium',
+    "start_date" timestamptz NOT NULL,
+    "end_date" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_task_boards_tasks" FOREIGN KEY ("task_board_id") REFERENCES "task_boards"("id")
+);
+CREATE INDEX "idx_tasks_task_board_id" ON "tasks" ("task_board_id");
+`
+
+func TestUpgradeFromPreSeriesSchema(t *testing.T) {
+	db := testdb.Open(t)
+	if err := db.Exec(preSeriesSQL).Error; err != nil {
+		t.Fatalf("create pre-series schema: %v", err)
+	}
+
+	userID, taskBoardID := uuid.New(), uuid.New()
+	now := time.Now().UTC()
+	seed := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{`INSERT INTO users (id, name, email, password, created_at, updated_at) VALUES (?, 'Ana', 'ana@example.com', 'x', ?, ?)`, []interface{}{userID, now, now}},
+		{`INSERT INTO task_boards (id, title, created_at, updated_at) VALUES (?, 'Board', ?, ?)`, []interface{}{taskBoardID, now, now}},
+		{`INSERT INTO user_task_boards (user_id, task_board_id, role, created_at, updated_at) VALUES (?, ?, 'owner', ?, ?)`, []interface{}{userID, taskBoardID, now, now}},
+		{`INSERT INTO tasks (task_board_id, title, start_date, end_date, created_at, updated_at) VALUES (?, 'Task', ?, ?, ?, ?)`, []interface{}{taskBoardID, now, now, now, now}},
+	}
+	for _, row := range seed {
+		if err := db.Exec(row.sql, row.args...).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.User{}, &models.TaskBoard{}, &models.UserTaskBoard{}, &models.Task{}} {
+		statement := &gorm.Statement{DB: db}
+		if err := statement.Parse(model); err != nil {
+			t.Fatalf("parse %T: %v", model, err)
+		}
+		for _, field := range statement.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !db.Migrator().HasColumn(model, field.DBName) {
+				t.Errorf("%s.%s is missing", statement.Schema.Table, field.DBName)
+			}
+		}
+	}
+
+	constraints := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.TaskBoard{}, "fk_task_boards_workspace"},
+		{&models.UserTaskBoard{}, "fk_user_task_boards_board_role"},
+		{&models.Task{}, "fk_tasks_assignee"},
+		{&models.Task{}, "fk_tasks_created_by"},
+		{&models.TaskBoardStatusLimit{}, "fk_task_board_status_limits_task_board"},
+	}
+	for _, constraint := range constraints {
+		if !db.Migrator().HasConstraint(constraint.model, constraint.name) {
+			t.Errorf("constraint %s is missing", constraint.name)
+		}
+	}
+
+	var board models.TaskBoard
+	if err := db.First(&board, "id = ?", taskBoardID).Error; err != nil {
+		t.Fatalf("find board: %v", err)
+	}
+	var workspace models.Workspace
+	if err := db.First(&workspace, "personal_owner_id = ?", userID).Error; err != nil {
+		t.Fatalf("personal workspace was not created: %v", err)
+	}
+	if board.WorkspaceID == nil || *board.WorkspaceID != workspace.ID {
+		t.Errorf("board workspace = %v, want %v", board.WorkspaceID, workspace.ID)
+	}
+	var member models.WorkspaceMember
+	if err := db.First(&member, "workspace_id = ? AND user_id = ?", workspace.ID, userID).Error; err != nil {
+		t.Fatalf("owner is not a member of their personal workspace: %v", err)
+	}
+	if member.Role != "admin" {
+		t.Errorf("owner's workspace role = %q, want admin", member.Role)
+	}
+
+	var activities int64
+	if err := db.Model(&models.TaskActivity{}).Count(&activities).Error; err != nil {
+		t.Fatalf("count activities: %v", err)
+	}
+	if activities != 1 {
+		t.Errorf("backfilled %d task activities, want 1", activities)
+	}
+
+	again, err := migrator.Up(0)
+	if err != nil {
+		t.Fatalf("second Up: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("second Up applied %d migrations, want none", len(again))
+	}
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit entity types.
+const (
+	AuditEntityTask         = "task"
+	AuditEntityTaskBoard    = "task_board"
+	AuditEntityCollaborator = "collaborator"
+	AuditEntityUser         = "user"
+)
+
+// Audit actions.
+const (
+	AuditTaskCreate        = "task.create"
+	AuditTaskUpdate        = "task.update"
+	AuditTaskMove          = "task.move"
+	AuditTaskDelete        = "task.delete"
+	AuditTaskArchive       = "task.archive"
+	AuditTaskRestore       = "task.restore"
+	AuditBoardCreate       = "board.create"
+	AuditBoardUpdate       = "board.update"
+	AuditBoardDelete       = "board.delete"
+	AuditBoardArchive      = "board.archive"
+	AuditBoardRestore      = "board.restore"
+	AuditBoardWIPLimits    = "board.wip_limits"
+	AuditBoardTransfer     = "board.transfer_ownership"
+	AuditMemberAdd         = "member.add"
+	AuditMemberUpdate      = "member.update"
+	AuditMemberRemove      = "member.remove"
+	AuditUserCreate        = "user.create"
+	AuditUserLogin         = "user.login"
+	AuditUserLoginRejected = "user.login_rejected"
+)
+
+// AuditLog is one entry of the append-only record of changes: who did what
+// to which entity, from where, and how the entity's fields changed. The
+// table keeps no foreign keys so entries outlive what they describe.
+type AuditLog struct {
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	// ActorID is the user who made the change; for a sign-in attempt, the
+	// account signed in to.
+	ActorID    *uuid.UUID `gorm:"type:uuid;index:idx_audit_logs_actor_time" json:"actor_id"`
+	Action     string     `gorm:"size:100;not null" json:"action"`
+	EntityType string     `gorm:"size:50;not null;index:idx_audit_logs_entity_time" json:"entity_type"`
+	EntityID   *uuid.UUID `gorm:"type:uuid;index:idx_audit_logs_entity_time" json:"entity_id"`
+	// TaskBoardID is the board the entity belongs to, when it belongs to one.
+	TaskBoardID *uuid.UUID   `gorm:"type:uuid;index:idx_audit_logs_board_time" json:"task_board_id"`
+	Changes     AuditChanges `gorm:"type:jsonb;not null;default:'{}'" json:"changes"`
+	IPAddress   string       `gorm:"size:64" json:"ip_address"`
+	UserAgent   string       `gorm:"size:255" json:"user_agent"`
+	RequestID   string       `gorm:"size:64" json:"request_id"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime;index:idx_audit_logs_actor_time;index:idx_audit_logs_entity_time;index:idx_audit_logs_board_time" json:"created_at"`
+}
+
+// AuditChange is a field's value before and after a change. From is left
+// out for created entities and To for deleted ones.
+type AuditChange struct {
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
+
+// AuditChanges maps the changed fields of an entity to their change,
+// persisted as a JSONB object.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]AuditChange(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = AuditChanges{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into AuditChanges", value)
+	}
+	return json.Unmarshal(data, (*map[string]AuditChange)(c))
+}
+
+// RequestMeta describes the HTTP request a change was made through, so the
+// audit log can say where it came from.
+type RequestMeta struct {
+	IPAddress string
+	UserAgent string
+	RequestID string
+}
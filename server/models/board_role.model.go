@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BoardRole is a custom role a board's owners assemble from the permission
+// catalogue. Members holding it have UserTaskBoard.Role set to "custom".
+type BoardRole struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskBoardID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_board_roles_board_name" json:"task_board_id"`
+	Name        string     `gorm:"size:50;not null;uniqueIndex:idx_board_roles_board_name" json:"name" validate:"required,max=50"`
+	Permissions StringList `gorm:"type:jsonb;not null;default:'[]'" json:"permissions"`
+	// Builtin marks the preset roles, which are listed alongside custom ones
+	// but never stored.
+	Builtin   bool      `gorm:"-" json:"builtin"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	TaskBoard TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "github.com/google/uuid"
+
+const (
+	BulkItemSucceeded  = "succeeded"
+	BulkItemFailed     = "failed"
+	BulkItemRolledBack = "rolled_back"
+)
+
+// BulkTaskItem reports what happened to one task in a bulk operation.
+type BulkTaskItem struct {
+	TaskID uuid.UUID `json:"task_id"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// BulkTaskResult is the outcome of a bulk operation. Committed is false when
+// an atomic operation was rolled back.
+type BulkTaskResult struct {
+	Operation string         `json:"operation"`
+	Mode      string         `json:"mode"`
+	Committed bool           `json:"committed"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Items     []BulkTaskItem `json:"items"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CustomFieldText         = "text"
+	CustomFieldNumber       = "number"
+	CustomFieldDate         = "date"
+	CustomFieldSingleSelect = "single_select"
+	CustomFieldMultiSelect  = "multi_select"
+	CustomFieldUser         = "user"
+	CustomFieldURL          = "url"
+)
+
+// CustomField is a typed, board-scoped attribute that tasks on the board can carry.
+type CustomField struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskBoardID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_custom_fields_board_name" json:"task_board_id"`
+	Name        string     `gorm:"size:255;not null;uniqueIndex:idx_custom_fields_board_name" json:"name" validate:"required,max=255"`
+	Type        string     `gorm:"size:50;not null" json:"type" validate:"required,oneof=text number date single_select multi_select user url"`
+	Options     StringList `gorm:"type:jsonb;not null;default:'[]'" json:"options"`
+	Required    bool       `gorm:"not null;default:false" json:"required"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	TaskBoard TaskBoard              `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+	Values    []TaskCustomFieldValue `gorm:"foreignKey:CustomFieldID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// ValueColumn is the typed column of task_custom_field_values holding this field's values.
+func (f CustomField) ValueColumn() string {
+	switch f.Type {
+	case CustomFieldNumber:
+		return "number_value"
+	case CustomFieldDate:
+		return "date_value"
+	default:
+		return "text_value"
+	}
+}
+
+// TaskCustomFieldValue stores one value of a custom field on a task. Multi-select
+// fields store one row per selected option so every value stays indexable.
+type TaskCustomFieldValue struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"-"`
+	TaskID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"task_id"`
+	CustomFieldID uuid.UUID  `gorm:"type:uuid;not null;index:idx_cf_values_text,priority:1;index:idx_cf_values_number,priority:1;index:idx_cf_values_date,priority:1" json:"custom_field_id"`
+	TextValue     *string    `gorm:"size:2048;index:idx_cf_values_text,priority:2" json:"text_value,omitempty"`
+	NumberValue   *float64   `gorm:"index:idx_cf_values_number,priority:2" json:"number_value,omitempty"`
+	DateValue     *time.Time `gorm:"index:idx_cf_values_date,priority:2" json:"date_value,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CollaboratorActive marks members in a collaborator list that also shows
+// invitations.
+const CollaboratorActive = "active"
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation asks someone, by email, to join a task board with a role. The
+// email does not need an account yet: registering through the invitation
+// link joins the board straight away.
+type Invitation struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskBoardID uuid.UUID `gorm:"type:uuid;not null;index" json:"task_board_id"`
+	Email       string    `gorm:"size:255;not null;index" json:"email" validate:"required,email"`
+	Role        string    `gorm:"size:50;not null" json:"role" validate:"required,oneof=owner editor viewer custom"`
+	// BoardRoleID names the custom role when Role is "custom". The role cannot
+	// be deleted while a pending invitation holds it.
+	BoardRoleID *uuid.UUID `gorm:"type:uuid;index" json:"board_role_id"`
+	InvitedByID uuid.UUID  `gorm:"type:uuid;not null" json:"invited_by_id"`
+	// TokenHash is the SHA-256 of the token sent in the invitation link; the
+	// token itself is never stored.
+	TokenHash   string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Status      string     `gorm:"size:50;not null;default:'pending'" json:"status"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	TaskBoard TaskBoard  `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"task_board,omitempty"`
+	InvitedBy User       `gorm:"foreignKey:InvitedByID;constraint:OnDelete:CASCADE" json:"invited_by,omitempty"`
+	BoardRole *BoardRole `gorm:"foreignKey:BoardRoleID;constraint:OnDelete:SET NULL" json:"board_role,omitempty"`
+}
+
+// CurrentStatus reports a pending invitation past its expiry as expired.
+func (invitation *Invitation) CurrentStatus(now time.Time) string {
+	if invitation.Status == InvitationPending && !now.Before(invitation.ExpiresAt) {
+		return InvitationExpired
+	}
+	return invitation.Status
+}
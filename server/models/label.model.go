@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Label is a board-scoped tag that can be attached to the board's tasks.
+type Label struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskBoardID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_labels_board_name" json:"task_board_id"`
+	Name        string    `gorm:"size:100;not null;uniqueIndex:idx_labels_board_name" json:"name" validate:"required,max=100"`
+	Color       string    `gorm:"size:7;not null;default:'#808080'" json:"color"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	TaskBoard TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification kinds.
+const (
+	NotificationBoardAdded   = "board_added"
+	NotificationTaskAssigned = "task_assigned"
+	NotificationMentioned    = "mentioned"
+	NotificationTaskUpdated  = "task_updated"
+	NotificationTaskCreated  = "task_created"
+)
+
+// Notification is an entry of a user's inbox. Changes to a task collapse
+// into the user's unread task_updated notification for it, which counts
+// them and gathers the fields they touched, until the user reads it.
+type Notification struct {
+	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index:idx_notifications_user_time" json:"user_id"`
+	// ActorID is who caused the notification, the latest one for a collapsed
+	// notification.
+	ActorID     *uuid.UUID `gorm:"type:uuid;index" json:"actor_id"`
+	Kind        string     `gorm:"size:50;not null" json:"kind"`
+	TaskBoardID *uuid.UUID `gorm:"type:uuid;index" json:"task_board_id"`
+	TaskID      *uuid.UUID `gorm:"type:uuid;index" json:"task_id"`
+	// CommentID is the comment a user was mentioned in.
+	CommentID *uuid.UUID `gorm:"type:uuid;index" json:"comment_id"`
+	Fields    StringList `gorm:"type:jsonb;not null;default:'[]'" json:"fields"`
+	Count     int        `gorm:"not null;default:1" json:"count"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime;index:idx_notifications_user_time" json:"updated_at"`
+
+	User      User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Actor     *User      `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL" json:"actor,omitempty"`
+	TaskBoard *TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"task_board,omitempty"`
+	Task      *Task      `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"task,omitempty"`
+	Comment   *Comment   `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"comment,omitempty"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/google/uuid"
+
+// BoardPermissions lists the actions a user may perform on a board, or on one
+// of its tasks when TaskID is set. Role is empty for users not on the board;
+// Permissions are the catalogue entries their role holds.
+type BoardPermissions struct {
+	TaskBoardID uuid.UUID  `json:"task_board_id"`
+	TaskID      *uuid.UUID `json:"task_id,omitempty"`
+	Role        string     `json:"role"`
+	Permissions []string   `json:"permissions"`
+	Actions     []string   `json:"actions"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ReminderDueSoon = "due_soon"
+	ReminderOverdue = "overdue"
+
+	ReminderChannelWebSocket = "websocket"
+	ReminderChannelEmail     = "email"
+)
+
+// ReminderSetting holds how long before a task's EndDate a user wants to be
+// reminded, and which channels besides the in-app list to use.
+type ReminderSetting struct {
+	UserID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	OffsetsMinutes IntList    `gorm:"type:jsonb;not null" json:"offsets_minutes"`
+	Channels       StringList `gorm:"type:jsonb;not null" json:"channels"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// DefaultReminderSetting is used for users who never changed their settings.
+func DefaultReminderSetting(userID uuid.UUID) ReminderSetting {
+	return ReminderSetting{
+		UserID:         userID,
+		OffsetsMinutes: IntList{24 * 60},
+		Channels:       StringList{ReminderChannelWebSocket, ReminderChannelEmail},
+	}
+}
+
+// TaskReminder records a reminder that has gone out. The unique index makes
+// each (task, user, kind, offset, due date) reminder claimable exactly once,
+// and moving a task's EndDate makes its reminders due again.
+type TaskReminder struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_task_reminders_once,priority:1" json:"task_id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_task_reminders_once,priority:2;index" json:"user_id"`
+	Kind          string    `gorm:"size:50;not null;uniqueIndex:idx_task_reminders_once,priority:3" json:"kind"`
+	OffsetMinutes int       `gorm:"not null;uniqueIndex:idx_task_reminders_once,priority:4" json:"offset_minutes"`
+	DueAt         time.Time `gorm:"not null;uniqueIndex:idx_task_reminders_once,priority:5" json:"due_at"`
+	SentAt        time.Time `gorm:"not null" json:"sent_at"`
+
+	Task Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"task,omitempty"`
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ViewPrivate = "private"
+	ViewShared  = "shared"
+)
+
+// SavedView is a named board layout: a task filter, a sort, a grouping and
+// the columns to show. Private views are only seen by their owner; shared
+// ones by everyone who can view the board.
+type SavedView struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskBoardID uuid.UUID  `gorm:"type:uuid;not null;index" json:"task_board_id"`
+	OwnerID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"owner_id"`
+	Name        string     `gorm:"size:100;not null" json:"name"`
+	Visibility  string     `gorm:"size:20;not null;default:'private'" json:"visibility"`
+	Filter      ViewFilter `gorm:"type:jsonb;not null;default:'{}'" json:"filter"`
+	// Sort and GroupBy take a task column or "cf:<field id>"; Sort may be
+	// prefixed with "-" for descending order.
+	Sort      string     `gorm:"size:300" json:"sort"`
+	GroupBy   string     `gorm:"size:300" json:"group_by"`
+	Columns   StringList `gorm:"type:jsonb;not null;default:'[]'" json:"columns"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Stale lists what the view refers to that is no longer on the board, such
+	// as a deleted label; those parts are ignored when the view is applied.
+	Stale []string `gorm:"-" json:"stale,omitempty"`
+
+	TaskBoard TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+	Owner     User      `gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// ViewFilter holds the task filters of a saved view, in the form
+// GET /task-boards/:id takes them. Assignees are user IDs, "me" or "none".
+type ViewFilter struct {
+	Status       []string          `json:"status,omitempty"`
+	Priority     []string          `json:"priority,omitempty"`
+	Labels       []uuid.UUID       `json:"labels,omitempty"`
+	Assignees    []string          `json:"assignees,omitempty"`
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+}
+
+func (f ViewFilter) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (f *ViewFilter) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*f = ViewFilter{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ViewFilter", value)
+	}
+	return json.Unmarshal(data, f)
+}
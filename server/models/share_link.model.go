@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink gives anyone holding its token read-only access to a board,
+// without an account, until it expires or is revoked.
+type ShareLink struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskBoardID uuid.UUID `gorm:"type:uuid;not null;index" json:"task_board_id"`
+	// TokenHash is the SHA-256 of the token in the link; the token itself is
+	// only returned when the link is created.
+	TokenHash string `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	// PasswordHash is the bcrypt hash of the optional password.
+	PasswordHash   string     `gorm:"size:255" json:"-"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedByID    uuid.UUID  `gorm:"type:uuid;not null" json:"created_by_id"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	AccessCount    int64      `gorm:"not null;default:0" json:"access_count"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	TaskBoard TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedBy User      `gorm:"foreignKey:CreatedByID;constraint:OnDelete:CASCADE" json:"created_by,omitempty"`
+
+	PasswordProtected bool `gorm:"-" json:"password_protected"`
+	// Token and URL are only filled in on the response that creates the link.
+	Token string `gorm:"-" json:"token,omitempty"`
+	URL   string `gorm:"-" json:"url,omitempty"`
+}
+
+// Active reports whether the link still grants access at now.
+func (link *ShareLink) Active(now time.Time) bool {
+	return link.RevokedAt == nil && (link.ExpiresAt == nil || now.Before(*link.ExpiresAt))
+}
+
+const (
+	ShareAccessGranted          = "granted"
+	ShareAccessPasswordRequired = "password_required"
+	ShareAccessWrongPassword    = "wrong_password"
+	ShareAccessExpired          = "expired"
+	ShareAccessRevoked          = "revoked"
+	ShareAccessLocked           = "locked"
+)
+
+// ShareLinkAccess records one request made through a share link, whether it
+// was let through or not.
+type ShareLinkAccess struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ShareLinkID uuid.UUID `gorm:"type:uuid;not null;index:idx_share_link_accesses_link_time" json:"share_link_id"`
+	Outcome     string    `gorm:"size:50;not null" json:"outcome"`
+	IPAddress   string    `gorm:"size:64" json:"ip_address"`
+	UserAgent   string    `gorm:"size:255" json:"user_agent"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index:idx_share_link_accesses_link_time" json:"created_at"`
+
+	ShareLink ShareLink `gorm:"foreignKey:ShareLinkID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// PublicTaskBoard is what a share link shows: the board and its tasks, with
+// collaborators listed by name only so their emails stay private.
+type PublicTaskBoard struct {
+	ID            uuid.UUID            `json:"id"`
+	Title         string               `json:"title"`
+	Description   string               `json:"description"`
+	ArchivedAt    *time.Time           `json:"archived_at"`
+	Columns       []TaskBoardColumn    `json:"columns"`
+	Tasks         []Task               `json:"tasks"`
+	Collaborators []PublicCollaborator `json:"collaborators"`
+}
+
+type PublicCollaborator struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Role   string    `json:"role"`
+}
@@ -4,28 +4,92 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type TaskBoard struct {
 	ID          uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
 	Title       string       `gorm:"size:255;not null" json:"title" validate:"required,max=255"`
 	Description string       `gorm:"size:255" json:"description" validate:"max=255"`
+	// WorkspaceID is the workspace the board belongs to; its members reach the
+	// board through their workspace role.
+	WorkspaceID *uuid.UUID   `gorm:"type:uuid;index" json:"workspace_id"`
+	// IsTemplate marks a board kept only to be cloned; it is hidden from board
+	// lists, and its tasks never trigger reminders or recurrences.
+	IsTemplate  bool         `gorm:"not null;default:false" json:"is_template"`
+	// ArchivedAt hides the board from lists and makes it read-only.
+	ArchivedAt  *time.Time     `gorm:"index" json:"archived_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
 	
 	Users       []User       `gorm:"many2many:user_task_boards;" json:"users,omitempty"`
 	Tasks       []Task       `gorm:"foreignKey:TaskBoardID" json:"tasks,omitempty"`
+	Workspace   *Workspace   `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:RESTRICT" json:"-"`
+
+	Columns     []TaskBoardColumn `gorm:"-" json:"columns,omitempty"`
+	// View is the saved view the tasks were filtered with, when one was asked for.
+	View        *SavedView        `gorm:"-" json:"view,omitempty"`
+}
+
+// TaskStatuses are the columns every board is rendered with, in display order.
+var TaskStatuses = []string{"todo", "in_progress", "done"}
+
+// TaskBoardStatusLimit caps how many tasks a board may hold in one status column.
+type TaskBoardStatusLimit struct {
+	TaskBoardID uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"task_board_id"`
+	Status      string    `gorm:"size:50;not null;primaryKey" json:"status" validate:"required,oneof=todo in_progress done"`
+	Limit       int       `gorm:"column:wip_limit;not null" json:"limit" validate:"min=1"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	TaskBoard TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TaskBoardColumn reports the current task count of a status column against its WIP limit.
+type TaskBoardColumn struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+	Limit  *int   `json:"limit"`
 }
 
 type UserTaskBoard struct {
 	UserID      uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"user_id"`
 	TaskBoardID uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"task_board_id"`
-	Role        string    `gorm:"size:50;not null;default:'viewer'" json:"role" validate:"required,oneof=owner editor viewer"`
+	Role        string    `gorm:"size:50;not null;default:'viewer'" json:"role" validate:"required,oneof=owner editor viewer custom"`
+	// BoardRoleID names the custom role when Role is "custom".
+	BoardRoleID *uuid.UUID `gorm:"type:uuid;index" json:"board_role_id"`
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	
 	User       User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	TaskBoard  TaskBoard `gorm:"foreignKey:TaskBoardID" json:"task_board,omitempty"`
+	BoardRole  *BoardRole `gorm:"foreignKey:BoardRoleID;constraint:OnDelete:RESTRICT" json:"board_role,omitempty"`
+
+	// Grants lists where the user's access comes from when it was resolved
+	// across direct, team and workspace grants. Role is then the most
+	// privileged of them and Permissions joins their custom roles.
+	Grants      []BoardGrant `gorm:"-" json:"grants,omitempty"`
+	Permissions []string     `gorm:"-" json:"permissions,omitempty"`
+
+	// Status is "active" for members in the collaborator list, or the status
+	// of an invitation listed alongside them, in which case Invitation is set
+	// and User only carries the invited email.
+	Status     string      `gorm:"-" json:"status,omitempty"`
+	Invitation *Invitation `gorm:"-" json:"invitation,omitempty"`
+}
+
+// CustomPermissions returns the permissions of the member's custom roles, or
+// nil when they only hold preset roles. Either the access was resolved or
+// BoardRole must be preloaded.
+func (userTaskBoard *UserTaskBoard) CustomPermissions() []string {
+	if userTaskBoard.Grants != nil {
+		return userTaskBoard.Permissions
+	}
+	if userTaskBoard.BoardRole == nil {
+		return nil
+	}
+	return userTaskBoard.BoardRole.Permissions
 }
 
 type Task struct {
@@ -37,8 +101,19 @@ type Task struct {
 	Priority    string    `gorm:"size:50;not null;default:'medium'" json:"priority" validate:"required,oneof=low medium high"`
 	StartDate   time.Time `gorm:"not null" json:"start_date"`
 	EndDate     time.Time `gorm:"not null" json:"end_date"`
+	EstimateMinutes *int  `json:"estimate_minutes"`
+	AssigneeID  *uuid.UUID `gorm:"type:uuid;index" json:"assignee_id"`
+	// CreatedByID is nil for tasks created before it was recorded.
+	CreatedByID *uuid.UUID `gorm:"type:uuid;index" json:"created_by_id"`
+	// ArchivedAt hides the task from its board and makes it read-only.
+	ArchivedAt  *time.Time     `gorm:"index" json:"archived_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	
 	TaskBoard   TaskBoard `gorm:"foreignKey:TaskBoardID" json:"task_board,omitempty"`
+	CustomFieldValues []TaskCustomFieldValue `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"custom_field_values,omitempty"`
+	Assignee    *User     `gorm:"foreignKey:AssigneeID;constraint:OnDelete:SET NULL" json:"assignee,omitempty"`
+	CreatedBy   *User     `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL" json:"-"`
+	Labels      []Label   `gorm:"many2many:task_labels;constraint:OnDelete:CASCADE" json:"labels,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Task activity kinds.
+const (
+	ActivityCreated   = "created"
+	ActivityChanged   = "changed"
+	ActivityCommented = "commented"
+)
+
+// TaskActivity is one entry of a task's timeline: the task being created,
+// one of its fields changing, or a comment left on it. Unlike the audit log
+// it is meant to be read by the people working on the task.
+type TaskActivity struct {
+	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskID uuid.UUID `gorm:"type:uuid;not null;index:idx_task_activities_task_time" json:"task_id"`
+	// TaskBoardID is the board the task was on when the activity happened.
+	TaskBoardID uuid.UUID  `gorm:"type:uuid;not null;index:idx_task_activities_board_time" json:"task_board_id"`
+	ActorID     *uuid.UUID `gorm:"type:uuid;index" json:"actor_id"`
+	Kind        string     `gorm:"size:20;not null" json:"kind"`
+	// Field, OldValue and NewValue describe a change; values are kept as
+	// text and are nil when the field was empty.
+	Field     string     `gorm:"size:50" json:"field,omitempty"`
+	OldValue  *string    `gorm:"type:text" json:"old_value"`
+	NewValue  *string    `gorm:"type:text" json:"new_value"`
+	CommentID *uuid.UUID `gorm:"type:uuid;index" json:"comment_id,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index:idx_task_activities_task_time;index:idx_task_activities_board_time" json:"created_at"`
+
+	Task    *Task    `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"task,omitempty"`
+	Actor   *User    `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL" json:"actor,omitempty"`
+	Comment *Comment `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"comment,omitempty"`
+
+	// Diff spells out a description change word by word.
+	Diff []TextDiff `gorm:"-" json:"diff,omitempty"`
+}
+
+// TextDiff is a run of text kept, inserted or deleted by an edit.
+type TextDiff struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskRecurrence describes a recurring series. TaskID always points at the
+// newest instance; the scheduler creates the following one at NextRunAt or as
+// soon as the current instance is done. NextRunAt is nil once the series ends.
+// Occurrences counts the instances created, and the occurrences skipped
+// because they were already past when the series was scheduled.
+type TaskRecurrence struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"task_id"`
+	Rule        string     `gorm:"size:255;not null" json:"rule"`
+	SeriesStart time.Time  `gorm:"not null" json:"series_start"`
+	Occurrences int        `gorm:"not null;default:1" json:"occurrences"`
+	NextRunAt   *time.Time `gorm:"index" json:"next_run_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Task Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// TaskSearchHit is a task matched by a search, with its board and how well
+// it matched the free text. Rank is 0 when the query had no text.
+type TaskSearchHit struct {
+	Task
+	Rank float64 `json:"rank"`
+}
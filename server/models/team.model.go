@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Team is a named group of workspace members that can be granted a role on
+// the workspace's boards as a unit.
+type Team struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_teams_workspace_name" json:"workspace_id"`
+	Name        string    `gorm:"size:100;not null;uniqueIndex:idx_teams_workspace_name" json:"name" validate:"required,max=100"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Members   []TeamMember `gorm:"foreignKey:TeamID" json:"members,omitempty"`
+	Workspace Workspace    `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+type TeamMember struct {
+	TeamID    uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"team_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Team Team `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TaskBoardTeam grants every member of a team a role on a board, with the
+// same roles a collaborator can hold.
+type TaskBoardTeam struct {
+	TaskBoardID uuid.UUID  `gorm:"type:uuid;not null;primaryKey" json:"task_board_id"`
+	TeamID      uuid.UUID  `gorm:"type:uuid;not null;primaryKey;index" json:"team_id"`
+	Role        string     `gorm:"size:50;not null;default:'viewer'" json:"role" validate:"required,oneof=owner editor viewer custom"`
+	BoardRoleID *uuid.UUID `gorm:"type:uuid;index" json:"board_role_id"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Team      Team       `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"team"`
+	TaskBoard TaskBoard  `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+	BoardRole *BoardRole `gorm:"foreignKey:BoardRoleID;constraint:OnDelete:RESTRICT" json:"board_role,omitempty"`
+}
+
+// Sources of a BoardGrant.
+const (
+	GrantDirect    = "direct"
+	GrantTeam      = "team"
+	GrantWorkspace = "workspace"
+)
+
+// BoardGrant is one of the ways a user reaches a board: their own membership,
+// a team they are on, or their workspace role.
+type BoardGrant struct {
+	Source      string     `json:"source"`
+	TeamID      *uuid.UUID `json:"team_id,omitempty"`
+	TeamName    string     `json:"team_name,omitempty"`
+	Role        string     `json:"role"`
+	BoardRoleID *uuid.UUID `json:"board_role_id,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntry is time a user spent on a task. Entries logged by hand carry only
+// a duration; timer entries also carry StartedAt, and are running until
+// EndedAt is set. TaskBoardID is the board the task was on when the time was
+// logged, so moving a task does not re-bill earlier work to its new board.
+type TimeEntry struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"task_id"`
+	TaskBoardID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"task_board_id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_time_entries_running,where:started_at IS NOT NULL AND ended_at IS NULL" json:"user_id"`
+	Date            time.Time  `gorm:"type:date;not null;index" json:"date"`
+	DurationSeconds int64      `gorm:"not null;default:0" json:"duration_seconds"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	Note            string     `gorm:"size:1000" json:"note" validate:"max=1000"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Task      Task      `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"-"`
+	TaskBoard TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// Running reports whether the entry is a timer that has not been stopped.
+func (e TimeEntry) Running() bool {
+	return e.StartedAt != nil && e.EndedAt == nil
+}
+
+// TimeSummaryRow is one group of a time report.
+type TimeSummaryRow struct {
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Seconds int64  `json:"seconds"`
+	Entries int64  `json:"entries"`
+}
+
+// TimeSummary totals the time entries in a scope and breaks them down by one dimension.
+type TimeSummary struct {
+	TotalSeconds    int64            `json:"total_seconds"`
+	EstimateMinutes *int             `json:"estimate_minutes,omitempty"`
+	GroupBy         string           `json:"group_by"`
+	Groups          []TimeSummaryRow `json:"groups"`
+}
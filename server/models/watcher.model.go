@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskWatcher records a user following a task. Creating, being assigned or
+// commenting on a task makes a user watch it; with Watching false the user
+// opted out, which keeps those from making them watch it again and hides
+// the task from a board they watch.
+type TaskWatcher struct {
+	TaskID    uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"task_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;primaryKey;index" json:"user_id"`
+	Watching  bool      `gorm:"not null" json:"watching"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Task Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"-"`
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// TaskBoardWatcher records a user following every task on a board.
+type TaskBoardWatcher struct {
+	TaskBoardID uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"task_board_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;primaryKey;index" json:"user_id"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	TaskBoard TaskBoard `gorm:"foreignKey:TaskBoardID;constraint:OnDelete:CASCADE" json:"-"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Workspace groups task boards and the people who work on them. Every user
+// also gets a personal workspace, which their boards land in by default.
+type Workspace struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name string    `gorm:"size:255;not null" json:"name" validate:"required,max=255"`
+	// PersonalOwnerID is set on a user's personal workspace.
+	PersonalOwnerID *uuid.UUID        `gorm:"type:uuid;uniqueIndex" json:"personal_owner_id"`
+	Settings        WorkspaceSettings `gorm:"embedded;embeddedPrefix:setting_" json:"settings"`
+	CreatedAt       time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Role is the requesting user's role, filled in by workspace listings.
+	Role string `gorm:"->;-:migration" json:"role,omitempty"`
+}
+
+// WorkspaceSettings are the workspace-wide defaults admins can change.
+type WorkspaceSettings struct {
+	// DefaultBoardRole is what members get on the workspace's boards they have
+	// not been added to: editor, viewer or none.
+	DefaultBoardRole string `gorm:"size:50;not null;default:'viewer'" json:"default_board_role"`
+	// RestrictBoardCreation keeps members from creating boards; admins always can.
+	RestrictBoardCreation bool `gorm:"not null;default:false" json:"restrict_board_creation"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"workspace_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;primaryKey;index" json:"user_id"`
+	Role        string    `gorm:"size:50;not null;default:'member'" json:"role" validate:"required,oneof=admin member guest"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,62 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// cursor is the decoded form of the opaque token handed to clients. Sort
+// ties it to the sort it was issued for.
+type cursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+func encodeCursor(sort string, values []interface{}) (string, error) {
+	data, err := json.Marshal(cursor{Sort: sort, Values: values})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(token string, sort string, keys int) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errorf("invalid cursor")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded cursor
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, errorf("invalid cursor")
+	}
+	if decoded.Sort != sort {
+		return nil, errorf("cursor was issued for sort %s; start again without a cursor to change the sort", decoded.Sort)
+	}
+	if len(decoded.Values) != keys {
+		return nil, errorf("invalid cursor")
+	}
+
+	for i, value := range decoded.Values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if !strings.ContainsAny(number.String(), ".eE") {
+			if integer, err := number.Int64(); err == nil {
+				decoded.Values[i] = integer
+				continue
+			}
+		}
+		float, err := number.Float64()
+		if err != nil {
+			return nil, errorf("invalid cursor")
+		}
+		decoded.Values[i] = float
+	}
+	return decoded.Values, nil
+}
@@ -0,0 +1,69 @@
+package pagination
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Select trims each row to the JSON fields asked for, id always included.
+func Select[T any](rows []T, fields []string) ([]map[string]json.RawMessage, error) {
+	selected := make([]map[string]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+
+		kept := make(map[string]json.RawMessage, len(fields)+1)
+		if id, ok := all["id"]; ok {
+			kept["id"] = id
+		}
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				kept[field] = value
+			}
+		}
+		selected = append(selected, kept)
+	}
+	return selected, nil
+}
+
+// jsonFields lists the JSON field names of a struct type, those of embedded
+// structs included.
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			for embedded := range jsonFields(field.Type) {
+				fields[embedded] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
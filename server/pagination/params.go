@@ -0,0 +1,199 @@
+// Package pagination pages list endpoints with keyset cursors. A request
+// names a limit, a sort over one or more fields and, after the first page,
+// the cursor the previous page returned. Each page starts right after the
+// last row of the one before, so paging stays stable while rows are added
+// or removed, and a fields= list trims every row to the fields asked for.
+package pagination
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+	// maxSortKeys bounds the sort fields a request may name.
+	maxSortKeys = 4
+)
+
+// Key is a field a list can be sorted by.
+type Key struct {
+	// Column is the SQL expression the key sorts on. It is written by the
+	// caller, never taken from the request; Args bind its placeholders.
+	Column string
+	Args   []interface{}
+	// Nullable keys sort their NULLs last in either direction.
+	Nullable bool
+	// Value reads the key from a row. By default it is the row's JSON field
+	// of the same name as the key.
+	Value func(row interface{}) interface{}
+}
+
+// Spec describes how one list pages.
+type Spec struct {
+	// Keys are the sortable fields by the name the request uses.
+	Keys map[string]Key
+	// Default is the sort of requests naming none, such as "-created_at".
+	Default string
+	// Unique names a key no two rows share. It ends every sort so that rows
+	// never tie and a cursor always points at a single row.
+	Unique string
+	// Model is a row of the list; fields= may name any of its JSON fields.
+	Model interface{}
+}
+
+// Order is one field of a sort.
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Query holds the raw paging parameters of a request.
+type Query struct {
+	Limit  string
+	Cursor string
+	Sort   string
+	Fields string
+}
+
+// Params is a validated page request.
+type Params struct {
+	Limit int
+	Sort  []Order
+	// After holds the sort values of the row the page starts after, or nil
+	// for the first page.
+	After []interface{}
+	// Fields lists the JSON fields to keep on each row, or nil for all.
+	Fields []string
+
+	spec Spec
+}
+
+// Error reports paging parameters the list does not accept.
+type Error struct {
+	message string
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func errorf(format string, args ...interface{}) *Error {
+	return &Error{message: fmt.Sprintf(format, args...)}
+}
+
+// Parse validates a request's paging parameters against spec. It returns an
+// *Error for parameters the list does not accept.
+func Parse(query Query, spec Spec) (*Params, error) {
+	params := &Params{Limit: DefaultLimit, spec: spec}
+
+	if query.Limit != "" {
+		limit, err := strconv.Atoi(query.Limit)
+		if err != nil || limit < 1 {
+			return nil, errorf("limit must be a positive number")
+		}
+		if limit > MaxLimit {
+			limit = MaxLimit
+		}
+		params.Limit = limit
+	}
+
+	sort := query.Sort
+	if sort == "" {
+		sort = spec.Default
+	}
+	orders, err := parseSort(sort, spec)
+	if err != nil {
+		return nil, err
+	}
+	params.Sort = orders
+
+	if query.Cursor != "" {
+		after, err := decodeCursor(query.Cursor, params.SortString(), len(orders))
+		if err != nil {
+			return nil, err
+		}
+		params.After = after
+	}
+
+	if query.Fields != "" {
+		fields, err := parseFields(query.Fields, spec.Model)
+		if err != nil {
+			return nil, err
+		}
+		params.Fields = fields
+	}
+	return params, nil
+}
+
+// All returns params that fetch every row in spec's default order, for
+// callers that need the whole list rather than a page of it.
+func All(spec Spec) *Params {
+	orders, err := parseSort(spec.Default, spec)
+	if err != nil {
+		panic(err)
+	}
+	return &Params{Limit: -1, Sort: orders, spec: spec}
+}
+
+// SortString is the sort in request form, the unique key included.
+func (params *Params) SortString() string {
+	fields := make([]string, 0, len(params.Sort))
+	for _, order := range params.Sort {
+		if order.Desc {
+			fields = append(fields, "-"+order.Field)
+		} else {
+			fields = append(fields, order.Field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+func parseSort(sort string, spec Spec) ([]Order, error) {
+	var orders []Order
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		order := Order{Field: field}
+		if name, ok := strings.CutPrefix(field, "-"); ok {
+			order = Order{Field: name, Desc: true}
+		}
+		if _, ok := spec.Keys[order.Field]; !ok {
+			return nil, errorf("cannot sort by %s", order.Field)
+		}
+		if seen[order.Field] {
+			return nil, errorf("%s appears twice in sort", order.Field)
+		}
+		seen[order.Field] = true
+		orders = append(orders, order)
+	}
+	if len(orders) > maxSortKeys {
+		return nil, errorf("sort takes at most %d fields", maxSortKeys)
+	}
+	if !seen[spec.Unique] {
+		orders = append(orders, Order{Field: spec.Unique})
+	}
+	return orders, nil
+}
+
+func parseFields(raw string, model interface{}) ([]string, error) {
+	known := jsonFields(reflect.TypeOf(model))
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !known[field] {
+			return nil, errorf("unknown field %s", field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
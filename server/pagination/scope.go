@@ -0,0 +1,149 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Page describes the page a list response holds. NextCursor fetches the
+// page after it and is empty on the last page. Total is only set by lists
+// that count their matches.
+type Page struct {
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// Scope orders the query by the sort, starts it after the cursor and fetches
+// one row past the limit so Cut can tell whether another page follows. It
+// sets the whole ORDER BY, so the query must not order by anything else.
+func (params *Params) Scope(db *gorm.DB) *gorm.DB {
+	if params.After != nil {
+		condition, args := params.afterCursor()
+		db = db.Where(condition, args...)
+	}
+
+	var orders []string
+	var args []interface{}
+	for _, order := range params.Sort {
+		key := params.spec.Keys[order.Field]
+		sql := key.Column + " ASC"
+		if order.Desc {
+			sql = key.Column + " DESC"
+		}
+		if key.Nullable {
+			sql += " NULLS LAST"
+		}
+		orders = append(orders, sql)
+		args = append(args, key.Args...)
+	}
+	db = db.Order(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(orders, ", "), Vars: args, WithoutParentheses: true}})
+
+	if params.Limit < 0 {
+		return db
+	}
+	return db.Limit(params.Limit + 1)
+}
+
+// afterCursor matches the rows that sort after the cursor: those past it on
+// the first key, or level with it on the first keys and past it on the next.
+func (params *Params) afterCursor() (string, []interface{}) {
+	var branches []string
+	var args []interface{}
+	var level []string
+	var levelArgs []interface{}
+
+	for i, order := range params.Sort {
+		key := params.spec.Keys[order.Field]
+		value := params.After[i]
+
+		// NULLs sort last, so nothing is past a NULL on this key
+		if value != nil {
+			comparison := ">"
+			if order.Desc {
+				comparison = "<"
+			}
+			past := key.Column + " " + comparison + " ?"
+			pastArgs := append(append([]interface{}{}, key.Args...), value)
+			if key.Nullable {
+				past = "(" + past + " OR " + key.Column + " IS NULL)"
+				pastArgs = append(pastArgs, key.Args...)
+			}
+			branch := append(append([]string{}, level...), past)
+			branches = append(branches, "("+strings.Join(branch, " AND ")+")")
+			args = append(append(args, levelArgs...), pastArgs...)
+		}
+
+		if value == nil {
+			level = append(level, key.Column+" IS NULL")
+			levelArgs = append(levelArgs, key.Args...)
+		} else {
+			level = append(level, key.Column+" = ?")
+			levelArgs = append(append(levelArgs, key.Args...), value)
+		}
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", args
+}
+
+// Cut trims rows fetched with Scope to the page and describes it.
+func Cut[T any](params *Params, rows []T) ([]T, *Page, error) {
+	page := &Page{Limit: params.Limit, Sort: params.SortString()}
+	if params.Limit < 0 || len(rows) <= params.Limit {
+		return rows, page, nil
+	}
+
+	rows = rows[:params.Limit]
+	values, err := params.values(rows[len(rows)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err := encodeCursor(page.Sort, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	page.NextCursor = next
+	page.HasMore = true
+	return rows, page, nil
+}
+
+// values reads the sort keys of a row for its cursor.
+func (params *Params) values(row interface{}) ([]interface{}, error) {
+	var fields map[string]json.RawMessage
+	values := make([]interface{}, 0, len(params.Sort))
+	for _, order := range params.Sort {
+		key := params.spec.Keys[order.Field]
+		if key.Value != nil {
+			values = append(values, key.Value(row))
+			continue
+		}
+
+		if fields == nil {
+			data, err := json.Marshal(row)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &fields); err != nil {
+				return nil, err
+			}
+		}
+		raw, ok := fields[order.Field]
+		if !ok {
+			return nil, fmt.Errorf("pagination: row has no %s field", order.Field)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,314 @@
+// Package policy decides what a user may do on a task board. Rules grant
+// actions to preset roles or to holders of catalogue permissions, optionally
+// only when a condition on the resource holds; anything no rule grants is
+// denied. The engine has no storage dependencies,
+// so callers look up the subject's role and the resource's attributes first.
+package policy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Preset roles a user can hold on a task board, from most to least
+// privileged. Members with RoleCustom hold a board's custom role instead.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+	RoleCustom = "custom"
+)
+
+var Roles = []string{RoleOwner, RoleEditor, RoleViewer}
+
+// MaxRole returns the most privileged of roles. Preset roles outrank custom
+// ones, whose permissions callers combine separately.
+func MaxRole(roles ...string) string {
+	for _, preset := range Roles {
+		if contains(roles, preset) {
+			return preset
+		}
+	}
+	if contains(roles, RoleCustom) {
+		return RoleCustom
+	}
+	return ""
+}
+
+// Permission is an entry of the catalogue custom roles are built from.
+type Permission string
+
+const (
+	PermView                Permission = "view"
+	PermComment             Permission = "comment"
+	PermCreateTask          Permission = "create_task"
+	PermEditAnyTask         Permission = "edit_any_task"
+	PermEditOwnTask         Permission = "edit_own_task"
+	PermDelete              Permission = "delete"
+	PermManageCollaborators Permission = "manage_collaborators"
+	PermManageSettings      Permission = "manage_settings"
+)
+
+// Catalogue lists every permission a custom role may pick from.
+var Catalogue = []Permission{
+	PermView, PermComment, PermCreateTask, PermEditAnyTask, PermEditOwnTask,
+	PermDelete, PermManageCollaborators, PermManageSettings,
+}
+
+// Presets are the permissions the preset roles hold. Owners additionally get
+// every action by role, including the ones no permission grants.
+var Presets = map[string][]Permission{
+	RoleOwner:  Catalogue,
+	RoleEditor: {PermView, PermComment, PermCreateTask, PermEditAnyTask, PermEditOwnTask},
+	RoleViewer: {PermView},
+}
+
+type Action string
+
+const (
+	BoardView       Action = "board.view"
+	BoardUpdate     Action = "board.update"
+	BoardDelete     Action = "board.delete"
+	BoardArchive    Action = "board.archive"
+	BoardClone      Action = "board.clone"
+	BoardSettings   Action = "board.settings"
+	BoardShare      Action = "board.share"
+	ViewShare       Action = "view.share"
+	RoleManage      Action = "role.manage"
+	MemberManage    Action = "member.manage"
+	MemberRemove    Action = "member.remove"
+	LabelManage     Action = "label.manage"
+	TaskView        Action = "task.view"
+	TaskCreate      Action = "task.create"
+	TaskUpdate      Action = "task.update"
+	TaskArchive     Action = "task.archive"
+	TaskDelete      Action = "task.delete"
+	TaskOverrideWIP Action = "task.override_wip"
+	CommentCreate   Action = "comment.create"
+	CommentDelete   Action = "comment.delete"
+	TimeLog         Action = "time.log"
+	TimeDelete      Action = "time.delete"
+	AuditView       Action = "audit.view"
+)
+
+// Actions lists every action the engine knows, in the order permission
+// listings report them.
+var Actions = []Action{
+	BoardView, BoardUpdate, BoardDelete, BoardArchive, BoardClone, BoardSettings,
+	BoardShare, ViewShare, RoleManage, MemberManage, MemberRemove, LabelManage,
+	TaskView, TaskCreate, TaskUpdate, TaskArchive, TaskDelete, TaskOverrideWIP,
+	CommentCreate, CommentDelete, TimeLog, TimeDelete, AuditView,
+}
+
+// Subject is the user asking. Role is empty when they are not on the board.
+type Subject struct {
+	UserID      uuid.UUID
+	Role        string
+	Permissions []Permission
+}
+
+// NewSubject describes a board member. Preset roles hold their preset's
+// permissions and custom roles the ones picked for them; a member granted
+// both, through a team for instance, holds the union.
+func NewSubject(userID uuid.UUID, role string, custom []string) Subject {
+	subject := Subject{UserID: userID, Role: role}
+	held := make(map[Permission]bool)
+	for _, permission := range Presets[role] {
+		held[permission] = true
+	}
+	for _, permission := range custom {
+		held[Permission(permission)] = true
+	}
+	for _, permission := range Catalogue {
+		if held[permission] {
+			subject.Permissions = append(subject.Permissions, permission)
+		}
+	}
+	return subject
+}
+
+// Resource is what the action targets. OwnerID is the user the resource
+// belongs to, when it has one: a task's creator, a time entry's author or a
+// collaborator's user.
+type Resource struct {
+	TaskBoardID uuid.UUID
+	OwnerID     *uuid.UUID
+}
+
+// Condition restricts a rule to resources with certain attributes.
+type Condition func(subject Subject, resource Resource) bool
+
+var conditions = map[string]Condition{
+	// own matches resources that belong to the subject.
+	"own": func(subject Subject, resource Resource) bool {
+		return resource.OwnerID != nil && *resource.OwnerID == subject.UserID
+	},
+}
+
+// Rule grants Actions to members holding one of Roles or any of
+// Permissions. An action ending in ".*" matches every action with that prefix
+// and "*" matches all of them.
+type Rule struct {
+	Actions     []string `json:"actions"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Condition   string   `json:"condition,omitempty"`
+}
+
+type Engine struct {
+	rules []Rule
+}
+
+//go:embed default.json
+var defaultRules []byte
+
+// New validates the rules and builds an engine from them.
+func New(rules []Rule) (*Engine, error) {
+	for i, rule := range rules {
+		if len(rule.Actions) == 0 || len(rule.Roles)+len(rule.Permissions) == 0 {
+			return nil, fmt.Errorf("rule %d: actions and roles or permissions are required", i)
+		}
+		for _, role := range rule.Roles {
+			if !isRole(role) {
+				return nil, fmt.Errorf("rule %d: unknown role %q", i, role)
+			}
+		}
+		for _, permission := range rule.Permissions {
+			if !IsPermission(permission) {
+				return nil, fmt.Errorf("rule %d: unknown permission %q", i, permission)
+			}
+		}
+		for _, action := range rule.Actions {
+			if !isAction(action) {
+				return nil, fmt.Errorf("rule %d: unknown action %q", i, action)
+			}
+		}
+		if _, ok := conditions[rule.Condition]; rule.Condition != "" && !ok {
+			return nil, fmt.Errorf("rule %d: unknown condition %q", i, rule.Condition)
+		}
+	}
+	return &Engine{rules: rules}, nil
+}
+
+// Parse builds an engine from a JSON document of the form {"rules": [...]}.
+func Parse(data []byte) (*Engine, error) {
+	var document struct {
+		Rules []Rule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	return New(document.Rules)
+}
+
+// Default returns the engine for the built-in rules.
+func Default() *Engine {
+	engine, err := Parse(defaultRules)
+	if err != nil {
+		panic(err)
+	}
+	return engine
+}
+
+// NewEngineFromEnv loads the rules from the file named by POLICY_FILE, or
+// falls back to the built-in rules when it is unset.
+func NewEngineFromEnv() (*Engine, error) {
+	path := os.Getenv("POLICY_FILE")
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Allows reports whether any rule grants the action to the subject on the
+// resource.
+func (engine *Engine) Allows(subject Subject, action Action, resource Resource) bool {
+	if subject.Role == "" {
+		return false
+	}
+	for _, rule := range engine.rules {
+		if !matchesAction(rule.Actions, action) {
+			continue
+		}
+		if !contains(rule.Roles, subject.Role) && !holdsAny(subject.Permissions, rule.Permissions) {
+			continue
+		}
+		if rule.Condition == "" || conditions[rule.Condition](subject, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed lists the actions the subject may perform on the resource.
+func (engine *Engine) Allowed(subject Subject, resource Resource) []Action {
+	allowed := []Action{}
+	for _, action := range Actions {
+		if engine.Allows(subject, action, resource) {
+			allowed = append(allowed, action)
+		}
+	}
+	return allowed
+}
+
+func matchesAction(patterns []string, action Action) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == string(action) {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(string(action), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAction(pattern string) bool {
+	for _, action := range Actions {
+		if matchesAction([]string{pattern}, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPermission reports whether permission is in the catalogue.
+func IsPermission(permission string) bool {
+	for _, p := range Catalogue {
+		if string(p) == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func holdsAny(held []Permission, wanted []string) bool {
+	for _, permission := range held {
+		if contains(wanted, string(permission)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRole(role string) bool {
+	return contains(Roles, role)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
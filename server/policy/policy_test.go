@@ -0,0 +1,154 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{"invalid JSON", `{"rules": [`, "invalid policy"},
+		{"no actions", `{"rules": [{"roles": ["owner"]}]}`, "actions and roles or permissions are required"},
+		{"no roles or permissions", `{"rules": [{"actions": ["task.view"]}]}`, "actions and roles or permissions are required"},
+		{"unknown role", `{"rules": [{"actions": ["task.view"], "roles": ["admin"]}]}`, `unknown role "admin"`},
+		{"custom role", `{"rules": [{"actions": ["task.view"], "roles": ["custom"]}]}`, `unknown role "custom"`},
+		{"unknown permission", `{"rules": [{"actions": ["task.view"], "permissions": ["fly"]}]}`, `unknown permission "fly"`},
+		{"unknown action", `{"rules": [{"actions": ["task.fly"], "roles": ["owner"]}]}`, `unknown action "task.fly"`},
+		{"unknown action prefix", `{"rules": [{"actions": ["ship.*"], "roles": ["owner"]}]}`, `unknown action "ship.*"`},
+		{"unknown condition", `{"rules": [{"actions": ["task.view"], "roles": ["viewer"], "condition": "mine"}]}`, `unknown condition "mine"`},
+		{"error names the rule", `{"rules": [{"actions": ["task.view"], "roles": ["viewer"]}, {"actions": ["task.view"], "roles": ["guest"]}]}`, "rule 1:"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.document))
+			if err == nil {
+				t.Fatalf("Parse succeeded, want an error containing %q", test.want)
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Parse error = %q, want it to contain %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestParseAcceptsWildcards(t *testing.T) {
+	engine, err := Parse([]byte(`{"rules": [{"actions": ["task.*"], "roles": ["viewer"], "condition": "own"}]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	userID := uuid.New()
+	subject := NewSubject(userID, RoleViewer, nil)
+	own := Resource{TaskBoardID: uuid.New(), OwnerID: &userID}
+	if !engine.Allows(subject, TaskDelete, own) {
+		t.Error("task.* does not grant task.delete on an own task")
+	}
+	if engine.Allows(subject, TaskDelete, Resource{TaskBoardID: own.TaskBoardID}) {
+		t.Error("the own condition holds for a task without an owner")
+	}
+	if engine.Allows(subject, BoardView, own) {
+		t.Error("task.* grants board.view")
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	engine := Default()
+	userID, otherID := uuid.New(), uuid.New()
+	taskBoardID := uuid.New()
+	own := Resource{TaskBoardID: taskBoardID, OwnerID: &userID}
+	others := Resource{TaskBoardID: taskBoardID, OwnerID: &otherID}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		resource Resource
+		want     []Action
+	}{
+		{
+			name:     "owner is granted everything",
+			subject:  NewSubject(userID, RoleOwner, nil),
+			resource: others,
+			want:     Actions,
+		},
+		{
+			name:     "editor edits any task but deletes only their own",
+			subject:  NewSubject(userID, RoleEditor, nil),
+			resource: others,
+			want:     []Action{BoardView, BoardClone, ViewShare, LabelManage, TaskView, TaskCreate, TaskUpdate, TaskArchive, CommentCreate, TimeLog},
+		},
+		{
+			name:     "editor deletes their own task",
+			subject:  NewSubject(userID, RoleEditor, nil),
+			resource: own,
+			want:     []Action{BoardView, BoardClone, ViewShare, MemberRemove, LabelManage, TaskView, TaskCreate, TaskUpdate, TaskArchive, TaskDelete, CommentCreate, CommentDelete, TimeLog, TimeDelete},
+		},
+		{
+			name:     "viewer is read-only",
+			subject:  NewSubject(userID, RoleViewer, nil),
+			resource: others,
+			want:     []Action{BoardView, BoardClone, TaskView},
+		},
+		{
+			name:     "viewer may only remove what is their own",
+			subject:  NewSubject(userID, RoleViewer, nil),
+			resource: own,
+			want:     []Action{BoardView, BoardClone, MemberRemove, TaskView, CommentDelete, TimeDelete},
+		},
+		{
+			name:     "delete permission deletes any task and comment",
+			subject:  NewSubject(userID, RoleCustom, []string{string(PermView), string(PermDelete)}),
+			resource: others,
+			want:     []Action{BoardView, BoardClone, TaskView, TaskDelete, CommentDelete},
+		},
+		{
+			name:     "custom role without permissions gets nothing",
+			subject:  NewSubject(userID, RoleCustom, nil),
+			resource: own,
+			want:     []Action{},
+		},
+		{
+			name:     "outsider gets nothing",
+			subject:  Subject{UserID: userID},
+			resource: own,
+			want:     []Action{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := engine.Allowed(test.subject, test.resource); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Allowed = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewSubjectCombinesPresetAndCustomPermissions(t *testing.T) {
+	subject := NewSubject(uuid.New(), RoleViewer, []string{string(PermDelete), string(PermView)})
+	want := []Permission{PermView, PermDelete}
+	if !reflect.DeepEqual(subject.Permissions, want) {
+		t.Errorf("Permissions = %v, want %v", subject.Permissions, want)
+	}
+}
+
+func TestMaxRole(t *testing.T) {
+	tests := []struct {
+		roles []string
+		want  string
+	}{
+		{[]string{RoleViewer, RoleOwner, RoleEditor}, RoleOwner},
+		{[]string{RoleCustom, RoleViewer}, RoleViewer},
+		{[]string{RoleCustom}, RoleCustom},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		if got := MaxRole(test.roles...); got != test.want {
+			t.Errorf("MaxRole(%v) = %q, want %q", test.roles, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package policy
+
+// Roles a user can hold in a workspace, from most to least privileged.
+const (
+	WorkspaceAdmin  = "admin"
+	WorkspaceMember = "member"
+	WorkspaceGuest  = "guest"
+)
+
+var WorkspaceRoles = []string{WorkspaceAdmin, WorkspaceMember, WorkspaceGuest}
+
+// NoBoardRole is the workspace default board role that grants members
+// nothing on boards they have not been added to.
+const NoBoardRole = "none"
+
+// WorkspaceBoardRole returns the board role a workspace role grants on every
+// board of the workspace, or "" when it grants none. Admins act as owners,
+// members get the workspace's default board role and guests only reach the
+// boards they were added to.
+func WorkspaceBoardRole(workspaceRole string, defaultBoardRole string) string {
+	switch workspaceRole {
+	case WorkspaceAdmin:
+		return RoleOwner
+	case WorkspaceMember:
+		if defaultBoardRole == RoleEditor || defaultBoardRole == RoleViewer {
+			return defaultBoardRole
+		}
+	}
+	return ""
+}
+
+// WorkspaceRoleAtLeast reports whether role is minimum or a more privileged
+// workspace role.
+func WorkspaceRoleAtLeast(role string, minimum string) bool {
+	for _, r := range WorkspaceRoles {
+		if r == role {
+			return true
+		}
+		if r == minimum {
+			return false
+		}
+	}
+	return false
+}
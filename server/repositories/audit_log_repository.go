@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	WithTx(tx *gorm.DB) AuditLogRepository
+	Create(entry *models.AuditLog) error
+	FindByTaskBoardID(taskBoardID uuid.UUID, filter AuditLogFilter, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error)
+	FindByWorkspaceID(workspaceID uuid.UUID, filter AuditLogFilter, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error)
+	FindByActorID(actorID uuid.UUID, filter AuditLogFilter, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error)
+}
+
+type AuditLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepositoryImpl {
+	return &AuditLogRepositoryImpl{db: db}
+}
+
+func (repo *AuditLogRepositoryImpl) WithTx(tx *gorm.DB) AuditLogRepository {
+	return &AuditLogRepositoryImpl{db: tx}
+}
+
+// AuditLogFilter narrows an audit log listing. Zero fields match everything.
+type AuditLogFilter struct {
+	ActorID     *uuid.UUID
+	Actions     []string
+	EntityType  string
+	EntityID    *uuid.UUID
+	TaskBoardID *uuid.UUID
+	From        *time.Time
+	To          *time.Time
+}
+
+// AuditLogPages is how audit log listings page, most recent first unless
+// asked otherwise.
+var AuditLogPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":          {Column: "audit_logs.id"},
+		"action":      {Column: "audit_logs.action"},
+		"entity_type": {Column: "audit_logs.entity_type"},
+		"created_at":  {Column: "audit_logs.created_at"},
+	},
+	Default: "-created_at",
+	Unique:  "id",
+	Model:   models.AuditLog{},
+}
+
+func (repo *AuditLogRepositoryImpl) Create(entry *models.AuditLog) error {
+	return repo.db.Create(entry).Error
+}
+
+// FindByTaskBoardID returns the entries about the board and everything on it.
+func (repo *AuditLogRepositoryImpl) FindByTaskBoardID(taskBoardID uuid.UUID, filter AuditLogFilter, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+	query := repo.db.Where("audit_logs.task_board_id = ?", taskBoardID)
+	return findAuditLogs(query, filter, params)
+}
+
+// FindByWorkspaceID returns the entries about the workspace's boards,
+// including boards that have since been deleted.
+func (repo *AuditLogRepositoryImpl) FindByWorkspaceID(workspaceID uuid.UUID, filter AuditLogFilter, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+	boards := repo.db.Unscoped().Model(&models.TaskBoard{}).Select("id").Where("workspace_id = ?", workspaceID)
+	query := repo.db.Where("audit_logs.task_board_id IN (?)", boards)
+	return findAuditLogs(query, filter, params)
+}
+
+// FindByActorID returns the entries about what the user did, wherever they
+// did it.
+func (repo *AuditLogRepositoryImpl) FindByActorID(actorID uuid.UUID, filter AuditLogFilter, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+	query := repo.db.Where("audit_logs.actor_id = ?", actorID)
+	return findAuditLogs(query, filter, params)
+}
+
+func findAuditLogs(query *gorm.DB, filter AuditLogFilter, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+	if filter.ActorID != nil {
+		query = query.Where("audit_logs.actor_id = ?", *filter.ActorID)
+	}
+	if len(filter.Actions) > 0 {
+		query = query.Where("audit_logs.action IN ?", filter.Actions)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("audit_logs.entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("audit_logs.entity_id = ?", *filter.EntityID)
+	}
+	if filter.TaskBoardID != nil {
+		query = query.Where("audit_logs.task_board_id = ?", *filter.TaskBoardID)
+	}
+	if filter.From != nil {
+		query = query.Where("audit_logs.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("audit_logs.created_at < ?", *filter.To)
+	}
+
+	var entries []models.AuditLog
+	if err := query.Scopes(params.Scope).Find(&entries).Error; err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, entries)
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"server/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BoardRoleRepository interface {
+	WithTx(tx *gorm.DB) BoardRoleRepository
+	Create(boardRole *models.BoardRole) (*models.BoardRole, error)
+	FindByID(boardRoleID uuid.UUID) (*models.BoardRole, error)
+	FindByTaskBoardID(taskBoardID uuid.UUID) ([]models.BoardRole, error)
+	Update(boardRole *models.BoardRole) (*models.BoardRole, error)
+	Delete(boardRoleID uuid.UUID) error
+	CountMembers(boardRoleID uuid.UUID) (int64, error)
+}
+
+type BoardRoleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBoardRoleRepository(db *gorm.DB) *BoardRoleRepositoryImpl {
+	return &BoardRoleRepositoryImpl{db: db}
+}
+
+func (repo *BoardRoleRepositoryImpl) WithTx(tx *gorm.DB) BoardRoleRepository {
+	return &BoardRoleRepositoryImpl{db: tx}
+}
+
+func (repo *BoardRoleRepositoryImpl) Create(boardRole *models.BoardRole) (*models.BoardRole, error) {
+	if err := repo.db.Create(boardRole).Error; err != nil {
+		return nil, err
+	}
+	return boardRole, nil
+}
+
+func (repo *BoardRoleRepositoryImpl) FindByID(boardRoleID uuid.UUID) (*models.BoardRole, error) {
+	var boardRole models.BoardRole
+	if err := repo.db.First(&boardRole, "id = ?", boardRoleID).Error; err != nil {
+		return nil, err
+	}
+	return &boardRole, nil
+}
+
+func (repo *BoardRoleRepositoryImpl) FindByTaskBoardID(taskBoardID uuid.UUID) ([]models.BoardRole, error) {
+	var boardRoles []models.BoardRole
+	err := repo.db.Where("task_board_id = ?", taskBoardID).Order("name").Find(&boardRoles).Error
+	if err != nil {
+		return nil, err
+	}
+	return boardRoles, nil
+}
+
+func (repo *BoardRoleRepositoryImpl) Update(boardRole *models.BoardRole) (*models.BoardRole, error) {
+	err := repo.db.Model(boardRole).Select("name", "permissions").Updates(boardRole).Error
+	if err != nil {
+		return nil, err
+	}
+	return boardRole, nil
+}
+
+func (repo *BoardRoleRepositoryImpl) Delete(boardRoleID uuid.UUID) error {
+	return repo.db.Delete(&models.BoardRole{}, "id = ?", boardRoleID).Error
+}
+
+// CountMembers counts the collaborators, teams and pending invitations
+// currently holding the role.
+func (repo *BoardRoleRepositoryImpl) CountMembers(boardRoleID uuid.UUID) (int64, error) {
+	var collaborators, teams, invitations int64
+	if err := repo.db.Model(&models.UserTaskBoard{}).Where("board_role_id = ?", boardRoleID).Count(&collaborators).Error; err != nil {
+		return 0, err
+	}
+	if err := repo.db.Model(&models.TaskBoardTeam{}).Where("board_role_id = ?", boardRoleID).Count(&teams).Error; err != nil {
+		return 0, err
+	}
+	err := repo.db.Model(&models.Invitation{}).
+		Where("board_role_id = ? AND status = ?", boardRoleID, models.InvitationPending).
+		Count(&invitations).Error
+	return collaborators + teams + invitations, err
+}
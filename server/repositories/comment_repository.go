@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) (*models.Comment, error)
+	FindByID(commentID uuid.UUID) (*models.Comment, error)
+	FindByTaskID(taskID uuid.UUID, params *pagination.Params) ([]models.Comment, *pagination.Page, error)
+	Delete(commentID uuid.UUID) error
+}
+
+type CommentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepositoryImpl {
+	return &CommentRepositoryImpl{db: db}
+}
+
+// Create adds the comment and its entry on the task's timeline.
+func (repo *CommentRepositoryImpl) Create(comment *models.Comment) (*models.Comment, error) {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		var task models.Task
+		if err := tx.Select("id", "task_board_id").First(&task, "id = ?", comment.TaskID).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.TaskActivity{
+			TaskID:      comment.TaskID,
+			TaskBoardID: task.TaskBoardID,
+			ActorID:     &comment.UserID,
+			Kind:        models.ActivityCommented,
+			CommentID:   &comment.ID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindByID(comment.ID)
+}
+
+func (repo *CommentRepositoryImpl) FindByID(commentID uuid.UUID) (*models.Comment, error) {
+	var comment models.Comment
+	if err := repo.db.Preload("User").First(&comment, "id = ?", commentID).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// CommentPages is how a task's comments page, oldest first unless asked
+// otherwise.
+var CommentPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "comments.id"},
+		"created_at": {Column: "comments.created_at"},
+		"updated_at": {Column: "comments.updated_at"},
+	},
+	Default: "created_at",
+	Unique:  "id",
+	Model:   models.Comment{},
+}
+
+// FindByTaskID lists the task's comments.
+func (repo *CommentRepositoryImpl) FindByTaskID(taskID uuid.UUID, params *pagination.Params) ([]models.Comment, *pagination.Page, error) {
+	var comments []models.Comment
+	err := repo.db.Preload("User").Where("comments.task_id = ?", taskID).Scopes(params.Scope).Find(&comments).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, comments)
+}
+
+func (repo *CommentRepositoryImpl) Delete(commentID uuid.UUID) error {
+	return repo.db.Delete(&models.Comment{}, "id = ?", commentID).Error
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CustomFieldRepository interface {
+	WithTx(tx *gorm.DB) CustomFieldRepository
+	Create(field *models.CustomField) (*models.CustomField, error)
+	FindByID(fieldID uuid.UUID) (*models.CustomField, error)
+	FindByTaskBoardID(taskBoardID uuid.UUID) ([]models.CustomField, error)
+	ListByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.CustomField, *pagination.Page, error)
+	Update(field *models.CustomField) (*models.CustomField, error)
+	Delete(fieldID uuid.UUID) error
+	DeleteValuesNotIn(fieldID uuid.UUID, options []string) error
+	ReplaceTaskValues(taskID uuid.UUID, values []models.TaskCustomFieldValue) error
+}
+
+type CustomFieldRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCustomFieldRepository(db *gorm.DB) *CustomFieldRepositoryImpl {
+	return &CustomFieldRepositoryImpl{db: db}
+}
+
+func (repo *CustomFieldRepositoryImpl) WithTx(tx *gorm.DB) CustomFieldRepository {
+	return &CustomFieldRepositoryImpl{db: tx}
+}
+
+func (repo *CustomFieldRepositoryImpl) Create(field *models.CustomField) (*models.CustomField, error) {
+	if err := repo.db.Create(field).Error; err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (repo *CustomFieldRepositoryImpl) FindByID(fieldID uuid.UUID) (*models.CustomField, error) {
+	var field models.CustomField
+	if err := repo.db.First(&field, "id = ?", fieldID).Error; err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (repo *CustomFieldRepositoryImpl) FindByTaskBoardID(taskBoardID uuid.UUID) ([]models.CustomField, error) {
+	var fields []models.CustomField
+	err := repo.db.Where("task_board_id = ?", taskBoardID).Order("created_at").Find(&fields).Error
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// CustomFieldPages is how a board's custom fields page, oldest first unless
+// asked otherwise.
+var CustomFieldPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "custom_fields.id"},
+		"name":       {Column: "custom_fields.name"},
+		"created_at": {Column: "custom_fields.created_at"},
+		"updated_at": {Column: "custom_fields.updated_at"},
+	},
+	Default: "created_at",
+	Unique:  "id",
+	Model:   models.CustomField{},
+}
+
+// ListByTaskBoardID is FindByTaskBoardID one page at a time.
+func (repo *CustomFieldRepositoryImpl) ListByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.CustomField, *pagination.Page, error) {
+	var fields []models.CustomField
+	err := repo.db.Where("custom_fields.task_board_id = ?", taskBoardID).Scopes(params.Scope).Find(&fields).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, fields)
+}
+
+func (repo *CustomFieldRepositoryImpl) Update(field *models.CustomField) (*models.CustomField, error) {
+	err := repo.db.Model(&models.CustomField{}).Where("id = ?", field.ID).
+		Select("name", "options", "required").
+		Updates(field).Error
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindByID(field.ID)
+}
+
+func (repo *CustomFieldRepositoryImpl) Delete(fieldID uuid.UUID) error {
+	return repo.db.Delete(&models.CustomField{}, "id = ?", fieldID).Error
+}
+
+// DeleteValuesNotIn drops stored selections that are no longer one of the field's options.
+func (repo *CustomFieldRepositoryImpl) DeleteValuesNotIn(fieldID uuid.UUID, options []string) error {
+	query := repo.db.Where("custom_field_id = ?", fieldID)
+	if len(options) > 0 {
+		query = query.Where("text_value NOT IN ?", options)
+	}
+	return query.Delete(&models.TaskCustomFieldValue{}).Error
+}
+
+func (repo *CustomFieldRepositoryImpl) ReplaceTaskValues(taskID uuid.UUID, values []models.TaskCustomFieldValue) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.TaskCustomFieldValue{}, "task_id = ?", taskID).Error; err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		for i := range values {
+			values[i].TaskID = taskID
+		}
+		return tx.Create(&values).Error
+	})
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvitationRepository interface {
+	WithTx(tx *gorm.DB) InvitationRepository
+	Create(invitation *models.Invitation) (*models.Invitation, error)
+	FindByID(invitationID uuid.UUID) (*models.Invitation, error)
+	FindByTokenHash(tokenHash string) (*models.Invitation, error)
+	FindByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Invitation, *pagination.Page, error)
+	FindPending(taskBoardID uuid.UUID, email string) (*models.Invitation, error)
+	Respond(invitationID uuid.UUID, status string) (bool, error)
+}
+
+type InvitationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) *InvitationRepositoryImpl {
+	return &InvitationRepositoryImpl{db: db}
+}
+
+func (repo *InvitationRepositoryImpl) WithTx(tx *gorm.DB) InvitationRepository {
+	return &InvitationRepositoryImpl{db: tx}
+}
+
+func (repo *InvitationRepositoryImpl) Create(invitation *models.Invitation) (*models.Invitation, error) {
+	if err := repo.db.Create(invitation).Error; err != nil {
+		return nil, err
+	}
+	return repo.FindByID(invitation.ID)
+}
+
+func (repo *InvitationRepositoryImpl) FindByID(invitationID uuid.UUID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := repo.db.Preload("InvitedBy").Preload("BoardRole").
+		First(&invitation, "id = ?", invitationID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindByTokenHash loads the invitation behind a link, with the board it is
+// for so the invitee can see what they are joining.
+func (repo *InvitationRepositoryImpl) FindByTokenHash(tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := repo.db.Preload("TaskBoard").Preload("InvitedBy").Preload("BoardRole").
+		First(&invitation, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// InvitationPages is how a board's invitations page, oldest first unless
+// asked otherwise.
+var InvitationPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "invitations.id"},
+		"email":      {Column: "invitations.email"},
+		"role":       {Column: "invitations.role"},
+		"expires_at": {Column: "invitations.expires_at"},
+		"created_at": {Column: "invitations.created_at"},
+	},
+	Default: "created_at",
+	Unique:  "id",
+	Model:   models.Invitation{},
+}
+
+// FindByTaskBoardID lists the board's invitations that have not been accepted
+// or revoked.
+func (repo *InvitationRepositoryImpl) FindByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Invitation, *pagination.Page, error) {
+	var invitations []models.Invitation
+	err := repo.db.Preload("InvitedBy").Preload("BoardRole").
+		Where("invitations.task_board_id = ? AND invitations.status IN ?", taskBoardID, []string{models.InvitationPending, models.InvitationDeclined}).
+		Scopes(params.Scope).
+		Find(&invitations).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, invitations)
+}
+
+// FindPending returns the unexpired pending invitation for the email, if any.
+func (repo *InvitationRepositoryImpl) FindPending(taskBoardID uuid.UUID, email string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := repo.db.
+		Where("task_board_id = ? AND email = ? AND status = ? AND expires_at > ?", taskBoardID, email, models.InvitationPending, time.Now()).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// Respond moves a pending invitation to status. It reports false when the
+// invitation was no longer pending, so two responses cannot both win.
+func (repo *InvitationRepositoryImpl) Respond(invitationID uuid.UUID, status string) (bool, error) {
+	result := repo.db.Model(&models.Invitation{}).
+		Where("id = ? AND status = ?", invitationID, models.InvitationPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LabelRepository interface {
+	WithTx(tx *gorm.DB) LabelRepository
+	Create(label *models.Label) (*models.Label, error)
+	FindByID(labelID uuid.UUID) (*models.Label, error)
+	FindByTaskBoardID(taskBoardID uuid.UUID) ([]models.Label, error)
+	ListByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Label, *pagination.Page, error)
+	FindByIDs(taskBoardID uuid.UUID, labelIDs []uuid.UUID) ([]models.Label, error)
+	Update(label *models.Label) (*models.Label, error)
+	Delete(labelID uuid.UUID) error
+}
+
+type LabelRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewLabelRepository(db *gorm.DB) *LabelRepositoryImpl {
+	return &LabelRepositoryImpl{db: db}
+}
+
+func (repo *LabelRepositoryImpl) WithTx(tx *gorm.DB) LabelRepository {
+	return &LabelRepositoryImpl{db: tx}
+}
+
+func (repo *LabelRepositoryImpl) Create(label *models.Label) (*models.Label, error) {
+	if err := repo.db.Create(label).Error; err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+func (repo *LabelRepositoryImpl) FindByID(labelID uuid.UUID) (*models.Label, error) {
+	var label models.Label
+	if err := repo.db.First(&label, "id = ?", labelID).Error; err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (repo *LabelRepositoryImpl) FindByTaskBoardID(taskBoardID uuid.UUID) ([]models.Label, error) {
+	var labels []models.Label
+	err := repo.db.Where("task_board_id = ?", taskBoardID).Order("name").Find(&labels).Error
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// LabelPages is how a board's labels page, by name unless asked otherwise.
+var LabelPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "labels.id"},
+		"name":       {Column: "labels.name"},
+		"created_at": {Column: "labels.created_at"},
+		"updated_at": {Column: "labels.updated_at"},
+	},
+	Default: "name",
+	Unique:  "id",
+	Model:   models.Label{},
+}
+
+// ListByTaskBoardID is FindByTaskBoardID one page at a time.
+func (repo *LabelRepositoryImpl) ListByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Label, *pagination.Page, error) {
+	var labels []models.Label
+	err := repo.db.Where("labels.task_board_id = ?", taskBoardID).Scopes(params.Scope).Find(&labels).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, labels)
+}
+
+// FindByIDs returns the listed labels that belong to the board; IDs from
+// other boards are silently left out.
+func (repo *LabelRepositoryImpl) FindByIDs(taskBoardID uuid.UUID, labelIDs []uuid.UUID) ([]models.Label, error) {
+	var labels []models.Label
+	if len(labelIDs) == 0 {
+		return labels, nil
+	}
+	err := repo.db.Where("task_board_id = ? AND id IN ?", taskBoardID, labelIDs).Find(&labels).Error
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (repo *LabelRepositoryImpl) Update(label *models.Label) (*models.Label, error) {
+	err := repo.db.Model(label).Select("name", "color").Updates(label).Error
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// Delete removes the label; the join table cascade detaches it from tasks.
+func (repo *LabelRepositoryImpl) Delete(labelID uuid.UUID) error {
+	return repo.db.Delete(&models.Label{}, "id = ?", labelID).Error
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"fmt"
+	"server/models"
+	"server/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Add(notification *models.Notification) (*models.Notification, error)
+	FindByID(notificationID uuid.UUID) (*models.Notification, error)
+	FindByUserID(userID uuid.UUID, unreadOnly bool, params *pagination.Params) ([]models.Notification, *pagination.Page, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	MarkRead(userID uuid.UUID, notificationID uuid.UUID) error
+	MarkAllRead(userID uuid.UUID) (int64, error)
+}
+
+type NotificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepositoryImpl {
+	return &NotificationRepositoryImpl{db: db}
+}
+
+// NotificationPages is how an inbox pages, most recently active first unless
+// asked otherwise.
+var NotificationPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "notifications.id"},
+		"created_at": {Column: "notifications.created_at"},
+		"updated_at": {Column: "notifications.updated_at"},
+	},
+	Default: "-updated_at",
+	Unique:  "id",
+	Model:   models.Notification{},
+}
+
+// Add files the notification and returns it as stored. A task_updated
+// notification collapses into the user's unread one for the same task when
+// there is one: its count goes up, its fields join the new ones and it
+// takes the new actor and time.
+func (repo *NotificationRepositoryImpl) Add(notification *models.Notification) (*models.Notification, error) {
+	if notification.Kind != models.NotificationTaskUpdated || notification.TaskID == nil {
+		if err := repo.db.Create(notification).Error; err != nil {
+			return nil, err
+		}
+		return repo.FindByID(notification.ID)
+	}
+
+	now := time.Now()
+	var notificationID uuid.UUID
+	err := repo.db.Raw(`
+		INSERT INTO notifications (user_id, actor_id, kind, task_board_id, task_id, fields, count, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, ?, ?)
+		ON CONFLICT (user_id, task_id) WHERE kind = 'task_updated' AND read_at IS NULL
+		DO UPDATE SET
+			actor_id = EXCLUDED.actor_id,
+			task_board_id = EXCLUDED.task_board_id,
+			fields = (
+				SELECT COALESCE(jsonb_agg(DISTINCT field ORDER BY field), '[]')
+				FROM jsonb_array_elements_text(notifications.fields || EXCLUDED.fields) AS field
+			),
+			count = notifications.count + 1,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id`,
+		notification.UserID, notification.ActorID, notification.Kind, notification.TaskBoardID,
+		notification.TaskID, notification.Fields, now, now,
+	).Scan(&notificationID).Error
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindByID(notificationID)
+}
+
+func (repo *NotificationRepositoryImpl) FindByID(notificationID uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	if err := repo.preload(repo.db).First(&notification, "notifications.id = ?", notificationID).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// FindByUserID lists the user's inbox, or only its unread notifications.
+func (repo *NotificationRepositoryImpl) FindByUserID(userID uuid.UUID, unreadOnly bool, params *pagination.Params) ([]models.Notification, *pagination.Page, error) {
+	query := repo.preload(repo.db).Where("notifications.user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("notifications.read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Scopes(params.Scope).Find(&notifications).Error; err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, notifications)
+}
+
+// preload loads what a notification is about, enough to render it.
+func (repo *NotificationRepositoryImpl) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Actor").Preload("Comment").
+		Preload("TaskBoard", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "title")
+		}).
+		Preload("Task", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "task_board_id", "title", "status")
+		})
+}
+
+func (repo *NotificationRepositoryImpl) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := repo.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of the user's notifications read; reading it again
+// keeps the first read time.
+func (repo *NotificationRepositoryImpl) MarkRead(userID uuid.UUID, notificationID uuid.UUID) error {
+	result := repo.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		UpdateColumn("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user read and returns
+// how many there were.
+func (repo *NotificationRepositoryImpl) MarkAllRead(userID uuid.UUID) (int64, error) {
+	result := repo.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		UpdateColumn("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
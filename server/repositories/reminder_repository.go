@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"errors"
+	"server/models"
+	"server/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository interface {
+	GetSetting(userID uuid.UUID) (*models.ReminderSetting, error)
+	SaveSetting(setting *models.ReminderSetting) (*models.ReminderSetting, error)
+	FindSettings(userIDs []uuid.UUID) ([]models.ReminderSetting, error)
+	FindOpenTasksDueBetween(from, to time.Time) ([]models.Task, error)
+	Claim(reminder *models.TaskReminder) (bool, error)
+	FindByUserID(userID uuid.UUID, params *pagination.Params) ([]models.TaskReminder, *pagination.Page, error)
+}
+
+type ReminderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) *ReminderRepositoryImpl {
+	return &ReminderRepositoryImpl{db: db}
+}
+
+// GetSetting returns nil without an error when the user kept the defaults.
+func (repo *ReminderRepositoryImpl) GetSetting(userID uuid.UUID) (*models.ReminderSetting, error) {
+	var setting models.ReminderSetting
+	if err := repo.db.First(&setting, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (repo *ReminderRepositoryImpl) SaveSetting(setting *models.ReminderSetting) (*models.ReminderSetting, error) {
+	if err := repo.db.Omit(clause.Associations).Save(setting).Error; err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+func (repo *ReminderRepositoryImpl) FindSettings(userIDs []uuid.UUID) ([]models.ReminderSetting, error) {
+	var settings []models.ReminderSetting
+	if len(userIDs) == 0 {
+		return settings, nil
+	}
+	if err := repo.db.Where("user_id IN ?", userIDs).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (repo *ReminderRepositoryImpl) FindOpenTasksDueBetween(from, to time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := repo.db.
+		Joins("JOIN task_boards ON task_boards.id = tasks.task_board_id").
+		Where("task_boards.is_template = ?", false).
+		Where("task_boards.archived_at IS NULL AND task_boards.deleted_at IS NULL AND tasks.archived_at IS NULL").
+		Where("tasks.status <> ? AND tasks.end_date BETWEEN ? AND ?", "done", from, to).
+		Order("tasks.end_date").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Claim stores the reminder unless it was already sent, reporting whether
+// this call is the one that should deliver it.
+func (repo *ReminderRepositoryImpl) Claim(reminder *models.TaskReminder) (bool, error) {
+	result := repo.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reminder)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReminderPages is how the reminders sent to a user page, most recent first
+// unless asked otherwise.
+var ReminderPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":      {Column: "task_reminders.id"},
+		"due_at":  {Column: "task_reminders.due_at"},
+		"sent_at": {Column: "task_reminders.sent_at"},
+	},
+	Default: "-sent_at",
+	Unique:  "id",
+	Model:   models.TaskReminder{},
+}
+
+func (repo *ReminderRepositoryImpl) FindByUserID(userID uuid.UUID, params *pagination.Params) ([]models.TaskReminder, *pagination.Page, error) {
+	var reminders []models.TaskReminder
+	err := repo.db.Preload("Task").
+		Where("task_reminders.user_id = ?", userID).
+		Scopes(params.Scope).
+		Find(&reminders).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, reminders)
+}
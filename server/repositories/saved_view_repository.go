@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SavedViewRepository interface {
+	Create(view *models.SavedView) (*models.SavedView, error)
+	FindByID(viewID uuid.UUID) (*models.SavedView, error)
+	FindVisible(taskBoardID uuid.UUID, userID uuid.UUID, params *pagination.Params) ([]models.SavedView, *pagination.Page, error)
+	Update(view *models.SavedView) (*models.SavedView, error)
+	Delete(viewID uuid.UUID) error
+}
+
+type SavedViewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSavedViewRepository(db *gorm.DB) *SavedViewRepositoryImpl {
+	return &SavedViewRepositoryImpl{db: db}
+}
+
+func (repo *SavedViewRepositoryImpl) Create(view *models.SavedView) (*models.SavedView, error) {
+	if err := repo.db.Create(view).Error; err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (repo *SavedViewRepositoryImpl) FindByID(viewID uuid.UUID) (*models.SavedView, error) {
+	var view models.SavedView
+	if err := repo.db.First(&view, "id = ?", viewID).Error; err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// SavedViewPages is how a board's saved views page, by name unless asked
+// otherwise.
+var SavedViewPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "saved_views.id"},
+		"name":       {Column: "saved_views.name"},
+		"created_at": {Column: "saved_views.created_at"},
+		"updated_at": {Column: "saved_views.updated_at"},
+	},
+	Default: "name",
+	Unique:  "id",
+	Model:   models.SavedView{},
+}
+
+// FindVisible lists the board's shared views and the user's private ones.
+func (repo *SavedViewRepositoryImpl) FindVisible(taskBoardID uuid.UUID, userID uuid.UUID, params *pagination.Params) ([]models.SavedView, *pagination.Page, error) {
+	var views []models.SavedView
+	err := repo.db.
+		Where("saved_views.task_board_id = ? AND (saved_views.visibility = ? OR saved_views.owner_id = ?)", taskBoardID, models.ViewShared, userID).
+		Scopes(params.Scope).
+		Find(&views).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, views)
+}
+
+func (repo *SavedViewRepositoryImpl) Update(view *models.SavedView) (*models.SavedView, error) {
+	err := repo.db.Model(view).
+		Select("name", "visibility", "filter", "sort", "group_by", "columns").
+		Updates(view).Error
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (repo *SavedViewRepositoryImpl) Delete(viewID uuid.UUID) error {
+	return repo.db.Delete(&models.SavedView{}, "id = ?", viewID).Error
+}
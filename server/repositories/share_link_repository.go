@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareLinkRepository interface {
+	Create(link *models.ShareLink) (*models.ShareLink, error)
+	FindByID(linkID uuid.UUID) (*models.ShareLink, error)
+	FindByTokenHash(tokenHash string) (*models.ShareLink, error)
+	FindByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.ShareLink, *pagination.Page, error)
+	Revoke(linkID uuid.UUID) error
+	RecordAccess(access *models.ShareLinkAccess) error
+	FindAccesses(linkID uuid.UUID, params *pagination.Params) ([]models.ShareLinkAccess, *pagination.Page, error)
+	CountAccesses(linkID uuid.UUID, outcome string, since time.Time) (int64, error)
+}
+
+type ShareLinkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewShareLinkRepository(db *gorm.DB) *ShareLinkRepositoryImpl {
+	return &ShareLinkRepositoryImpl{db: db}
+}
+
+func (repo *ShareLinkRepositoryImpl) Create(link *models.ShareLink) (*models.ShareLink, error) {
+	if err := repo.db.Create(link).Error; err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (repo *ShareLinkRepositoryImpl) FindByID(linkID uuid.UUID) (*models.ShareLink, error) {
+	var link models.ShareLink
+	if err := repo.db.Preload("CreatedBy").First(&link, "id = ?", linkID).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (repo *ShareLinkRepositoryImpl) FindByTokenHash(tokenHash string) (*models.ShareLink, error) {
+	var link models.ShareLink
+	if err := repo.db.First(&link, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ShareLinkPages is how a board's share links page, newest first unless
+// asked otherwise.
+var ShareLinkPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":               {Column: "share_links.id"},
+		"created_at":       {Column: "share_links.created_at"},
+		"expires_at":       {Column: "share_links.expires_at", Nullable: true},
+		"last_accessed_at": {Column: "share_links.last_accessed_at", Nullable: true},
+		"access_count":     {Column: "share_links.access_count"},
+	},
+	Default: "-created_at",
+	Unique:  "id",
+	Model:   models.ShareLink{},
+}
+
+// FindByTaskBoardID lists the links of the board, revoked ones included.
+func (repo *ShareLinkRepositoryImpl) FindByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.ShareLink, *pagination.Page, error) {
+	var links []models.ShareLink
+	err := repo.db.Preload("CreatedBy").
+		Where("share_links.task_board_id = ?", taskBoardID).
+		Scopes(params.Scope).
+		Find(&links).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, links)
+}
+
+func (repo *ShareLinkRepositoryImpl) Revoke(linkID uuid.UUID) error {
+	return repo.db.Model(&models.ShareLink{}).
+		Where("id = ? AND revoked_at IS NULL", linkID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RecordAccess writes the audit entry, and for granted requests bumps the
+// link's access counter.
+func (repo *ShareLinkRepositoryImpl) RecordAccess(access *models.ShareLinkAccess) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(access).Error; err != nil {
+			return err
+		}
+		if access.Outcome != models.ShareAccessGranted {
+			return nil
+		}
+		return tx.Model(&models.ShareLink{}).
+			Where("id = ?", access.ShareLinkID).
+			Updates(map[string]interface{}{
+				"access_count":     gorm.Expr("access_count + 1"),
+				"last_accessed_at": access.CreatedAt,
+			}).Error
+	})
+}
+
+// ShareLinkAccessPages is how a link's audit entries page, most recent first
+// unless asked otherwise.
+var ShareLinkAccessPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "share_link_accesses.id"},
+		"outcome":    {Column: "share_link_accesses.outcome"},
+		"created_at": {Column: "share_link_accesses.created_at"},
+	},
+	Default: "-created_at",
+	Unique:  "id",
+	Model:   models.ShareLinkAccess{},
+}
+
+// FindAccesses returns the link's audit entries.
+func (repo *ShareLinkRepositoryImpl) FindAccesses(linkID uuid.UUID, params *pagination.Params) ([]models.ShareLinkAccess, *pagination.Page, error) {
+	var accesses []models.ShareLinkAccess
+	err := repo.db.Where("share_link_accesses.share_link_id = ?", linkID).
+		Scopes(params.Scope).
+		Find(&accesses).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, accesses)
+}
+
+func (repo *ShareLinkRepositoryImpl) CountAccesses(linkID uuid.UUID, outcome string, since time.Time) (int64, error) {
+	var count int64
+	err := repo.db.Model(&models.ShareLinkAccess{}).
+		Where("share_link_id = ? AND outcome = ? AND created_at >= ?", linkID, outcome, since).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaskActivityRepository interface {
+	FindByTaskID(taskID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error)
+	FindByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error)
+}
+
+type TaskActivityRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTaskActivityRepository(db *gorm.DB) *TaskActivityRepositoryImpl {
+	return &TaskActivityRepositoryImpl{db: db}
+}
+
+// TaskActivityPages is how task timelines and board feeds page, most recent
+// first unless asked otherwise.
+var TaskActivityPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "task_activities.id"},
+		"created_at": {Column: "task_activities.created_at"},
+	},
+	Default: "-created_at",
+	Unique:  "id",
+	Model:   models.TaskActivity{},
+}
+
+// FindByTaskID returns the task's timeline.
+func (repo *TaskActivityRepositoryImpl) FindByTaskID(taskID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error) {
+	var activities []models.TaskActivity
+	err := repo.db.Preload("Actor").Preload("Comment").
+		Where("task_activities.task_id = ?", taskID).
+		Scopes(params.Scope).Find(&activities).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, activities)
+}
+
+// FindByTaskBoardID returns what happened to tasks while they were on the
+// board, each entry with its task, including tasks since deleted.
+func (repo *TaskActivityRepositoryImpl) FindByTaskBoardID(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error) {
+	var activities []models.TaskActivity
+	err := repo.db.Preload("Actor").Preload("Comment").
+		Preload("Task", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Select("id", "task_board_id", "title", "status", "deleted_at")
+		}).
+		Where("task_activities.task_board_id = ?", taskBoardID).
+		Scopes(params.Scope).Find(&activities).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, activities)
+}
+
+// taskActivityFields reads the task fields whose changes show on its
+// timeline, as the text they are recorded with.
+func taskActivityFields(task *models.Task) map[string]*string {
+	return map[string]*string{
+		"title":            activityText(task.Title),
+		"description":      activityText(task.Description),
+		"status":           activityText(task.Status),
+		"priority":         activityText(task.Priority),
+		"start_date":       activityTime(task.StartDate),
+		"end_date":         activityTime(task.EndDate),
+		"estimate_minutes": activityInt(task.EstimateMinutes),
+		"assignee_id":      activityUUID(task.AssigneeID),
+		"task_board_id":    activityUUID(&task.TaskBoardID),
+	}
+}
+
+// taskActivityOrder is the order changes made together are recorded in.
+var taskActivityOrder = []string{"task_board_id", "status", "assignee_id", "priority", "title", "description", "start_date", "end_date", "estimate_minutes"}
+
+// recordTaskChanges adds a timeline entry for each field that differs
+// between the task as read before and after a write.
+func recordTaskChanges(db *gorm.DB, before *models.Task, after *models.Task, actorID uuid.UUID) error {
+	from := taskActivityFields(before)
+	to := taskActivityFields(after)
+
+	var activities []models.TaskActivity
+	for _, field := range taskActivityOrder {
+		if sameActivityValue(from[field], to[field]) {
+			continue
+		}
+		activities = append(activities, models.TaskActivity{
+			TaskID:      after.ID,
+			TaskBoardID: after.TaskBoardID,
+			ActorID:     &actorID,
+			Kind:        models.ActivityChanged,
+			Field:       field,
+			OldValue:    from[field],
+			NewValue:    to[field],
+		})
+	}
+	if len(activities) == 0 {
+		return nil
+	}
+	return db.Create(&activities).Error
+}
+
+func sameActivityValue(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func activityText(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func activityTime(value time.Time) *string {
+	if value.IsZero() {
+		return nil
+	}
+	return activityText(value.UTC().Format(time.RFC3339))
+}
+
+func activityInt(value *int) *string {
+	if value == nil {
+		return nil
+	}
+	return activityText(strconv.Itoa(*value))
+}
+
+func activityUUID(value *uuid.UUID) *string {
+	if value == nil {
+		return nil
+	}
+	return activityText(value.String())
+}
@@ -1,35 +1,183 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"server/models"
+	"server/pagination"
+	"server/policy"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Role is a user's role on a task board; the values match policy.Roles.
 type Role string
 
 const (
 	Owner       Role = "owner"
-	Collaborator Role = "collaborator"
+	Editor      Role = "editor"
 	Viewer      Role = "viewer"
 )
 
+// ListOptions brings archived or soft-deleted rows back into a list query,
+// which leaves them out by default.
+type ListOptions struct {
+	IncludeArchived bool
+	IncludeDeleted  bool
+}
+
+// TaskBoardPages is how board lists page.
+var TaskBoardPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "task_boards.id"},
+		"title":      {Column: "task_boards.title"},
+		"created_at": {Column: "task_boards.created_at"},
+		"updated_at": {Column: "task_boards.updated_at"},
+	},
+	Default: "title",
+	Unique:  "id",
+	Model:   models.TaskBoard{},
+}
+
+// TaskBoardTrashPages is how the list of deleted boards pages.
+var TaskBoardTrashPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "task_boards.id"},
+		"title":      {Column: "task_boards.title"},
+		"deleted_at": {Column: "task_boards.deleted_at"},
+	},
+	Default: "-deleted_at",
+	Unique:  "id",
+	Model:   models.TaskBoard{},
+}
+
+// TaskTrashPages is how the list of a board's deleted tasks pages.
+var TaskTrashPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "tasks.id"},
+		"title":      {Column: "tasks.title"},
+		"deleted_at": {Column: "tasks.deleted_at"},
+	},
+	Default: "-deleted_at",
+	Unique:  "id",
+	Model:   models.Task{},
+}
+
+// TaskFilter narrows the tasks returned with a board.
+type TaskFilter struct {
+	ListOptions
+	Status       []string
+	Priority     []string
+	// Labels matches tasks carrying any of the labels; Assignees and
+	// Unassigned together match tasks assigned to any of the users, or to
+	// no one.
+	Labels       []uuid.UUID
+	Assignees    []uuid.UUID
+	Unassigned   bool
+	CustomFields []CustomFieldFilter
+}
+
+// CustomFieldFilter matches tasks holding any of Values, or a value within
+// [Min, Max], in the field's typed Column.
+type CustomFieldFilter struct {
+	FieldID uuid.UUID
+	Column  string
+	Values  []interface{}
+	Min     interface{}
+	Max     interface{}
+}
+
+// TaskPages is how a board's tasks page. Besides the task columns, tasks
+// sort by the value of any of the board's fields other than multi-select
+// ones, named "cf:<field id>"; tasks without a value come last.
+func TaskPages(fields []models.CustomField) pagination.Spec {
+	keys := map[string]pagination.Key{
+		"id":         {Column: "tasks.id"},
+		"title":      {Column: "tasks.title"},
+		"status":     {Column: "tasks.status"},
+		"priority":   {Column: "tasks.priority"},
+		"start_date": {Column: "tasks.start_date"},
+		"end_date":   {Column: "tasks.end_date"},
+		"created_at": {Column: "tasks.created_at"},
+		"updated_at": {Column: "tasks.updated_at"},
+	}
+	for _, field := range fields {
+		if field.Type == models.CustomFieldMultiSelect {
+			continue
+		}
+		field := field
+		keys["cf:"+field.ID.String()] = pagination.Key{
+			Column:   fmt.Sprintf("(SELECT cf.%s FROM task_custom_field_values cf WHERE cf.task_id = tasks.id AND cf.custom_field_id = ?)", field.ValueColumn()),
+			Args:     []interface{}{field.ID},
+			Nullable: true,
+			Value: func(row interface{}) interface{} {
+				return customFieldValue(row.(models.Task), field)
+			},
+		}
+	}
+	return pagination.Spec{
+		Keys:    keys,
+		Default: "created_at",
+		Unique:  "id",
+		Model:   models.Task{},
+	}
+}
+
+// customFieldValue is the task's value of a single-valued field, or nil.
+func customFieldValue(task models.Task, field models.CustomField) interface{} {
+	for _, value := range task.CustomFieldValues {
+		if value.CustomFieldID != field.ID {
+			continue
+		}
+		switch {
+		case value.NumberValue != nil:
+			return *value.NumberValue
+		case value.DateValue != nil:
+			return *value.DateValue
+		case value.TextValue != nil:
+			return *value.TextValue
+		}
+	}
+	return nil
+}
+
 // TaskBoardRepository defines the interface for task board operations
 type TaskBoardRepository interface {
+	WithTx(tx *gorm.DB) TaskBoardRepository
 	Create(taskBoard *models.TaskBoard) (*models.TaskBoard, error)
 	CreateUserBoard(userTaskBoard *models.UserTaskBoard) (*models.UserTaskBoard, error)
-	FindByUserID(userID uuid.UUID) ([]models.TaskBoard, error)
+	FindByUserID(userID uuid.UUID, options ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+	FindTemplatesByUserID(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+	FindByWorkspaceID(workspaceID uuid.UUID, userID uuid.UUID, options ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+	FindTaskRef(taskID uuid.UUID) (*models.Task, error)
+	FindDeletedByOwnerID(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+	FindDeletedTasks(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Task, *pagination.Page, error)
+	SetArchived(taskBoardID uuid.UUID, archivedAt *time.Time) error
+	Restore(taskBoardID uuid.UUID) error
+	FindDeletedByID(taskBoardID uuid.UUID) (*models.TaskBoard, error)
+	PurgeDeleted(before time.Time) (int64, error)
 	FindByID(taskBoardID uuid.UUID) (*models.TaskBoard, error)
-	FindByIDWithFilter(taskBoardID uuid.UUID, status []string, priority []string) (*models.TaskBoard, error)
+	FindByIDWithFilter(taskBoardID uuid.UUID, filter TaskFilter, params *pagination.Params) (*models.TaskBoard, *pagination.Page, error)
 	Update(taskBoardID uuid.UUID, taskBoard *models.TaskBoard) (*models.TaskBoard, error)
 	Delete(taskBoardID uuid.UUID) error
-	AddCollaborator(UserID uuid.UUID, TaskBoardID uuid.UUID, role Role) (*models.UserTaskBoard, error)
+	AddCollaborator(UserID uuid.UUID, TaskBoardID uuid.UUID, role Role, boardRoleID *uuid.UUID) (*models.UserTaskBoard, error)
 	RemoveCollaborator(taskBoardID uuid.UUID, userID uuid.UUID) error
+	LockCollaborators(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error)
+	UpdateCollaboratorRole(taskBoardID uuid.UUID, userID uuid.UUID, role string, boardRoleID *uuid.UUID) error
+	CountOwners(taskBoardID uuid.UUID) (int64, error)
+	UnassignUserTasks(taskBoardID uuid.UUID, userID uuid.UUID) error
 	CheckUserRole(taskBoardID uuid.UUID, userID uuid.UUID) (*models.UserTaskBoard, error)
+	FindCollaborator(taskBoardID uuid.UUID, userID uuid.UUID) (*models.UserTaskBoard, error)
+	FindCollaborators(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error)
 	GetUsersOnTaskBoard(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error)
+	GetStatusLimits(taskBoardID uuid.UUID) ([]models.TaskBoardStatusLimit, error)
+	LockStatusLimit(taskBoardID uuid.UUID, status string) (*models.TaskBoardStatusLimit, error)
+	SetStatusLimits(taskBoardID uuid.UUID, limits []models.TaskBoardStatusLimit) error
+	CountTasksByStatus(taskBoardID uuid.UUID) (map[string]int64, error)
 }
 
 // TaskBoardRepositoryImpl is the concrete implementation of TaskBoardRepository
@@ -41,6 +189,10 @@ func NewTaskBoardRepository(db *gorm.DB) *TaskBoardRepositoryImpl {
 	return &TaskBoardRepositoryImpl{db: db}
 }
 
+func (repo *TaskBoardRepositoryImpl) WithTx(tx *gorm.DB) TaskBoardRepository {
+	return &TaskBoardRepositoryImpl{db: tx}
+}
+
 func (repo *TaskBoardRepositoryImpl) Create(taskBoard *models.TaskBoard) (*models.TaskBoard, error) {
     if err := repo.db.Create(taskBoard).Error; err != nil {
         log.Printf("Error creating task board in database: %v", err)
@@ -64,11 +216,11 @@ func (repo *TaskBoardRepositoryImpl) CreateUserBoard(userTaskBoard *models.UserT
 }
 
 
+// FindByID loads the board row; its tasks are listed a page at a time by
+// FindByIDWithFilter.
 func (repo *TaskBoardRepositoryImpl) FindByID(taskBoardID uuid.UUID) (*models.TaskBoard, error) {
 	var taskBoard models.TaskBoard
 	err := repo.db.
-		Preload("Tasks").
-		Preload("Tasks.TaskBoard").
 		Where("id = ?", taskBoardID).
 		First(&taskBoard).Error
 
@@ -78,45 +230,154 @@ func (repo *TaskBoardRepositoryImpl) FindByID(taskBoardID uuid.UUID) (*models.Ta
 	return &taskBoard, nil
 }
 
-func (repo *TaskBoardRepositoryImpl) FindByIDWithFilter(taskBoardID uuid.UUID, status  []string, priorities []string) (*models.TaskBoard, error) {
+// FindByIDWithFilter loads the board with one page of the tasks matching the
+// filter, or with all of them when params is nil.
+func (repo *TaskBoardRepositoryImpl) FindByIDWithFilter(taskBoardID uuid.UUID, filter TaskFilter, params *pagination.Params) (*models.TaskBoard, *pagination.Page, error) {
+	db := repo.db
+	if filter.IncludeDeleted {
+		db = db.Unscoped()
+	}
+
 	var taskBoard models.TaskBoard
-	if err := repo.db.First(&taskBoard, "id = ?", taskBoardID).Error; err != nil {
-		return nil, err
+	if err := db.First(&taskBoard, "id = ?", taskBoardID).Error; err != nil {
+		return nil, nil, err
+	}
+
+	tasksQuery := db.Model(&models.Task{}).
+		Select("tasks.*").
+		Preload("CustomFieldValues").
+		Preload("Labels").
+		Where("tasks.task_board_id = ?", taskBoardID)
+
+	if !filter.IncludeArchived {
+		tasksQuery = tasksQuery.Where("tasks.archived_at IS NULL")
 	}
 
-	tasksQuery := repo.db.Model(&models.Task{}).Where("task_board_id = ?", taskBoardID)
+	if len(filter.Status) > 0 {
+		tasksQuery = tasksQuery.Where("tasks.status IN (?)", filter.Status)
+	}
 
-	if len(status) > 0 {
-		tasksQuery = tasksQuery.Where("status IN (?)", status)
+	if len(filter.Priority) > 0 {
+		tasksQuery = tasksQuery.Where("tasks.priority IN (?)", filter.Priority)
 	}
 
-	if len(priorities) > 0 {
-		tasksQuery = tasksQuery.Where("priority IN (?)", priorities)
+	if len(filter.Labels) > 0 {
+		tasksQuery = tasksQuery.Where("EXISTS (SELECT 1 FROM task_labels WHERE task_labels.task_id = tasks.id AND task_labels.label_id IN (?))", filter.Labels)
+	}
+
+	switch {
+	case len(filter.Assignees) > 0 && filter.Unassigned:
+		tasksQuery = tasksQuery.Where("(tasks.assignee_id IN (?) OR tasks.assignee_id IS NULL)", filter.Assignees)
+	case len(filter.Assignees) > 0:
+		tasksQuery = tasksQuery.Where("tasks.assignee_id IN (?)", filter.Assignees)
+	case filter.Unassigned:
+		tasksQuery = tasksQuery.Where("tasks.assignee_id IS NULL")
+	}
+
+	for _, fieldFilter := range filter.CustomFields {
+		tasksQuery = applyCustomFieldFilter(tasksQuery, fieldFilter)
 	}
 
 	var filteredTasks []models.Task
-	if err := tasksQuery.Find(&filteredTasks).Error; err != nil {
-		return nil, err
+	if params == nil {
+		if err := tasksQuery.Find(&filteredTasks).Error; err != nil {
+			return nil, nil, err
+		}
+		taskBoard.Tasks = filteredTasks
+		return &taskBoard, nil, nil
 	}
 
+	if err := tasksQuery.Scopes(params.Scope).Find(&filteredTasks).Error; err != nil {
+		return nil, nil, err
+	}
+	filteredTasks, page, err := pagination.Cut(params, filteredTasks)
+	if err != nil {
+		return nil, nil, err
+	}
 	taskBoard.Tasks = filteredTasks
 
-	return &taskBoard, nil
+	return &taskBoard, page, nil
+}
+
+func applyCustomFieldFilter(query *gorm.DB, filter CustomFieldFilter) *gorm.DB {
+	condition := "EXISTS (SELECT 1 FROM task_custom_field_values cf WHERE cf.task_id = tasks.id AND cf.custom_field_id = ?"
+	args := []interface{}{filter.FieldID}
+
+	if len(filter.Values) > 0 {
+		condition += fmt.Sprintf(" AND cf.%s IN ?", filter.Column)
+		args = append(args, filter.Values)
+	}
+	if filter.Min != nil {
+		condition += fmt.Sprintf(" AND cf.%s >= ?", filter.Column)
+		args = append(args, filter.Min)
+	}
+	if filter.Max != nil {
+		condition += fmt.Sprintf(" AND cf.%s <= ?", filter.Column)
+		args = append(args, filter.Max)
+	}
+
+	return query.Where(condition+")", args...)
 }
 
 
 
-func (repo *TaskBoardRepositoryImpl) FindByUserID(userID uuid.UUID) ([]models.TaskBoard, error) {
+func (repo *TaskBoardRepositoryImpl) FindByUserID(userID uuid.UUID, options ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	db := repo.db
+	if options.IncludeDeleted {
+		db = db.Unscoped()
+	}
+
+	query := db.Preload("Users").
+		Scopes(accessibleTo(userID)).
+		Where("task_boards.is_template = ?", false)
+	if !options.IncludeArchived {
+		query = query.Where("task_boards.archived_at IS NULL")
+	}
+
 	var taskBoards []models.TaskBoard
-	err := repo.db.Preload("Users").
-		Joins("JOIN user_task_boards ON task_boards.id = user_task_boards.task_board_id").
-		Where("user_task_boards.user_id = ?", userID).
-		Find(&taskBoards).Error
+	err := query.Scopes(params.Scope).Find(&taskBoards).Error
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	return pagination.Cut(params, taskBoards)
+}
+
+// FindByWorkspaceID lists the workspace's boards the user can open.
+func (repo *TaskBoardRepositoryImpl) FindByWorkspaceID(workspaceID uuid.UUID, userID uuid.UUID, options ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	db := repo.db
+	if options.IncludeDeleted {
+		db = db.Unscoped()
+	}
+
+	query := db.Preload("Users").
+		Scopes(accessibleTo(userID)).
+		Where("task_boards.workspace_id = ? AND task_boards.is_template = ?", workspaceID, false)
+	if !options.IncludeArchived {
+		query = query.Where("task_boards.archived_at IS NULL")
+	}
+
+	var taskBoards []models.TaskBoard
+	if err := query.Scopes(params.Scope).Find(&taskBoards).Error; err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, taskBoards)
+}
+
+// FindTemplatesByUserID lists the templates the user can open, which
+// includes every template shared in their workspaces.
+func (repo *TaskBoardRepositoryImpl) FindTemplatesByUserID(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	var taskBoards []models.TaskBoard
+	err := repo.db.
+		Scopes(accessibleTo(userID)).
+		Where("task_boards.is_template = ?", true).
+		Where("task_boards.archived_at IS NULL").
+		Scopes(params.Scope).
+		Find(&taskBoards).Error
+	if err != nil {
+		return nil, nil, err
 	}
-	return taskBoards, nil
+	return pagination.Cut(params, taskBoards)
 }
 
 
@@ -141,18 +402,21 @@ func (repo *TaskBoardRepositoryImpl) Delete(taskBoardID uuid.UUID) error {
 	return repo.db.Delete(&models.TaskBoard{}, "id = ?", taskBoardID).Error
 }
 
-func (repo *TaskBoardRepositoryImpl) AddCollaborator(UserID uuid.UUID, TaskBoardID uuid.UUID, role Role) (*models.UserTaskBoard, error) {
+// AddCollaborator adds the user with a preset role, or with the custom role
+// boardRoleID when role is "custom".
+func (repo *TaskBoardRepositoryImpl) AddCollaborator(UserID uuid.UUID, TaskBoardID uuid.UUID, role Role, boardRoleID *uuid.UUID) (*models.UserTaskBoard, error) {
 	userTaskBoard := models.UserTaskBoard{
 		UserID:      UserID,
 		TaskBoardID: TaskBoardID,
 		Role:        string(role),
+		BoardRoleID: boardRoleID,
 	}
 
 	if err := repo.db.Create(&userTaskBoard).Error; err != nil {
 		return nil, err
 	}
 
-	if err := repo.db.Preload("User").Preload("TaskBoard").
+	if err := repo.db.Preload("User").Preload("TaskBoard").Preload("BoardRole").
 		First(&userTaskBoard, "user_id = ? AND task_board_id = ?", userTaskBoard.UserID, userTaskBoard.TaskBoardID).Error; err != nil {
 		return nil, err
 	}
@@ -164,30 +428,442 @@ func (repo *TaskBoardRepositoryImpl) RemoveCollaborator(taskBoardID uuid.UUID, u
 	return repo.db.Delete(&models.UserTaskBoard{}, "task_board_id = ? AND user_id = ?", taskBoardID, userID).Error
 }
 
+// LockCollaborators returns the board's memberships locked FOR UPDATE, so
+// concurrent role changes on one board run one after another. It must be
+// called inside a transaction.
+func (repo *TaskBoardRepositoryImpl) LockCollaborators(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error) {
+	var userTaskBoards []models.UserTaskBoard
+	err := repo.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("task_board_id = ?", taskBoardID).
+		Find(&userTaskBoards).Error
+	if err != nil {
+		return nil, err
+	}
+	return userTaskBoards, nil
+}
+
+func (repo *TaskBoardRepositoryImpl) UpdateCollaboratorRole(taskBoardID uuid.UUID, userID uuid.UUID, role string, boardRoleID *uuid.UUID) error {
+	return repo.db.Model(&models.UserTaskBoard{}).
+		Where("task_board_id = ? AND user_id = ?", taskBoardID, userID).
+		Updates(map[string]interface{}{"role": role, "board_role_id": boardRoleID}).Error
+}
+
+func (repo *TaskBoardRepositoryImpl) CountOwners(taskBoardID uuid.UUID) (int64, error) {
+	var count int64
+	err := repo.db.Model(&models.UserTaskBoard{}).
+		Where("task_board_id = ? AND role = ?", taskBoardID, "owner").
+		Count(&count).Error
+	return count, err
+}
+
+// UnassignUserTasks clears the user from every task on the board they were assigned.
+func (repo *TaskBoardRepositoryImpl) UnassignUserTasks(taskBoardID uuid.UUID, userID uuid.UUID) error {
+	return repo.db.Model(&models.Task{}).
+		Where("task_board_id = ? AND assignee_id = ?", taskBoardID, userID).
+		Update("assignee_id", nil).Error
+}
+
+// CheckUserRole resolves the user's access to the board across their own
+// membership, the grants of their teams and their workspace role. Role is the
+// most privileged grant and Grants says where each one comes from. The error
+// is gorm.ErrRecordNotFound when the user has no access at all.
 func (repo *TaskBoardRepositoryImpl) CheckUserRole(taskBoardID uuid.UUID, userID uuid.UUID) (*models.UserTaskBoard, error) {
-	var userTaskBoard *models.UserTaskBoard
-	err := repo.db.Where("task_board_id = ? AND user_id = ?", taskBoardID, userID).Preload("User").First(&userTaskBoard).Error
+	access, err := repo.resolveAccess(taskBoardID, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(access) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &access[0], nil
+}
+
+// FindCollaborator returns the user's own membership on the board, ignoring
+// team and workspace grants.
+func (repo *TaskBoardRepositoryImpl) FindCollaborator(taskBoardID uuid.UUID, userID uuid.UUID) (*models.UserTaskBoard, error) {
+	var userTaskBoard models.UserTaskBoard
+	err := repo.db.Where("task_board_id = ? AND user_id = ?", taskBoardID, userID).
+		Preload("User").Preload("BoardRole").
+		First(&userTaskBoard).Error
+	if err != nil {
+		return nil, err
+	}
+	return &userTaskBoard, nil
+}
+
+// FindCollaborators lists the board's own memberships, without team grants.
+func (repo *TaskBoardRepositoryImpl) FindCollaborators(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error) {
+	var userTaskBoards []models.UserTaskBoard
+	err := repo.db.Where("task_board_id = ?", taskBoardID).
+		Preload("User").Preload("BoardRole").
+		Order("created_at").
+		Find(&userTaskBoards).Error
 	if err != nil {
-		return userTaskBoard, err
+		return nil, err
+	}
+	return userTaskBoards, nil
+}
+
+// accessibleTo narrows a task board query to the boards the user is on,
+// reaches through a team or reaches through their workspace role.
+func accessibleTo(userID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`(task_boards.id IN (SELECT task_board_id FROM user_task_boards WHERE user_id = ?)
+			OR task_boards.id IN (
+				SELECT task_board_teams.task_board_id FROM task_board_teams
+				JOIN team_members ON team_members.team_id = task_board_teams.team_id
+				WHERE team_members.user_id = ?
+			)
+			OR task_boards.workspace_id IN (
+				SELECT workspace_members.workspace_id FROM workspace_members
+				JOIN workspaces ON workspaces.id = workspace_members.workspace_id
+				WHERE workspace_members.user_id = ?
+				AND (workspace_members.role = ? OR (workspace_members.role = ? AND workspaces.setting_default_board_role IN ?))
+			))`,
+			userID, userID, userID, policy.WorkspaceAdmin, policy.WorkspaceMember, []string{policy.RoleEditor, policy.RoleViewer})
 	}
-	
-	return userTaskBoard, nil
 }
 
+// GetUsersOnTaskBoard lists the board's collaborators and the members of the
+// teams granted on it, each with their resolved access. Users who only reach
+// the board through their workspace role are not listed.
 func (repo *TaskBoardRepositoryImpl) GetUsersOnTaskBoard(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error) {
-	var userTaskBoards []models.UserTaskBoard 
+	return repo.resolveAccess(taskBoardID, nil)
+}
 
-	err := repo.db.
-		Where("task_board_id = ?", taskBoardID).
-		Preload("User").
-		Preload("TaskBoard").
-		Find(&userTaskBoards).Error 
+// resolveAccess gathers every grant on the board for userIDs, or for every
+// collaborator and team member when userIDs is nil, and folds each user's
+// grants into one membership holding the most privileged role.
+func (repo *TaskBoardRepositoryImpl) resolveAccess(taskBoardID uuid.UUID, userIDs []uuid.UUID) ([]models.UserTaskBoard, error) {
+	direct := repo.db.Where("task_board_id = ?", taskBoardID).Preload("User").Preload("BoardRole")
+	if userIDs != nil {
+		direct = direct.Where("user_id IN ?", userIDs)
+	} else {
+		direct = direct.Preload("TaskBoard")
+	}
+	var collaborators []models.UserTaskBoard
+	if err := direct.Order("created_at").Find(&collaborators).Error; err != nil {
+		return nil, err
+	}
 
+	var teamGrants []struct {
+		UserID      uuid.UUID
+		TeamID      uuid.UUID
+		TeamName    string
+		Role        string
+		BoardRoleID *uuid.UUID
+	}
+	teams := repo.db.Table("task_board_teams").
+		Select("team_members.user_id, teams.id AS team_id, teams.name AS team_name, task_board_teams.role, task_board_teams.board_role_id").
+		Joins("JOIN teams ON teams.id = task_board_teams.team_id").
+		Joins("JOIN team_members ON team_members.team_id = teams.id").
+		Where("task_board_teams.task_board_id = ?", taskBoardID)
+	if userIDs != nil {
+		teams = teams.Where("team_members.user_id IN ?", userIDs)
+	}
+	if err := teams.Order("teams.name").Scan(&teamGrants).Error; err != nil {
+		return nil, err
+	}
+
+	access := make(map[uuid.UUID]*models.UserTaskBoard)
+	var order []uuid.UUID
+	entry := func(userID uuid.UUID) *models.UserTaskBoard {
+		if userTaskBoard, ok := access[userID]; ok {
+			return userTaskBoard
+		}
+		userTaskBoard := &models.UserTaskBoard{UserID: userID, TaskBoardID: taskBoardID, Grants: []models.BoardGrant{}}
+		access[userID] = userTaskBoard
+		order = append(order, userID)
+		return userTaskBoard
+	}
+
+	for i := range collaborators {
+		collaborator := collaborators[i]
+		collaborator.Grants = []models.BoardGrant{{Source: models.GrantDirect, Role: collaborator.Role, BoardRoleID: collaborator.BoardRoleID}}
+		access[collaborator.UserID] = &collaborator
+		order = append(order, collaborator.UserID)
+	}
+	for _, grant := range teamGrants {
+		teamID := grant.TeamID
+		userTaskBoard := entry(grant.UserID)
+		userTaskBoard.Grants = append(userTaskBoard.Grants, models.BoardGrant{
+			Source:      models.GrantTeam,
+			TeamID:      &teamID,
+			TeamName:    grant.TeamName,
+			Role:        grant.Role,
+			BoardRoleID: grant.BoardRoleID,
+		})
+	}
+
+	workspaceUsers := userIDs
+	if workspaceUsers == nil {
+		workspaceUsers = order
+	}
+	if len(workspaceUsers) > 0 {
+		var workspaceGrants []struct {
+			UserID           uuid.UUID
+			Role             string
+			DefaultBoardRole string
+		}
+		err := repo.db.Table("workspace_members").
+			Select("workspace_members.user_id, workspace_members.role, workspaces.setting_default_board_role AS default_board_role").
+			Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id").
+			Joins("JOIN task_boards ON task_boards.workspace_id = workspaces.id").
+			Where("task_boards.id = ? AND workspace_members.user_id IN ?", taskBoardID, workspaceUsers).
+			Scan(&workspaceGrants).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range workspaceGrants {
+			role := policy.WorkspaceBoardRole(grant.Role, grant.DefaultBoardRole)
+			if role == "" {
+				continue
+			}
+			userTaskBoard := entry(grant.UserID)
+			userTaskBoard.Grants = append(userTaskBoard.Grants, models.BoardGrant{Source: models.GrantWorkspace, Role: role})
+		}
+	}
+
+	if len(order) == 0 {
+		return []models.UserTaskBoard{}, nil
+	}
+
+	var boardRoles []models.BoardRole
+	if err := repo.db.Where("task_board_id = ?", taskBoardID).Find(&boardRoles).Error; err != nil {
+		return nil, err
+	}
+	rolesByID := make(map[uuid.UUID]*models.BoardRole, len(boardRoles))
+	for i := range boardRoles {
+		rolesByID[boardRoles[i].ID] = &boardRoles[i]
+	}
+
+	var missingUsers []uuid.UUID
+	for _, userID := range order {
+		if access[userID].User.ID == uuid.Nil {
+			missingUsers = append(missingUsers, userID)
+		}
+	}
+	if len(missingUsers) > 0 {
+		var users []models.User
+		if err := repo.db.Where("id IN ?", missingUsers).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			access[user.ID].User = user
+		}
+	}
+
+	resolved := make([]models.UserTaskBoard, 0, len(order))
+	for _, userID := range order {
+		userTaskBoard := access[userID]
+		foldGrants(userTaskBoard, rolesByID)
+		resolved = append(resolved, *userTaskBoard)
+	}
+	return resolved, nil
+}
+
+// foldGrants sets the membership's role to the most privileged of its grants
+// and collects the permissions of every custom role among them. Grants only
+// add up: an explicit membership does not take precedence over team or
+// workspace grants, so adding a workspace admin to a board as a viewer leaves
+// them an owner there.
+func foldGrants(userTaskBoard *models.UserTaskBoard, rolesByID map[uuid.UUID]*models.BoardRole) {
+	roles := make([]string, 0, len(userTaskBoard.Grants))
+	var customRole *models.BoardRole
+	seen := make(map[string]bool)
+	userTaskBoard.Permissions = []string{}
+	for _, grant := range userTaskBoard.Grants {
+		roles = append(roles, grant.Role)
+		if grant.Role != policy.RoleCustom || grant.BoardRoleID == nil {
+			continue
+		}
+		boardRole, ok := rolesByID[*grant.BoardRoleID]
+		if !ok {
+			continue
+		}
+		if customRole == nil {
+			customRole = boardRole
+		}
+		for _, permission := range boardRole.Permissions {
+			if !seen[permission] {
+				seen[permission] = true
+				userTaskBoard.Permissions = append(userTaskBoard.Permissions, permission)
+			}
+		}
+	}
+
+	userTaskBoard.Role = policy.MaxRole(roles...)
+	userTaskBoard.BoardRoleID = nil
+	userTaskBoard.BoardRole = nil
+	if userTaskBoard.Role == policy.RoleCustom && customRole != nil {
+		userTaskBoard.BoardRoleID = &customRole.ID
+		userTaskBoard.BoardRole = customRole
+	}
+}
+
+func (repo *TaskBoardRepositoryImpl) GetStatusLimits(taskBoardID uuid.UUID) ([]models.TaskBoardStatusLimit, error) {
+	var limits []models.TaskBoardStatusLimit
+	if err := repo.db.Where("task_board_id = ?", taskBoardID).Find(&limits).Error; err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// LockStatusLimit returns the column's WIP limit locked FOR UPDATE, so tasks
+// entering a limited column are counted one transaction at a time. It returns
+// nil without an error when the column has no WIP limit, and must be called
+// inside a transaction.
+func (repo *TaskBoardRepositoryImpl) LockStatusLimit(taskBoardID uuid.UUID, status string) (*models.TaskBoardStatusLimit, error) {
+	var limit models.TaskBoardStatusLimit
+	err := repo.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("task_board_id = ? AND status = ?", taskBoardID, status).
+		First(&limit).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
+	return &limit, nil
+}
 
-	return userTaskBoards, nil
+// SetStatusLimits replaces every WIP limit on the board with the given set.
+func (repo *TaskBoardRepositoryImpl) SetStatusLimits(taskBoardID uuid.UUID, limits []models.TaskBoardStatusLimit) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.TaskBoardStatusLimit{}, "task_board_id = ?", taskBoardID).Error; err != nil {
+			return err
+		}
+		if len(limits) == 0 {
+			return nil
+		}
+		for i := range limits {
+			limits[i].TaskBoardID = taskBoardID
+		}
+		return tx.Create(&limits).Error
+	})
+}
+
+func (repo *TaskBoardRepositoryImpl) CountTasksByStatus(taskBoardID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := repo.db.Model(&models.Task{}).
+		Select("status, COUNT(*) AS count").
+		Where("task_board_id = ? AND archived_at IS NULL", taskBoardID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// FindTaskRef loads just the columns needed to authorize access to a task:
+// its board and creator. Trashed tasks resolve too, so they can be
+// authorized for restore.
+func (repo *TaskBoardRepositoryImpl) FindTaskRef(taskID uuid.UUID) (*models.Task, error) {
+	var task models.Task
+	err := repo.db.Unscoped().
+		Select("id", "task_board_id", "created_by_id").
+		First(&task, "id = ?", taskID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// FindDeletedByOwnerID lists the soft-deleted boards the user owns.
+func (repo *TaskBoardRepositoryImpl) FindDeletedByOwnerID(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	var taskBoards []models.TaskBoard
+	err := repo.db.Unscoped().
+		Joins("JOIN user_task_boards ON task_boards.id = user_task_boards.task_board_id").
+		Where("user_task_boards.user_id = ? AND user_task_boards.role = ?", userID, "owner").
+		Where("task_boards.deleted_at IS NOT NULL").
+		Scopes(params.Scope).
+		Find(&taskBoards).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, taskBoards)
+}
+
+// FindDeletedTasks lists the board's soft-deleted tasks, most recent first
+// by default.
+func (repo *TaskBoardRepositoryImpl) FindDeletedTasks(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Task, *pagination.Page, error) {
+	var tasks []models.Task
+	err := repo.db.Unscoped().
+		Where("tasks.task_board_id = ? AND tasks.deleted_at IS NOT NULL", taskBoardID).
+		Scopes(params.Scope).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, tasks)
+}
+
+// SetArchived archives the board, or unarchives it when archivedAt is nil.
+func (repo *TaskBoardRepositoryImpl) SetArchived(taskBoardID uuid.UUID, archivedAt *time.Time) error {
+	result := repo.db.Model(&models.TaskBoard{}).Where("id = ?", taskBoardID).Update("archived_at", archivedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task board not found")
+	}
+	return nil
 }
 
+// FindDeletedByID finds a board in the trash.
+func (repo *TaskBoardRepositoryImpl) FindDeletedByID(taskBoardID uuid.UUID) (*models.TaskBoard, error) {
+	var taskBoard models.TaskBoard
+	err := repo.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", taskBoardID).First(&taskBoard).Error
+	if err != nil {
+		return nil, err
+	}
+	return &taskBoard, nil
+}
 
+// Restore brings a board back from the trash or the archive.
+func (repo *TaskBoardRepositoryImpl) Restore(taskBoardID uuid.UUID) error {
+	result := repo.db.Unscoped().Model(&models.TaskBoard{}).Where("id = ?", taskBoardID).
+		Updates(map[string]interface{}{"deleted_at": nil, "archived_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task board not found")
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes boards deleted before the cutoff along
+// with their tasks, memberships and WIP limits. Other board data goes with
+// the board through ON DELETE CASCADE.
+func (repo *TaskBoardRepositoryImpl) PurgeDeleted(before time.Time) (int64, error) {
+	var purged int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&models.TaskBoard{}).Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+
+		if err := tx.Unscoped().Where("task_board_id IN (?)", expired).Delete(&models.Task{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_board_id IN (?)", expired).Delete(&models.UserTaskBoard{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_board_id IN (?)", expired).Delete(&models.TaskBoardStatusLimit{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&models.TaskBoard{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
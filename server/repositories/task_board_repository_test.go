@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"reflect"
+	"server/models"
+	"server/policy"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFoldGrants(t *testing.T) {
+	triage := &models.BoardRole{ID: uuid.New(), Name: "triage", Permissions: models.StringList{"view", "comment"}}
+	reviewer := &models.BoardRole{ID: uuid.New(), Name: "reviewer", Permissions: models.StringList{"comment", "edit_any_task"}}
+	rolesByID := map[uuid.UUID]*models.BoardRole{triage.ID: triage, reviewer.ID: reviewer}
+	teamID := uuid.New()
+
+	tests := []struct {
+		name            string
+		grants          []models.BoardGrant
+		wantRole        string
+		wantBoardRole   *models.BoardRole
+		wantPermissions []string
+	}{
+		{
+			name:            "direct membership alone",
+			grants:          []models.BoardGrant{{Source: models.GrantDirect, Role: policy.RoleEditor}},
+			wantRole:        policy.RoleEditor,
+			wantPermissions: []string{},
+		},
+		{
+			name: "explicit viewer membership does not lower a workspace admin",
+			grants: []models.BoardGrant{
+				{Source: models.GrantDirect, Role: policy.RoleViewer},
+				{Source: models.GrantWorkspace, Role: policy.RoleOwner},
+			},
+			wantRole:        policy.RoleOwner,
+			wantPermissions: []string{},
+		},
+		{
+			name: "team grant raises a direct viewer",
+			grants: []models.BoardGrant{
+				{Source: models.GrantDirect, Role: policy.RoleViewer},
+				{Source: models.GrantTeam, TeamID: &teamID, Role: policy.RoleEditor},
+			},
+			wantRole:        policy.RoleEditor,
+			wantPermissions: []string{},
+		},
+		{
+			name: "preset role outranks a custom one, whose permissions are kept",
+			grants: []models.BoardGrant{
+				{Source: models.GrantDirect, Role: policy.RoleCustom, BoardRoleID: &reviewer.ID},
+				{Source: models.GrantWorkspace, Role: policy.RoleViewer},
+			},
+			wantRole:        policy.RoleViewer,
+			wantPermissions: []string{"comment", "edit_any_task"},
+		},
+		{
+			name: "custom roles join their permissions",
+			grants: []models.BoardGrant{
+				{Source: models.GrantDirect, Role: policy.RoleCustom, BoardRoleID: &triage.ID},
+				{Source: models.GrantTeam, TeamID: &teamID, Role: policy.RoleCustom, BoardRoleID: &reviewer.ID},
+			},
+			wantRole:        policy.RoleCustom,
+			wantBoardRole:   triage,
+			wantPermissions: []string{"view", "comment", "edit_any_task"},
+		},
+		{
+			name:            "deleted custom role grants nothing",
+			grants:          []models.BoardGrant{{Source: models.GrantDirect, Role: policy.RoleCustom, BoardRoleID: ptr(uuid.New())}},
+			wantRole:        policy.RoleCustom,
+			wantPermissions: []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userTaskBoard := &models.UserTaskBoard{Role: "stale", Grants: test.grants}
+			foldGrants(userTaskBoard, rolesByID)
+
+			if userTaskBoard.Role != test.wantRole {
+				t.Errorf("Role = %q, want %q", userTaskBoard.Role, test.wantRole)
+			}
+			if userTaskBoard.BoardRole != test.wantBoardRole {
+				t.Errorf("BoardRole = %v, want %v", userTaskBoard.BoardRole, test.wantBoardRole)
+			}
+			if !reflect.DeepEqual(userTaskBoard.Permissions, test.wantPermissions) {
+				t.Errorf("Permissions = %v, want %v", userTaskBoard.Permissions, test.wantPermissions)
+			}
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
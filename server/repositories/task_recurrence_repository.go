@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"errors"
+	"server/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// recurringTasksLockKey identifies the Postgres advisory lock held while the
+// recurring task scheduler runs, so only one replica creates instances at a time.
+const recurringTasksLockKey int64 = 2820260001
+
+type TaskRecurrenceRepository interface {
+	WithTx(tx *gorm.DB) TaskRecurrenceRepository
+	FindByTaskID(taskID uuid.UUID) (*models.TaskRecurrence, error)
+	Save(recurrence *models.TaskRecurrence) (*models.TaskRecurrence, error)
+	DeleteByTaskID(taskID uuid.UUID) error
+	FindDue(now time.Time) ([]models.TaskRecurrence, error)
+	WithSchedulerLock(fn func(tx *gorm.DB) error) (bool, error)
+}
+
+type TaskRecurrenceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTaskRecurrenceRepository(db *gorm.DB) *TaskRecurrenceRepositoryImpl {
+	return &TaskRecurrenceRepositoryImpl{db: db}
+}
+
+func (repo *TaskRecurrenceRepositoryImpl) WithTx(tx *gorm.DB) TaskRecurrenceRepository {
+	return &TaskRecurrenceRepositoryImpl{db: tx}
+}
+
+// FindByTaskID returns nil without an error when the task does not recur.
+func (repo *TaskRecurrenceRepositoryImpl) FindByTaskID(taskID uuid.UUID) (*models.TaskRecurrence, error) {
+	var recurrence models.TaskRecurrence
+	if err := repo.db.First(&recurrence, "task_id = ?", taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+func (repo *TaskRecurrenceRepositoryImpl) Save(recurrence *models.TaskRecurrence) (*models.TaskRecurrence, error) {
+	if err := repo.db.Omit(clause.Associations).Save(recurrence).Error; err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+func (repo *TaskRecurrenceRepositoryImpl) DeleteByTaskID(taskID uuid.UUID) error {
+	return repo.db.Delete(&models.TaskRecurrence{}, "task_id = ?", taskID).Error
+}
+
+// FindDue returns series whose next instance is scheduled by now or whose
+// current instance has already been completed.
+func (repo *TaskRecurrenceRepositoryImpl) FindDue(now time.Time) ([]models.TaskRecurrence, error) {
+	var recurrences []models.TaskRecurrence
+	err := repo.db.
+		Joins("JOIN tasks ON tasks.id = task_recurrences.task_id").
+		Joins("JOIN task_boards ON task_boards.id = tasks.task_board_id").
+		Where("task_boards.is_template = ?", false).
+		Where("task_boards.archived_at IS NULL AND task_boards.deleted_at IS NULL").
+		Where("tasks.archived_at IS NULL AND tasks.deleted_at IS NULL").
+		Where("task_recurrences.next_run_at IS NOT NULL").
+		Where("task_recurrences.next_run_at <= ? OR tasks.status = ?", now, "done").
+		Preload("Task").
+		Preload("Task.CustomFieldValues").
+		Preload("Task.Labels").
+		Find(&recurrences).Error
+	if err != nil {
+		return nil, err
+	}
+	return recurrences, nil
+}
+
+// WithSchedulerLock runs fn in a transaction holding the scheduler's advisory
+// lock. It reports false without running fn when another instance holds it.
+func (repo *TaskRecurrenceRepositoryImpl) WithSchedulerLock(fn func(tx *gorm.DB) error) (bool, error) {
+	acquired := false
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", recurringTasksLockKey).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		return fn(tx)
+	})
+	return acquired, err
+}
@@ -5,17 +5,45 @@ import (
 	"log"
 	"server/dto"
 	"server/models"
+	"server/pagination"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 
+// TaskMove describes where a task goes when it changes boards, along with
+// the board-scoped data that replaces what it had on the old board.
+type TaskMove struct {
+	TaskBoardID uuid.UUID
+	Status      string
+	Values      []models.TaskCustomFieldValue
+	Labels      []models.Label
+	AssigneeID  *uuid.UUID
+	// ActorID is who moved the task, for its timeline.
+	ActorID     uuid.UUID
+}
+
 type TaskRepository interface {
+	WithTx(tx *gorm.DB) TaskRepository
 	Create(task *models.Task) (*models.Task, error)
 	FindByID(taskID uuid.UUID) (*models.Task, error)
-	Update(taskID uuid.UUID, task *models.Task) (*models.Task, error)
+	Update(taskID uuid.UUID, task *models.Task, actorID uuid.UUID) (*models.Task, error)
 	Delete(taskID uuid.UUID) error
+	CountByStatus(taskBoardID uuid.UUID, status string) (int64, error)
+	Move(taskID uuid.UUID, move TaskMove) (*models.Task, error)
+	UpdateFields(taskID uuid.UUID, fields map[string]interface{}) error
+	SetAssignee(taskID uuid.UUID, assigneeID *uuid.UUID, actorID uuid.UUID) error
+	ClearEstimate(taskID uuid.UUID, actorID uuid.UUID) error
+	ReplaceLabels(taskID uuid.UUID, labels []models.Label) error
+	AddLabels(taskID uuid.UUID, labels []models.Label) error
+	RemoveLabels(taskID uuid.UUID, labelIDs []uuid.UUID) error
+	FindDeletedByID(taskID uuid.UUID) (*models.Task, error)
+	SetArchived(taskID uuid.UUID, archivedAt *time.Time) error
+	Restore(taskID uuid.UUID) error
+	PurgeDeleted(before time.Time) (int64, error)
+	Search(taskSearch TaskSearch, params *pagination.Params) ([]models.TaskSearchHit, *pagination.Page, error)
 }
 
 type TaskRepositoryImpl struct {
@@ -26,8 +54,23 @@ func NewTaskRepository(db *gorm.DB) *TaskRepositoryImpl {
 	return &TaskRepositoryImpl{db: db}
 }
 
+func (repo *TaskRepositoryImpl) WithTx(tx *gorm.DB) TaskRepository {
+	return &TaskRepositoryImpl{db: tx}
+}
+
 func (repo *TaskRepositoryImpl) Create(task *models.Task) (*models.Task, error) {
-	if err := repo.db.Create(task).Error; err != nil {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.TaskActivity{
+			TaskID:      task.ID,
+			TaskBoardID: task.TaskBoardID,
+			ActorID:     task.CreatedByID,
+			Kind:        models.ActivityCreated,
+		}).Error
+	})
+	if err != nil {
         log.Printf("Error creating task board in database: %v", err)
 		return nil, fmt.Errorf("error creating task board in database: %w", err)
     }
@@ -39,7 +82,8 @@ func (repo *TaskRepositoryImpl) Create(task *models.Task) (*models.Task, error)
 
 func (repo *TaskRepositoryImpl) FindByID(taskID uuid.UUID) (*models.Task, error) {
 	var task models.Task
-	err := repo.db.Preload("TaskBoard").First(&task, "id = ?", taskID).Error
+	err := repo.db.Preload("TaskBoard").Preload("CustomFieldValues").Preload("Labels").Preload("Assignee").
+		First(&task, "id = ?", taskID).Error
 	if err != nil {
 		return nil, err 
 	}
@@ -58,14 +102,30 @@ func (repo *TaskRepositoryImpl) SortByStatusAndPriority(sortByPriorityAndStatus
 }
 
 
-func (repo *TaskRepositoryImpl) Update(taskID uuid.UUID, task *models.Task) (*models.Task, error) {
-	err := repo.db.Model(&models.Task{}).Where("id = ?", taskID).Updates(task).Error
+// Update writes the task's non-zero fields and records each field it changed
+// on the task's timeline as done by actorID.
+func (repo *TaskRepositoryImpl) Update(taskID uuid.UUID, task *models.Task, actorID uuid.UUID) (*models.Task, error) {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Task
+		if err := tx.First(&before, "id = ?", taskID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Task{}).Where("id = ?", taskID).Updates(task).Error; err != nil {
+			return err
+		}
+		var after models.Task
+		if err := tx.First(&after, "id = ?", taskID).Error; err != nil {
+			return err
+		}
+		return recordTaskChanges(tx, &before, &after, actorID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	var updatedTask models.Task
-	if err := repo.db.Preload("TaskBoard").First(&updatedTask, "id = ?", taskID).Error; err != nil {
+	if err := repo.db.Preload("TaskBoard").Preload("CustomFieldValues").Preload("Labels").Preload("Assignee").
+		First(&updatedTask, "id = ?", taskID).Error; err != nil {
 		return nil, err
 	}
 	return &updatedTask, nil
@@ -82,3 +142,161 @@ func (repo *TaskRepositoryImpl) Delete(taskID uuid.UUID) error {
 	}
 	return nil
 }
+
+func (repo *TaskRepositoryImpl) CountByStatus(taskBoardID uuid.UUID, status string) (int64, error) {
+	var count int64
+	err := repo.db.Model(&models.Task{}).
+		Where("task_board_id = ? AND status = ? AND archived_at IS NULL", taskBoardID, status).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Move reassigns a task to another board and swaps its custom field values,
+// labels and assignee for the target board's in one transaction, recording
+// the changes on the task's timeline.
+func (repo *TaskRepositoryImpl) Move(taskID uuid.UUID, move TaskMove) (*models.Task, error) {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Task
+		if err := tx.First(&before, "id = ?", taskID).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.Task{}).Where("id = ?", taskID).
+			Updates(map[string]interface{}{
+				"task_board_id": move.TaskBoardID,
+				"status":        move.Status,
+				"assignee_id":   move.AssigneeID,
+			}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Task{ID: taskID}).Association("Labels").Replace(move.Labels); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.TaskCustomFieldValue{}, "task_id = ?", taskID).Error; err != nil {
+			return err
+		}
+		var after models.Task
+		if err := tx.First(&after, "id = ?", taskID).Error; err != nil {
+			return err
+		}
+		if err := recordTaskChanges(tx, &before, &after, move.ActorID); err != nil {
+			return err
+		}
+		if len(move.Values) == 0 {
+			return nil
+		}
+		for i := range move.Values {
+			move.Values[i].TaskID = taskID
+		}
+		return tx.Create(&move.Values).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindByID(taskID)
+}
+
+// UpdateFields writes the given columns, including zero values, without
+// touching the task's associations.
+func (repo *TaskRepositoryImpl) UpdateFields(taskID uuid.UUID, fields map[string]interface{}) error {
+	result := repo.db.Model(&models.Task{}).Where("id = ?", taskID).Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task not found")
+	}
+	return nil
+}
+
+// SetAssignee assigns the task, or unassigns it when assigneeID is nil, and
+// records the change on the task's timeline as done by actorID.
+func (repo *TaskRepositoryImpl) SetAssignee(taskID uuid.UUID, assigneeID *uuid.UUID, actorID uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Task
+		if err := tx.First(&before, "id = ?", taskID).Error; err != nil {
+			return err
+		}
+		txRepo := &TaskRepositoryImpl{db: tx}
+		if err := txRepo.UpdateFields(taskID, map[string]interface{}{"assignee_id": assigneeID}); err != nil {
+			return err
+		}
+		after := before
+		after.AssigneeID = assigneeID
+		return recordTaskChanges(tx, &before, &after, actorID)
+	})
+}
+
+// ClearEstimate removes the task's estimate and records the change on the
+// task's timeline as done by actorID.
+func (repo *TaskRepositoryImpl) ClearEstimate(taskID uuid.UUID, actorID uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Task
+		if err := tx.First(&before, "id = ?", taskID).Error; err != nil {
+			return err
+		}
+		txRepo := &TaskRepositoryImpl{db: tx}
+		if err := txRepo.UpdateFields(taskID, map[string]interface{}{"estimate_minutes": nil}); err != nil {
+			return err
+		}
+		after := before
+		after.EstimateMinutes = nil
+		return recordTaskChanges(tx, &before, &after, actorID)
+	})
+}
+
+func (repo *TaskRepositoryImpl) ReplaceLabels(taskID uuid.UUID, labels []models.Label) error {
+	return repo.db.Model(&models.Task{ID: taskID}).Association("Labels").Replace(labels)
+}
+
+func (repo *TaskRepositoryImpl) AddLabels(taskID uuid.UUID, labels []models.Label) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	return repo.db.Model(&models.Task{ID: taskID}).Association("Labels").Append(labels)
+}
+
+func (repo *TaskRepositoryImpl) RemoveLabels(taskID uuid.UUID, labelIDs []uuid.UUID) error {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+	return repo.db.Exec("DELETE FROM task_labels WHERE task_id = ? AND label_id IN ?", taskID, labelIDs).Error
+}
+
+// FindDeletedByID loads a task from the trash.
+func (repo *TaskRepositoryImpl) FindDeletedByID(taskID uuid.UUID) (*models.Task, error) {
+	var task models.Task
+	err := repo.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", taskID).First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SetArchived archives the task, or unarchives it when archivedAt is nil.
+func (repo *TaskRepositoryImpl) SetArchived(taskID uuid.UUID, archivedAt *time.Time) error {
+	return repo.UpdateFields(taskID, map[string]interface{}{"archived_at": archivedAt})
+}
+
+// Restore brings a task back from the trash or the archive.
+func (repo *TaskRepositoryImpl) Restore(taskID uuid.UUID) error {
+	result := repo.db.Unscoped().Model(&models.Task{}).Where("id = ?", taskID).
+		Updates(map[string]interface{}{"deleted_at": nil, "archived_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task not found")
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes tasks deleted before the cutoff; their
+// values, labels, reminders, recurrences, time entries and activity cascade.
+func (repo *TaskRepositoryImpl) PurgeDeleted(before time.Time) (int64, error) {
+	result := repo.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Delete(&models.Task{})
+	return result.RowsAffected, result.Error
+}
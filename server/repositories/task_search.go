@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+	"server/search"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// searchConfig is the text search configuration of the tasks.search_vector
+// column; queries must use the same one to match it.
+const searchConfig = "english"
+
+// TaskSearch runs a parsed query over the tasks of TaskBoardIDs. ActorID
+// resolves assignee:me and Now anchors relative dates.
+type TaskSearch struct {
+	Query        *search.Query
+	TaskBoardIDs []uuid.UUID
+	ActorID      uuid.UUID
+	Now          time.Time
+}
+
+// TaskSearchPages is how search results page. Matches are ranked in a
+// subquery, so the keys read its columns.
+var TaskSearchPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "hits.id"},
+		"rank":       {Column: "hits.rank"},
+		"title":      {Column: "hits.title"},
+		"created_at": {Column: "hits.created_at"},
+		"updated_at": {Column: "hits.updated_at"},
+	},
+	Default: "-rank,-updated_at",
+	Unique:  "id",
+	Model:   models.TaskSearchHit{},
+}
+
+// rankedTaskID is a match before its task is loaded.
+type rankedTaskID struct {
+	ID   uuid.UUID
+	Rank float64
+}
+
+// Search returns one page of matching tasks, best ranked first unless
+// sorted otherwise, with the total number of matches on the page. Free text
+// is matched with full-text search over title and description; without text,
+// the most recently updated come first.
+func (repo *TaskRepositoryImpl) Search(taskSearch TaskSearch, params *pagination.Params) ([]models.TaskSearchHit, *pagination.Page, error) {
+	var total int64
+	if len(taskSearch.TaskBoardIDs) == 0 {
+		return repo.searchPage(params, []models.TaskSearchHit{}, total)
+	}
+
+	conditions := compileTaskSearch(taskSearch)
+
+	if err := repo.db.Model(&models.Task{}).Scopes(conditions).Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	rank, rankArgs := searchRank(taskSearch.Query.Text)
+	ranked := repo.db.Model(&models.Task{}).Scopes(conditions).
+		Select("tasks.id, tasks.title, tasks.created_at, tasks.updated_at, "+rank+" AS rank", rankArgs...)
+	var hits []rankedTaskID
+	err := repo.db.Table("(?) AS hits", ranked).
+		Select("hits.id, hits.rank").
+		Scopes(params.Scope).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(hits) == 0 {
+		return repo.searchPage(params, []models.TaskSearchHit{}, total)
+	}
+
+	ids := make([]uuid.UUID, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	var tasks []models.Task
+	err = repo.db.Preload("TaskBoard").Preload("Labels").Preload("Assignee").
+		Where("id IN ?", ids).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uuid.UUID]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	results := make([]models.TaskSearchHit, 0, len(hits))
+	for _, hit := range hits {
+		if task, ok := byID[hit.ID]; ok {
+			results = append(results, models.TaskSearchHit{Task: task, Rank: hit.Rank})
+		}
+	}
+	return repo.searchPage(params, results, total)
+}
+
+func (repo *TaskRepositoryImpl) searchPage(params *pagination.Params, results []models.TaskSearchHit, total int64) ([]models.TaskSearchHit, *pagination.Page, error) {
+	results, page, err := pagination.Cut(params, results)
+	if err != nil {
+		return nil, nil, err
+	}
+	page.Total = &total
+	return results, page, nil
+}
+
+// compileTaskSearch turns the query into WHERE clauses. Every value reaches
+// the database as a bind parameter; only fixed SQL fragments are written
+// into the statement.
+func compileTaskSearch(taskSearch TaskSearch) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		query := taskSearch.Query
+		db = db.Where("tasks.task_board_id IN ?", taskSearch.TaskBoardIDs)
+		if query.Has(search.FieldIs, search.StateArchived) {
+			db = db.Where("tasks.archived_at IS NOT NULL")
+		} else {
+			db = db.Where("tasks.archived_at IS NULL")
+		}
+
+		for _, filter := range query.Filters {
+			condition, args := compileFilter(filter, taskSearch)
+			if condition == "" {
+				continue
+			}
+			if filter.Negate {
+				condition = negate(condition)
+			}
+			db = db.Where(condition, args...)
+		}
+
+		for _, text := range query.Text {
+			condition := "tasks.search_vector @@ " + tsQuery(text)
+			if text.Negate {
+				condition = negate(condition)
+			}
+			db = db.Where(condition, text.Value)
+		}
+		return db
+	}
+}
+
+// compileFilter returns the condition a filter adds, matching any of its
+// values, or "" when it adds none.
+func compileFilter(filter search.Filter, taskSearch TaskSearch) (string, []interface{}) {
+	switch filter.Field {
+	case search.FieldStatus:
+		return "tasks.status IN ?", []interface{}{filter.Values}
+	case search.FieldPriority:
+		return "tasks.priority IN ?", []interface{}{filter.Values}
+	case search.FieldBoard:
+		return "tasks.task_board_id IN ?", []interface{}{filter.Boards}
+	case search.FieldLabel:
+		return `EXISTS (SELECT 1 FROM task_labels
+			JOIN labels ON labels.id = task_labels.label_id
+			WHERE task_labels.task_id = tasks.id AND LOWER(labels.name) IN ?)`, []interface{}{filter.Values}
+	case search.FieldAssignee:
+		var conditions []string
+		var args []interface{}
+		var emails []string
+		for _, value := range filter.Values {
+			switch value {
+			case search.AssigneeMe:
+				conditions = append(conditions, "tasks.assignee_id = ?")
+				args = append(args, taskSearch.ActorID)
+			case search.AssigneeNone:
+				conditions = append(conditions, "tasks.assignee_id IS NULL")
+			default:
+				emails = append(emails, value)
+			}
+		}
+		if len(filter.Assignees) > 0 {
+			conditions = append(conditions, "tasks.assignee_id IN ?")
+			args = append(args, filter.Assignees)
+		}
+		if len(emails) > 0 {
+			conditions = append(conditions, "tasks.assignee_id IN (SELECT id FROM users WHERE LOWER(email) IN ?)")
+			args = append(args, emails)
+		}
+		return strings.Join(conditions, " OR "), args
+	case search.FieldIs:
+		var conditions []string
+		var args []interface{}
+		for _, value := range filter.Values {
+			switch value {
+			case search.StateOverdue:
+				conditions = append(conditions, "(tasks.end_date < ? AND tasks.status <> ?)")
+				args = append(args, taskSearch.Now, "done")
+			case search.StateUnassigned:
+				conditions = append(conditions, "tasks.assignee_id IS NULL")
+			}
+			// is:archived picks the archive scope in compileTaskSearch
+		}
+		return strings.Join(conditions, " OR "), args
+	case search.FieldDue:
+		return compileBound("tasks.end_date", filter, taskSearch.Now, false)
+	case search.FieldCreated:
+		return compileBound("tasks.created_at", filter, taskSearch.Now, true)
+	}
+	return "", nil
+}
+
+// compileBound compares column with the filter's bound. A date compares by
+// whole days. A relative bound lies ahead of now, or behind it when past is
+// set, in which case created:<7d reads "less than 7 days old".
+func compileBound(column string, filter search.Filter, now time.Time, past bool) (string, []interface{}) {
+	bound := filter.Bound
+	if bound.IsRelative {
+		op := filter.Op
+		point := now.Add(bound.Relative)
+		if past {
+			point = now.Add(-bound.Relative)
+			op = flip(op)
+		}
+		return column + " " + string(op) + " ?", []interface{}{point}
+	}
+
+	start := bound.Date
+	end := start.AddDate(0, 0, 1)
+	switch filter.Op {
+	case search.OpLt:
+		return column + " < ?", []interface{}{start}
+	case search.OpLte:
+		return column + " < ?", []interface{}{end}
+	case search.OpGt:
+		return column + " >= ?", []interface{}{end}
+	case search.OpGte:
+		return column + " >= ?", []interface{}{start}
+	default:
+		return column + " >= ? AND " + column + " < ?", []interface{}{start, end}
+	}
+}
+
+// negate inverts a condition, counting a NULL result as false so that, say,
+// -assignee:me still matches unassigned tasks.
+func negate(condition string) string {
+	return "NOT COALESCE((" + condition + "), FALSE)"
+}
+
+func flip(op search.Op) search.Op {
+	switch op {
+	case search.OpLt:
+		return search.OpGt
+	case search.OpLte:
+		return search.OpGte
+	case search.OpGt:
+		return search.OpLt
+	case search.OpGte:
+		return search.OpLte
+	}
+	return op
+}
+
+// tsQuery is the query function for a text term, taking its value as the
+// single bind parameter.
+func tsQuery(text search.Text) string {
+	if text.Phrase {
+		return "phraseto_tsquery('" + searchConfig + "', ?)"
+	}
+	return "plainto_tsquery('" + searchConfig + "', ?)"
+}
+
+// searchRank ranks by the positive text terms, or returns a constant rank
+// when there are none.
+func searchRank(texts []search.Text) (string, []interface{}) {
+	var queries []string
+	var args []interface{}
+	for _, text := range texts {
+		if text.Negate {
+			continue
+		}
+		queries = append(queries, tsQuery(text))
+		args = append(args, text.Value)
+	}
+	if len(queries) == 0 {
+		return "0", nil
+	}
+	return "ts_rank(tasks.search_vector, " + strings.Join(queries, " && ") + ")", args
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TeamRepository interface {
+	WithTx(tx *gorm.DB) TeamRepository
+	Create(team *models.Team) (*models.Team, error)
+	FindByID(teamID uuid.UUID) (*models.Team, error)
+	FindByWorkspaceID(workspaceID uuid.UUID, params *pagination.Params) ([]models.Team, *pagination.Page, error)
+	Update(team *models.Team) (*models.Team, error)
+	Delete(teamID uuid.UUID) error
+	FindMembers(teamID uuid.UUID, params *pagination.Params) ([]models.TeamMember, *pagination.Page, error)
+	AddMember(teamID uuid.UUID, userID uuid.UUID) (*models.TeamMember, error)
+	RemoveMember(teamID uuid.UUID, userID uuid.UUID) error
+	FindBoardGrants(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskBoardTeam, *pagination.Page, error)
+	FindBoardGrant(taskBoardID uuid.UUID, teamID uuid.UUID) (*models.TaskBoardTeam, error)
+	GrantBoard(grant *models.TaskBoardTeam) (*models.TaskBoardTeam, error)
+	UpdateBoardGrant(taskBoardID uuid.UUID, teamID uuid.UUID, role string, boardRoleID *uuid.UUID) error
+	RevokeBoard(taskBoardID uuid.UUID, teamID uuid.UUID) error
+}
+
+type TeamRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTeamRepository(db *gorm.DB) *TeamRepositoryImpl {
+	return &TeamRepositoryImpl{db: db}
+}
+
+func (repo *TeamRepositoryImpl) WithTx(tx *gorm.DB) TeamRepository {
+	return &TeamRepositoryImpl{db: tx}
+}
+
+func (repo *TeamRepositoryImpl) Create(team *models.Team) (*models.Team, error) {
+	if err := repo.db.Create(team).Error; err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (repo *TeamRepositoryImpl) FindByID(teamID uuid.UUID) (*models.Team, error) {
+	var team models.Team
+	if err := repo.db.First(&team, "id = ?", teamID).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// TeamPages is how a workspace's teams page, by name unless asked otherwise.
+var TeamPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "teams.id"},
+		"name":       {Column: "teams.name"},
+		"created_at": {Column: "teams.created_at"},
+	},
+	Default: "name",
+	Unique:  "id",
+	Model:   models.Team{},
+}
+
+// FindByWorkspaceID lists the workspace's teams with their members.
+func (repo *TeamRepositoryImpl) FindByWorkspaceID(workspaceID uuid.UUID, params *pagination.Params) ([]models.Team, *pagination.Page, error) {
+	var teams []models.Team
+	err := repo.db.Preload("Members.User").
+		Where("teams.workspace_id = ?", workspaceID).
+		Scopes(params.Scope).
+		Find(&teams).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, teams)
+}
+
+func (repo *TeamRepositoryImpl) Update(team *models.Team) (*models.Team, error) {
+	if err := repo.db.Model(team).Select("name").Updates(team).Error; err != nil {
+		return nil, err
+	}
+	return repo.FindByID(team.ID)
+}
+
+// Delete removes the team, its memberships and its board grants.
+func (repo *TeamRepositoryImpl) Delete(teamID uuid.UUID) error {
+	return repo.db.Delete(&models.Team{}, "id = ?", teamID).Error
+}
+
+// TeamMemberPages is how a team's members page, by name unless asked
+// otherwise.
+var TeamMemberPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"user_id": {Column: "team_members.user_id"},
+		"name": {
+			Column: "users.name",
+			Value:  func(row interface{}) interface{} { return row.(models.TeamMember).User.Name },
+		},
+		"created_at": {Column: "team_members.created_at"},
+	},
+	Default: "name",
+	Unique:  "user_id",
+	Model:   models.TeamMember{},
+}
+
+func (repo *TeamRepositoryImpl) FindMembers(teamID uuid.UUID, params *pagination.Params) ([]models.TeamMember, *pagination.Page, error) {
+	var members []models.TeamMember
+	err := repo.db.Preload("User").
+		Joins("JOIN users ON users.id = team_members.user_id").
+		Where("team_members.team_id = ?", teamID).
+		Scopes(params.Scope).
+		Find(&members).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, members)
+}
+
+func (repo *TeamRepositoryImpl) AddMember(teamID uuid.UUID, userID uuid.UUID) (*models.TeamMember, error) {
+	member := models.TeamMember{TeamID: teamID, UserID: userID}
+	if err := repo.db.Create(&member).Error; err != nil {
+		return nil, err
+	}
+	if err := repo.db.Preload("User").First(&member, "team_id = ? AND user_id = ?", teamID, userID).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (repo *TeamRepositoryImpl) RemoveMember(teamID uuid.UUID, userID uuid.UUID) error {
+	return repo.db.Delete(&models.TeamMember{}, "team_id = ? AND user_id = ?", teamID, userID).Error
+}
+
+// BoardTeamPages is how the teams granted a board page, in the order they
+// were granted unless asked otherwise.
+var BoardTeamPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"team_id":    {Column: "task_board_teams.team_id"},
+		"role":       {Column: "task_board_teams.role"},
+		"created_at": {Column: "task_board_teams.created_at"},
+	},
+	Default: "created_at",
+	Unique:  "team_id",
+	Model:   models.TaskBoardTeam{},
+}
+
+func (repo *TeamRepositoryImpl) FindBoardGrants(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskBoardTeam, *pagination.Page, error) {
+	var grants []models.TaskBoardTeam
+	err := repo.db.Preload("Team").Preload("BoardRole").
+		Where("task_board_teams.task_board_id = ?", taskBoardID).
+		Scopes(params.Scope).
+		Find(&grants).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, grants)
+}
+
+func (repo *TeamRepositoryImpl) FindBoardGrant(taskBoardID uuid.UUID, teamID uuid.UUID) (*models.TaskBoardTeam, error) {
+	var grant models.TaskBoardTeam
+	err := repo.db.Preload("Team").Preload("BoardRole").
+		Where("task_board_id = ? AND team_id = ?", taskBoardID, teamID).
+		First(&grant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func (repo *TeamRepositoryImpl) GrantBoard(grant *models.TaskBoardTeam) (*models.TaskBoardTeam, error) {
+	if err := repo.db.Create(grant).Error; err != nil {
+		return nil, err
+	}
+	return repo.FindBoardGrant(grant.TaskBoardID, grant.TeamID)
+}
+
+func (repo *TeamRepositoryImpl) UpdateBoardGrant(taskBoardID uuid.UUID, teamID uuid.UUID, role string, boardRoleID *uuid.UUID) error {
+	return repo.db.Model(&models.TaskBoardTeam{}).
+		Where("task_board_id = ? AND team_id = ?", taskBoardID, teamID).
+		Updates(map[string]interface{}{"role": role, "board_role_id": boardRoleID}).Error
+}
+
+func (repo *TeamRepositoryImpl) RevokeBoard(taskBoardID uuid.UUID, teamID uuid.UUID) error {
+	return repo.db.Delete(&models.TaskBoardTeam{}, "task_board_id = ? AND team_id = ?", taskBoardID, teamID).Error
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"server/models"
+	"server/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TimeEntryFilter scopes time reports; nil fields are not filtered on.
+type TimeEntryFilter struct {
+	TaskID      *uuid.UUID
+	TaskBoardID *uuid.UUID
+	UserID      *uuid.UUID
+	From        *time.Time
+	To          *time.Time
+}
+
+// timeGroupings maps a report dimension to its group key and label expressions.
+var timeGroupings = map[string]struct {
+	join  string
+	key   string
+	label string
+}{
+	"user":  {"JOIN users ON users.id = time_entries.user_id", "time_entries.user_id::text", "users.name"},
+	"task":  {"JOIN tasks ON tasks.id = time_entries.task_id", "time_entries.task_id::text", "tasks.title"},
+	"board": {"JOIN task_boards ON task_boards.id = time_entries.task_board_id", "time_entries.task_board_id::text", "task_boards.title"},
+	"date":  {"", "to_char(time_entries.date, 'YYYY-MM-DD')", "to_char(time_entries.date, 'YYYY-MM-DD')"},
+}
+
+type TimeEntryRepository interface {
+	Create(entry *models.TimeEntry) (*models.TimeEntry, error)
+	FindByID(entryID uuid.UUID) (*models.TimeEntry, error)
+	FindByTaskID(taskID uuid.UUID, params *pagination.Params) ([]models.TimeEntry, *pagination.Page, error)
+	FindRunningByUserID(userID uuid.UUID) (*models.TimeEntry, error)
+	Update(entry *models.TimeEntry) (*models.TimeEntry, error)
+	Delete(entryID uuid.UUID) error
+	Summarize(filter TimeEntryFilter, groupBy string) ([]models.TimeSummaryRow, error)
+	FindEntries(filter TimeEntryFilter) ([]models.TimeEntry, error)
+}
+
+type TimeEntryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) *TimeEntryRepositoryImpl {
+	return &TimeEntryRepositoryImpl{db: db}
+}
+
+func (repo *TimeEntryRepositoryImpl) Create(entry *models.TimeEntry) (*models.TimeEntry, error) {
+	if err := repo.db.Omit(clause.Associations).Create(entry).Error; err != nil {
+		return nil, err
+	}
+	return repo.FindByID(entry.ID)
+}
+
+func (repo *TimeEntryRepositoryImpl) FindByID(entryID uuid.UUID) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := repo.db.Preload("User").First(&entry, "id = ?", entryID).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// TimeEntryPages is how a task's time entries page, most recent day first
+// unless asked otherwise.
+var TimeEntryPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":               {Column: "time_entries.id"},
+		"date":             {Column: "time_entries.date"},
+		"duration_seconds": {Column: "time_entries.duration_seconds"},
+		"created_at":       {Column: "time_entries.created_at"},
+	},
+	Default: "-date,-created_at",
+	Unique:  "id",
+	Model:   models.TimeEntry{},
+}
+
+func (repo *TimeEntryRepositoryImpl) FindByTaskID(taskID uuid.UUID, params *pagination.Params) ([]models.TimeEntry, *pagination.Page, error) {
+	var entries []models.TimeEntry
+	err := repo.db.Preload("User").
+		Where("time_entries.task_id = ?", taskID).
+		Scopes(params.Scope).
+		Find(&entries).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, entries)
+}
+
+// FindRunningByUserID returns nil without an error when the user has no running timer.
+func (repo *TimeEntryRepositoryImpl) FindRunningByUserID(userID uuid.UUID) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := repo.db.Preload("User").
+		Where("user_id = ? AND started_at IS NOT NULL AND ended_at IS NULL", userID).
+		First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (repo *TimeEntryRepositoryImpl) Update(entry *models.TimeEntry) (*models.TimeEntry, error) {
+	err := repo.db.Model(&models.TimeEntry{}).Where("id = ?", entry.ID).
+		Select("date", "duration_seconds", "ended_at", "note").
+		Updates(entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindByID(entry.ID)
+}
+
+func (repo *TimeEntryRepositoryImpl) Delete(entryID uuid.UUID) error {
+	return repo.db.Delete(&models.TimeEntry{}, "id = ?", entryID).Error
+}
+
+// Summarize totals finished entries per group. Running timers are left out
+// until they are stopped.
+func (repo *TimeEntryRepositoryImpl) Summarize(filter TimeEntryFilter, groupBy string) ([]models.TimeSummaryRow, error) {
+	grouping, ok := timeGroupings[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("cannot group time by %s", groupBy)
+	}
+
+	query := repo.filtered(filter).Model(&models.TimeEntry{})
+	if grouping.join != "" {
+		query = query.Joins(grouping.join)
+	}
+
+	var rows []models.TimeSummaryRow
+	err := query.
+		Select(fmt.Sprintf("%s AS key, %s AS label, SUM(time_entries.duration_seconds) AS seconds, COUNT(*) AS entries", grouping.key, grouping.label)).
+		Group(grouping.key).
+		Group(grouping.label).
+		Order("key").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (repo *TimeEntryRepositoryImpl) FindEntries(filter TimeEntryFilter) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	err := repo.filtered(filter).
+		Preload("User").
+		Preload("Task").
+		Preload("TaskBoard").
+		Order("time_entries.date, time_entries.created_at").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (repo *TimeEntryRepositoryImpl) filtered(filter TimeEntryFilter) *gorm.DB {
+	query := repo.db.Where("time_entries.started_at IS NULL OR time_entries.ended_at IS NOT NULL")
+	if filter.TaskID != nil {
+		query = query.Where("time_entries.task_id = ?", *filter.TaskID)
+	}
+	if filter.TaskBoardID != nil {
+		query = query.Where("time_entries.task_board_id = ?", *filter.TaskBoardID)
+	}
+	if filter.UserID != nil {
+		query = query.Where("time_entries.user_id = ?", *filter.UserID)
+	}
+	if filter.From != nil {
+		query = query.Where("time_entries.date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("time_entries.date <= ?", *filter.To)
+	}
+	return query
+}
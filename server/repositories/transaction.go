@@ -0,0 +1,21 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// Transactor runs work that spans several repositories in one database
+// transaction. Repositories join it through their WithTx methods.
+type Transactor interface {
+	Transaction(fn func(tx *gorm.DB) error) error
+}
+
+type TransactorImpl struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) *TransactorImpl {
+	return &TransactorImpl{db: db}
+}
+
+func (t *TransactorImpl) Transaction(fn func(tx *gorm.DB) error) error {
+	return t.db.Transaction(fn)
+}
@@ -3,14 +3,16 @@ package repositories
 import (
 	"errors"
 	"server/models"
+	"server/pagination"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
+	WithTx(tx *gorm.DB) UserRepository
 	Create(user *models.User) error
-	FindAll() ([]models.User, error)
+	FindAll(params *pagination.Params) ([]models.User, *pagination.Page, error)
 	FindByEmail(email string) (*models.User, error)
 	GetUserByID(id uuid.UUID) (*models.User, error)
 }
@@ -23,16 +25,33 @@ func NewUserRepository(db *gorm.DB) *UserRepositoryImpl {
 	return &UserRepositoryImpl{db: db}
 }
 
+func (repo *UserRepositoryImpl) WithTx(tx *gorm.DB) UserRepository {
+	return &UserRepositoryImpl{db: tx}
+}
+
 func (repo *UserRepositoryImpl) Create(user *models.User) error {
 	return repo.db.Create(user).Error
 }
 
-func (repo *UserRepositoryImpl) FindAll() ([]models.User, error) {
+// UserPages is how the user list pages.
+var UserPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":         {Column: "users.id"},
+		"name":       {Column: "users.name"},
+		"email":      {Column: "users.email"},
+		"created_at": {Column: "users.created_at"},
+	},
+	Default: "name",
+	Unique:  "id",
+	Model:   models.User{},
+}
+
+func (repo *UserRepositoryImpl) FindAll(params *pagination.Params) ([]models.User, *pagination.Page, error) {
 	var users []models.User
-	if err := repo.db.Find(&users).Error; err != nil {
-		return nil, err
+	if err := repo.db.Scopes(params.Scope).Find(&users).Error; err != nil {
+		return nil, nil, err
 	}
-	return users, nil
+	return pagination.Cut(params, users)
 }
 
 func (repo *UserRepositoryImpl) FindByEmail(email string) (*models.User, error) {
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"server/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WatcherRepository interface {
+	WithTx(tx *gorm.DB) WatcherRepository
+	AutoWatchTask(taskID uuid.UUID, userID uuid.UUID) error
+	SetTaskWatching(taskID uuid.UUID, userID uuid.UUID, watching bool) error
+	FindTaskWatchers(taskID uuid.UUID) ([]models.TaskWatcher, error)
+	FindTaskWatcherIDs(taskID uuid.UUID, taskBoardID uuid.UUID) ([]uuid.UUID, error)
+	WatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error
+	UnwatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error
+	IsWatchingTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) (bool, error)
+}
+
+type WatcherRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWatcherRepository(db *gorm.DB) *WatcherRepositoryImpl {
+	return &WatcherRepositoryImpl{db: db}
+}
+
+func (repo *WatcherRepositoryImpl) WithTx(tx *gorm.DB) WatcherRepository {
+	return &WatcherRepositoryImpl{db: tx}
+}
+
+// AutoWatchTask makes the user watch the task unless they already do or
+// opted out of it.
+func (repo *WatcherRepositoryImpl) AutoWatchTask(taskID uuid.UUID, userID uuid.UUID) error {
+	return repo.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.TaskWatcher{TaskID: taskID, UserID: userID, Watching: true}).Error
+}
+
+// SetTaskWatching records the user's own choice to watch the task or not.
+func (repo *WatcherRepositoryImpl) SetTaskWatching(taskID uuid.UUID, userID uuid.UUID, watching bool) error {
+	return repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"watching", "updated_at"}),
+	}).Create(&models.TaskWatcher{TaskID: taskID, UserID: userID, Watching: watching}).Error
+}
+
+// FindTaskWatchers lists who watches the task itself, not counting those
+// who only watch its board.
+func (repo *WatcherRepositoryImpl) FindTaskWatchers(taskID uuid.UUID) ([]models.TaskWatcher, error) {
+	var watchers []models.TaskWatcher
+	err := repo.db.Preload("User").
+		Where("task_id = ? AND watching", taskID).
+		Order("created_at").
+		Find(&watchers).Error
+	return watchers, err
+}
+
+// FindTaskWatcherIDs returns everyone following the task: its watchers and
+// the watchers of its board who did not opt out of it.
+func (repo *WatcherRepositoryImpl) FindTaskWatcherIDs(taskID uuid.UUID, taskBoardID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := repo.db.Raw(`
+		SELECT user_id FROM task_watchers WHERE task_id = ? AND watching
+		UNION
+		SELECT user_id FROM task_board_watchers
+		WHERE task_board_id = ?
+			AND user_id NOT IN (SELECT user_id FROM task_watchers WHERE task_id = ? AND NOT watching)`,
+		taskID, taskBoardID, taskID,
+	).Scan(&userIDs).Error
+	return userIDs, err
+}
+
+func (repo *WatcherRepositoryImpl) WatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error {
+	return repo.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.TaskBoardWatcher{TaskBoardID: taskBoardID, UserID: userID}).Error
+}
+
+func (repo *WatcherRepositoryImpl) UnwatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error {
+	return repo.db.Delete(&models.TaskBoardWatcher{}, "task_board_id = ? AND user_id = ?", taskBoardID, userID).Error
+}
+
+func (repo *WatcherRepositoryImpl) IsWatchingTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := repo.db.Model(&models.TaskBoardWatcher{}).
+		Where("task_board_id = ? AND user_id = ?", taskBoardID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"server/models"
+	"server/pagination"
+	"server/policy"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WorkspaceRepository interface {
+	WithTx(tx *gorm.DB) WorkspaceRepository
+	Create(workspace *models.Workspace, adminID uuid.UUID) (*models.Workspace, error)
+	FindByID(workspaceID uuid.UUID) (*models.Workspace, error)
+	FindByUserID(userID uuid.UUID, params *pagination.Params) ([]models.Workspace, *pagination.Page, error)
+	FindPersonal(userID uuid.UUID) (*models.Workspace, error)
+	Update(workspace *models.Workspace) (*models.Workspace, error)
+	Delete(workspaceID uuid.UUID) error
+	CountBoards(workspaceID uuid.UUID) (int64, error)
+	FindMember(workspaceID uuid.UUID, userID uuid.UUID) (*models.WorkspaceMember, error)
+	FindMembers(workspaceID uuid.UUID, params *pagination.Params) ([]models.WorkspaceMember, *pagination.Page, error)
+	LockMembers(workspaceID uuid.UUID) ([]models.WorkspaceMember, error)
+	AddMember(member *models.WorkspaceMember) (*models.WorkspaceMember, error)
+	UpdateMemberRole(workspaceID uuid.UUID, userID uuid.UUID, role string) error
+	RemoveMember(workspaceID uuid.UUID, userID uuid.UUID) error
+	CountAdmins(workspaceID uuid.UUID) (int64, error)
+}
+
+type WorkspaceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) *WorkspaceRepositoryImpl {
+	return &WorkspaceRepositoryImpl{db: db}
+}
+
+func (repo *WorkspaceRepositoryImpl) WithTx(tx *gorm.DB) WorkspaceRepository {
+	return &WorkspaceRepositoryImpl{db: tx}
+}
+
+// Create stores the workspace and makes adminID its first admin.
+func (repo *WorkspaceRepositoryImpl) Create(workspace *models.Workspace, adminID uuid.UUID) (*models.Workspace, error) {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      adminID,
+			Role:        policy.WorkspaceAdmin,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	workspace.Role = policy.WorkspaceAdmin
+	return workspace, nil
+}
+
+func (repo *WorkspaceRepositoryImpl) FindByID(workspaceID uuid.UUID) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := repo.db.First(&workspace, "id = ?", workspaceID).Error; err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// WorkspacePages is how workspace listings page; by default the personal
+// workspace comes first.
+var WorkspacePages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"id":   {Column: "workspaces.id"},
+		"name": {Column: "workspaces.name"},
+		"personal": {
+			Column: "(workspaces.personal_owner_id IS NOT NULL)",
+			Value:  func(row interface{}) interface{} { return row.(models.Workspace).PersonalOwnerID != nil },
+		},
+		"created_at": {Column: "workspaces.created_at"},
+	},
+	Default: "-personal,name",
+	Unique:  "id",
+	Model:   models.Workspace{},
+}
+
+// FindByUserID lists the workspaces the user belongs to, with their role in each.
+func (repo *WorkspaceRepositoryImpl) FindByUserID(userID uuid.UUID, params *pagination.Params) ([]models.Workspace, *pagination.Page, error) {
+	var workspaces []models.Workspace
+	err := repo.db.
+		Select("workspaces.*, workspace_members.role").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", userID).
+		Scopes(params.Scope).
+		Find(&workspaces).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, workspaces)
+}
+
+func (repo *WorkspaceRepositoryImpl) FindPersonal(userID uuid.UUID) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := repo.db.First(&workspace, "personal_owner_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// Update saves the name and settings, including settings set back to their
+// zero value.
+func (repo *WorkspaceRepositoryImpl) Update(workspace *models.Workspace) (*models.Workspace, error) {
+	err := repo.db.Model(workspace).
+		Select("name", "setting_default_board_role", "setting_restrict_board_creation").
+		Updates(workspace).Error
+	if err != nil {
+		return nil, err
+	}
+	return repo.FindByID(workspace.ID)
+}
+
+func (repo *WorkspaceRepositoryImpl) Delete(workspaceID uuid.UUID) error {
+	return repo.db.Delete(&models.Workspace{}, "id = ?", workspaceID).Error
+}
+
+// CountBoards counts the workspace's boards, including trashed ones.
+func (repo *WorkspaceRepositoryImpl) CountBoards(workspaceID uuid.UUID) (int64, error) {
+	var count int64
+	err := repo.db.Unscoped().Model(&models.TaskBoard{}).Where("workspace_id = ?", workspaceID).Count(&count).Error
+	return count, err
+}
+
+func (repo *WorkspaceRepositoryImpl) FindMember(workspaceID uuid.UUID, userID uuid.UUID) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := repo.db.Preload("User").
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// WorkspaceMemberPages is how the workspace's user directory pages, by name
+// unless asked otherwise.
+var WorkspaceMemberPages = pagination.Spec{
+	Keys: map[string]pagination.Key{
+		"user_id": {Column: "workspace_members.user_id"},
+		"name": {
+			Column: "users.name",
+			Value:  func(row interface{}) interface{} { return row.(models.WorkspaceMember).User.Name },
+		},
+		"role":       {Column: "workspace_members.role"},
+		"created_at": {Column: "workspace_members.created_at"},
+	},
+	Default: "name",
+	Unique:  "user_id",
+	Model:   models.WorkspaceMember{},
+}
+
+// FindMembers is the workspace's user directory.
+func (repo *WorkspaceRepositoryImpl) FindMembers(workspaceID uuid.UUID, params *pagination.Params) ([]models.WorkspaceMember, *pagination.Page, error) {
+	var members []models.WorkspaceMember
+	err := repo.db.Preload("User").
+		Joins("JOIN users ON users.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ?", workspaceID).
+		Scopes(params.Scope).
+		Find(&members).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return pagination.Cut(params, members)
+}
+
+// LockMembers reads the workspace's memberships FOR UPDATE so concurrent role
+// changes cannot leave it without an admin. It must run inside a transaction.
+func (repo *WorkspaceRepositoryImpl) LockMembers(workspaceID uuid.UUID) ([]models.WorkspaceMember, error) {
+	var members []models.WorkspaceMember
+	err := repo.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("workspace_id = ?", workspaceID).
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (repo *WorkspaceRepositoryImpl) AddMember(member *models.WorkspaceMember) (*models.WorkspaceMember, error) {
+	if err := repo.db.Create(member).Error; err != nil {
+		return nil, err
+	}
+	return repo.FindMember(member.WorkspaceID, member.UserID)
+}
+
+func (repo *WorkspaceRepositoryImpl) UpdateMemberRole(workspaceID uuid.UUID, userID uuid.UUID, role string) error {
+	return repo.db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Update("role", role).Error
+}
+
+// RemoveMember also takes the user off the workspace's teams.
+func (repo *WorkspaceRepositoryImpl) RemoveMember(workspaceID uuid.UUID, userID uuid.UUID) error {
+	err := repo.db.
+		Where("user_id = ? AND team_id IN (SELECT id FROM teams WHERE workspace_id = ?)", userID, workspaceID).
+		Delete(&models.TeamMember{}).Error
+	if err != nil {
+		return err
+	}
+	return repo.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Delete(&models.WorkspaceMember{}).Error
+}
+
+func (repo *WorkspaceRepositoryImpl) CountAdmins(workspaceID uuid.UUID) (int64, error) {
+	var count int64
+	err := repo.db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ?", workspaceID, policy.WorkspaceAdmin).
+		Count(&count).Error
+	return count, err
+}
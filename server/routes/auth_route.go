@@ -14,7 +14,7 @@ import (
 
 func AuthRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
     userRepository := repositories.NewUserRepository(db)
-    authService := services.NewAuthService(userRepository, logger)
+    authService := services.NewAuthService(userRepository, repositories.NewAuditLogRepository(db), logger)
     authController := controllers.NewAuthController(authService, logger)
 
     authGroup := router.Group("/auth")
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"server/controllers"
+	"server/middlewares"
+	"server/repositories"
+	"server/services"
+	"server/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InvitationRoutes serves the invitation link. Boards manage their
+// invitations under /task-boards/:id/invitations.
+func InvitationRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, mailer utils.Mailer) {
+	taskBoardRepository := repositories.NewTaskBoardRepository(db)
+	invitationService := services.NewInvitationService(
+		repositories.NewInvitationRepository(db),
+		taskBoardRepository,
+		repositories.NewUserRepository(db),
+		repositories.NewBoardRoleRepository(db),
+		repositories.NewTransactor(db),
+		mailer,
+		logger,
+	)
+	invitationController := controllers.NewInvitationController(invitationService, logger)
+
+	invitationGroup := router.Group("/invitations")
+	{
+		// holding the link is enough to look at or decline an invitation
+		public := invitationGroup.Group("")
+		public.Use(
+			middlewares.RequestLogger(logger),
+			middlewares.RateLimiter(50, time.Minute),
+		)
+		{
+			public.GET("/:token", invitationController.GetInvitation)
+			public.POST("/:token/decline", invitationController.DeclineInvitation)
+		}
+		protected := invitationGroup.Group("")
+		protected.Use(
+			middlewares.AuthMiddleware(logger),
+			middlewares.RequestLogger(logger),
+			middlewares.RateLimiter(100, time.Minute),
+		)
+		{
+			protected.POST("/:token/accept", invitationController.AcceptInvitation)
+		}
+	}
+}
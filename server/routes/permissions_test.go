@@ -0,0 +1,328 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"server/gateway"
+	"server/migrations"
+	"server/models"
+	"server/policy"
+	"server/testdb"
+	"server/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const outsider = "outsider"
+
+var (
+	viewers = []string{policy.RoleViewer, policy.RoleEditor, policy.RoleOwner}
+	editors = []string{policy.RoleEditor, policy.RoleOwner}
+	owners  = []string{policy.RoleOwner}
+)
+
+type routeRule struct {
+	method  string
+	path    string
+	action  policy.Action
+	allowed []string
+}
+
+// boardRules lists every route on a task board, where :id is the board, with
+// the action its middleware requires and the roles the default policy
+// grants it to.
+var boardRules = []routeRule{
+	{"GET", "/api/task-boards/:id", policy.BoardView, viewers},
+	{"PUT", "/api/task-boards/:id", policy.BoardUpdate, owners},
+	{"DELETE", "/api/task-boards/:id", policy.BoardDelete, owners},
+	{"POST", "/api/task-boards/:id/clone", policy.BoardClone, viewers},
+	{"POST", "/api/task-boards/:id/archive", policy.BoardArchive, owners},
+	{"POST", "/api/task-boards/:id/restore", policy.BoardArchive, owners},
+	{"GET", "/api/task-boards/:id/trash", policy.BoardView, viewers},
+
+	{"GET", "/api/task-boards/:id/share-links", policy.BoardShare, owners},
+	{"POST", "/api/task-boards/:id/share-links", policy.BoardShare, owners},
+	{"DELETE", "/api/task-boards/:id/share-links/:link_id", policy.BoardShare, owners},
+	{"GET", "/api/task-boards/:id/share-links/:link_id/accesses", policy.BoardShare, owners},
+
+	{"POST", "/api/task-boards/:id/collaborators", policy.MemberManage, owners},
+	{"GET", "/api/task-boards/:id/collaborators", policy.BoardView, viewers},
+	{"PATCH", "/api/task-boards/:id/collaborators/:user_id", policy.MemberManage, owners},
+	{"DELETE", "/api/task-boards/:id/collaborators/:user_id", policy.BoardView, viewers},
+	{"POST", "/api/task-boards/:id/transfer-ownership", policy.MemberManage, owners},
+
+	{"GET", "/api/task-boards/:id/invitations", policy.MemberManage, owners},
+	{"POST", "/api/task-boards/:id/invitations", policy.MemberManage, owners},
+	{"DELETE", "/api/task-boards/:id/invitations/:invitation_id", policy.MemberManage, owners},
+
+	{"GET", "/api/task-boards/:id/teams", policy.BoardView, viewers},
+	{"POST", "/api/task-boards/:id/teams", policy.MemberManage, owners},
+	{"PATCH", "/api/task-boards/:id/teams/:team_id", policy.MemberManage, owners},
+	{"DELETE", "/api/task-boards/:id/teams/:team_id", policy.MemberManage, owners},
+
+	{"GET", "/api/task-boards/:id/roles", policy.BoardView, viewers},
+	{"POST", "/api/task-boards/:id/roles", policy.RoleManage, owners},
+	{"PUT", "/api/task-boards/:id/roles/:role_id", policy.RoleManage, owners},
+	{"DELETE", "/api/task-boards/:id/roles/:role_id", policy.RoleManage, owners},
+
+	{"GET", "/api/task-boards/:id/permissions", policy.BoardView, viewers},
+	{"GET", "/api/task-boards/:id/check-collaborators-permission/user_id/:user_id", policy.BoardView, viewers},
+	{"PUT", "/api/task-boards/:id/wip-limits", policy.BoardSettings, owners},
+	{"GET", "/api/task-boards/:id/audit", policy.AuditView, owners},
+	{"GET", "/api/task-boards/:id/activity", policy.TaskView, viewers},
+	{"GET", "/api/task-boards/:id/time-report", policy.BoardView, viewers},
+
+	{"GET", "/api/task-boards/:id/watch", policy.BoardView, viewers},
+	{"POST", "/api/task-boards/:id/watch", policy.BoardView, viewers},
+	{"DELETE", "/api/task-boards/:id/watch", policy.BoardView, viewers},
+
+	{"GET", "/api/task-boards/:id/custom-fields", policy.BoardView, viewers},
+	{"POST", "/api/task-boards/:id/custom-fields", policy.BoardSettings, owners},
+	{"PUT", "/api/task-boards/:id/custom-fields/:field_id", policy.BoardSettings, owners},
+	{"DELETE", "/api/task-boards/:id/custom-fields/:field_id", policy.BoardSettings, owners},
+
+	{"GET", "/api/task-boards/:id/views", policy.BoardView, viewers},
+	{"POST", "/api/task-boards/:id/views", policy.BoardView, viewers},
+	{"PUT", "/api/task-boards/:id/views/:view_id", policy.BoardView, viewers},
+	{"DELETE", "/api/task-boards/:id/views/:view_id", policy.BoardView, viewers},
+
+	{"GET", "/api/task-boards/:id/labels", policy.BoardView, viewers},
+	{"POST", "/api/task-boards/:id/labels", policy.LabelManage, editors},
+	{"PUT", "/api/task-boards/:id/labels/:label_id", policy.LabelManage, editors},
+	{"DELETE", "/api/task-boards/:id/labels/:label_id", policy.LabelManage, editors},
+}
+
+// taskRules lists every route on a task, where :id is a task the board owner
+// created.
+var taskRules = []routeRule{
+	{"GET", "/api/tasks/:id", policy.TaskView, viewers},
+	{"PUT", "/api/tasks/:id", policy.TaskUpdate, editors},
+	{"DELETE", "/api/tasks/:id", policy.TaskDelete, owners},
+
+	{"GET", "/api/tasks/:id/recurrence", policy.TaskView, viewers},
+	{"PUT", "/api/tasks/:id/recurrence", policy.TaskUpdate, editors},
+	{"DELETE", "/api/tasks/:id/recurrence", policy.TaskUpdate, editors},
+
+	{"GET", "/api/tasks/:id/activity", policy.TaskView, viewers},
+	{"GET", "/api/tasks/:id/watchers", policy.TaskView, viewers},
+	{"POST", "/api/tasks/:id/watch", policy.TaskView, viewers},
+	{"DELETE", "/api/tasks/:id/watch", policy.TaskView, viewers},
+
+	{"GET", "/api/tasks/:id/comments", policy.TaskView, viewers},
+	{"POST", "/api/tasks/:id/comments", policy.CommentCreate, editors},
+	{"DELETE", "/api/tasks/:id/comments/:comment_id", policy.TaskView, viewers},
+
+	{"POST", "/api/tasks/:id/move", policy.TaskUpdate, editors},
+	{"POST", "/api/tasks/:id/archive", policy.TaskArchive, editors},
+	{"POST", "/api/tasks/:id/restore", policy.TaskArchive, editors},
+
+	{"GET", "/api/tasks/:id/time-entries", policy.TaskView, viewers},
+	{"POST", "/api/tasks/:id/time-entries", policy.TimeLog, editors},
+	{"POST", "/api/tasks/:id/timer/start", policy.TimeLog, editors},
+	{"GET", "/api/tasks/:id/time-report", policy.TaskView, viewers},
+}
+
+func newRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	apiGroup := router.Group("/api")
+	logger := zap.NewNop()
+	wsService := gateway.NewWebSocketService()
+	engine := policy.Default()
+	TaskBoardRoutes(apiGroup, db, logger, wsService, engine, &utils.LogMailer{})
+	TaskRoutes(apiGroup, db, logger, wsService, engine)
+	TimeEntryRoutes(apiGroup, db, logger, wsService, engine)
+	return router
+}
+
+// TestPermissionRulesCoverEveryRoute keeps the tables in step with the
+// routes, so a new board or task route cannot go untested.
+func TestPermissionRulesCoverEveryRoute(t *testing.T) {
+	db, err := gorm.Open(postgres.Open(""), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := make(map[string]bool)
+	for _, rule := range append(boardRules, taskRules...) {
+		rules[rule.method+" "+rule.path] = true
+	}
+	registered := make(map[string]bool)
+	for _, route := range newRouter(db).Routes() {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		scoped := strings.HasPrefix(route.Path, "/api/task-boards/:id") || strings.HasPrefix(route.Path, "/api/tasks/:id")
+		if scoped && !rules[key] {
+			t.Errorf("%s has no permission rule", key)
+		}
+	}
+	for key := range rules {
+		if !registered[key] {
+			t.Errorf("%s is not a registered route", key)
+		}
+	}
+}
+
+type permissionFixture struct {
+	users       map[string]models.User
+	taskBoardID uuid.UUID
+	taskID      uuid.UUID
+}
+
+// seedBoard makes a board with an owner, an editor and a viewer, a task the
+// owner created, and a user who is not on the board.
+func seedBoard(t *testing.T, db *gorm.DB) permissionFixture {
+	t.Helper()
+	fixture := permissionFixture{users: make(map[string]models.User)}
+	for _, role := range []string{policy.RoleOwner, policy.RoleEditor, policy.RoleViewer, outsider} {
+		user := models.User{Name: role, Email: fmt.Sprintf("%s-%s@example.com", role, uuid.NewString()), Password: "password"}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		fixture.users[role] = user
+	}
+
+	taskBoard := models.TaskBoard{Title: "Board"}
+	if err := db.Omit(clause.Associations).Create(&taskBoard).Error; err != nil {
+		t.Fatalf("create board: %v", err)
+	}
+	fixture.taskBoardID = taskBoard.ID
+	for _, role := range viewers {
+		member := models.UserTaskBoard{UserID: fixture.users[role].ID, TaskBoardID: taskBoard.ID, Role: role}
+		if err := db.Omit(clause.Associations).Create(&member).Error; err != nil {
+			t.Fatalf("add %s: %v", role, err)
+		}
+	}
+
+	ownerID := fixture.users[policy.RoleOwner].ID
+	now := time.Now()
+	task := models.Task{
+		TaskBoardID: taskBoard.ID,
+		Title:       "Task",
+		Status:      "todo",
+		Priority:    "medium",
+		StartDate:   now,
+		EndDate:     now.Add(24 * time.Hour),
+		CreatedByID: &ownerID,
+	}
+	if err := db.Omit(clause.Associations).Create(&task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	fixture.taskID = task.ID
+	return fixture
+}
+
+var routeParam = regexp.MustCompile(`:[a-z_]+`)
+
+// fillPath puts id in the :id parameter and fresh IDs in the others.
+func fillPath(path string, id uuid.UUID) string {
+	path = strings.Replace(path, ":id", id.String(), 1)
+	return routeParam.ReplaceAllStringFunc(path, func(string) string {
+		return uuid.NewString()
+	})
+}
+
+// deniedAction returns the action the permission middleware refused, if the
+// response is its refusal.
+func deniedAction(recorder *httptest.ResponseRecorder) policy.Action {
+	if recorder.Code != http.StatusForbidden {
+		return ""
+	}
+	var body struct {
+		Action policy.Action `json:"action"`
+	}
+	json.Unmarshal(recorder.Body.Bytes(), &body)
+	return body.Action
+}
+
+func TestRoutePermissions(t *testing.T) {
+	db := testdb.Open(t)
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	t.Setenv("JWT_SECRET", "route-permissions-test")
+	router := newRouter(db)
+
+	// every request comes from its own address so the rate limiter stays out
+	// of the way
+	requests := 0
+	do := func(t *testing.T, method, path string, user models.User) *httptest.ResponseRecorder {
+		t.Helper()
+		token, err := utils.CreateToken(utils.User{ID: user.ID.String(), Name: user.Name, Email: user.Email})
+		if err != nil {
+			t.Fatalf("CreateToken: %v", err)
+		}
+		requests++
+		request := httptest.NewRequest(method, path, strings.NewReader("{}"))
+		request.RemoteAddr = fmt.Sprintf("10.%d.%d.%d:1234", requests>>16&255, requests>>8&255, requests&255)
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	check := func(rules []routeRule, target func(permissionFixture) uuid.UUID) {
+		for _, rule := range rules {
+			for _, role := range []string{outsider, policy.RoleViewer, policy.RoleEditor, policy.RoleOwner} {
+				allowed := false
+				for _, allowedRole := range rule.allowed {
+					allowed = allowed || allowedRole == role
+				}
+				t.Run(fmt.Sprintf("%s %s as %s", rule.method, rule.path, role), func(t *testing.T) {
+					// each request gets a board of its own, as an allowed one
+					// may delete or archive what it acts on
+					fixture := seedBoard(t, db)
+					recorder := do(t, rule.method, fillPath(rule.path, target(fixture)), fixture.users[role])
+
+					denied := deniedAction(recorder)
+					switch {
+					case recorder.Code == http.StatusUnauthorized:
+						t.Errorf("got 401: %s", recorder.Body)
+					case allowed && denied != "":
+						t.Errorf("denied %s, want it allowed", denied)
+					case !allowed && denied != rule.action:
+						t.Errorf("got %d %s, want 403 for %s", recorder.Code, recorder.Body, rule.action)
+					}
+				})
+			}
+		}
+	}
+	check(boardRules, func(fixture permissionFixture) uuid.UUID { return fixture.taskBoardID })
+	check(taskRules, func(fixture permissionFixture) uuid.UUID { return fixture.taskID })
+
+	fixture := seedBoard(t, db)
+	for _, rule := range taskRules {
+		t.Run(fmt.Sprintf("%s %s for a missing task", rule.method, rule.path), func(t *testing.T) {
+			recorder := do(t, rule.method, fillPath(rule.path, uuid.New()), fixture.users[outsider])
+			if recorder.Code != http.StatusNotFound {
+				t.Errorf("got %d %s, want 404", recorder.Code, recorder.Body)
+			}
+		})
+	}
+
+	t.Run("another user's boards", func(t *testing.T) {
+		path := "/api/task-boards/user/" + fixture.users[policy.RoleOwner].ID.String()
+		if recorder := do(t, "GET", path, fixture.users[outsider]); recorder.Code != http.StatusForbidden {
+			t.Errorf("got %d %s, want 403", recorder.Code, recorder.Body)
+		}
+		if recorder := do(t, "GET", path, fixture.users[policy.RoleOwner]); recorder.Code != http.StatusOK {
+			t.Errorf("got %d %s for the user's own boards, want 200", recorder.Code, recorder.Body)
+		}
+	})
+}
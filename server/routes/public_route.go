@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"server/controllers"
+	"server/middlewares"
+	"server/repositories"
+	"server/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PublicRoutes serves boards shared through share links, without an account.
+// Owners manage the links under /task-boards/:id/share-links.
+func PublicRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
+	shareLinkService := services.NewShareLinkService(
+		repositories.NewShareLinkRepository(db),
+		repositories.NewTaskBoardRepository(db),
+		repositories.NewCustomFieldRepository(db),
+		logger,
+	)
+	shareLinkController := controllers.NewShareLinkController(shareLinkService, logger)
+
+	publicGroup := router.Group("/public")
+	{
+		publicGroup.Use(
+			middlewares.RequestLogger(logger),
+			middlewares.RateLimiter(30, time.Minute),
+		)
+		publicGroup.GET("/boards/:token", shareLinkController.GetPublicBoard)
+	}
+}
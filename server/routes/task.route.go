@@ -4,20 +4,38 @@ import (
 	"server/controllers"
 	"server/gateway"
 	"server/middlewares"
+	"server/policy"
 	"server/repositories"
 	"server/services"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-func TaskRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, wsService *gateway.WebSocketService) {
+func TaskRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, wsService *gateway.WebSocketService, engine *policy.Engine) {
 	taskRepo := repositories.NewTaskRepository(db)
 	taskBoardRepo := repositories.NewTaskBoardRepository(db)
-	taskService := services.NewTaskService(taskRepo, taskBoardRepo, wsService, logger)
+	customFieldRepo := repositories.NewCustomFieldRepository(db)
+	labelRepo := repositories.NewLabelRepository(db)
+	transactor := repositories.NewTransactor(db)
+	auditRepo := repositories.NewAuditLogRepository(db)
+	watcherRepo := repositories.NewWatcherRepository(db)
+	notifier := services.NewNotifier(repositories.NewNotificationRepository(db), watcherRepo, repositories.NewUserRepository(db), taskBoardRepo, wsService, logger)
+	taskService := services.NewTaskService(taskRepo, taskBoardRepo, customFieldRepo, labelRepo, auditRepo, watcherRepo, notifier, transactor, engine, wsService, logger)
 	taskController := controllers.NewTaskController(taskService, logger)
+	taskBoardService := services.NewTaskBoardService(taskBoardRepo, logger, repositories.NewUserRepository(db), customFieldRepo, repositories.NewBoardRoleRepository(db), repositories.NewWorkspaceRepository(db), repositories.NewInvitationRepository(db), repositories.NewSavedViewRepository(db), repositories.NewLabelRepository(db), auditRepo, notifier, transactor, engine)
+	commentService := services.NewCommentService(repositories.NewCommentRepository(db), taskRepo, taskBoardRepo, watcherRepo, notifier, engine, wsService, logger)
+	commentController := controllers.NewCommentController(commentService, logger)
+	recurrenceRepo := repositories.NewTaskRecurrenceRepository(db)
+	recurrenceService := services.NewRecurrenceService(recurrenceRepo, taskRepo, taskService, logger)
+	recurrenceController := controllers.NewRecurrenceController(recurrenceService, logger)
+	searchService := services.NewSearchService(taskRepo, taskBoardRepo, engine, logger)
+	searchController := controllers.NewSearchController(searchService, logger)
+	watcherController := controllers.NewWatcherController(services.NewWatcherService(watcherRepo, taskRepo, logger), logger)
+	activityController := controllers.NewActivityController(services.NewActivityService(repositories.NewTaskActivityRepository(db), logger), logger)
 
 	taskGroup := router.Group("/tasks")
 	{
@@ -28,14 +46,48 @@ func TaskRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, wsServ
 			middlewares.RateLimiter(100, time.Minute),
 		)
 		{
-			taskGroup.POST("/", taskController.CreateTask)
-			taskGroup.GET("/:id", taskController.GetTaskByID)
-			taskGroup.PUT("/:id", taskController.UpdateTask)
-			taskGroup.DELETE("/:id", taskController.DeleteTask)
+			// the board comes from the body; the service checks task.create on it
+			protected.POST("/", taskController.CreateTask)
+			// searches every board the user can view tasks on
+			protected.GET("/search", searchController.SearchTasks)
+			protected.GET("/:id", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), taskController.GetTaskByID)
+			protected.PUT("/:id", middlewares.HasTaskPermission(policy.TaskUpdate, engine, taskBoardService, logger), taskController.UpdateTask)
+			protected.DELETE("/:id", middlewares.HasTaskPermission(policy.TaskDelete, engine, taskBoardService, logger), taskController.DeleteTask)
+
+			protected.GET("/:id/recurrence", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), recurrenceController.GetRecurrence)
+			protected.PUT("/:id/recurrence", middlewares.HasTaskPermission(policy.TaskUpdate, engine, taskBoardService, logger), recurrenceController.SetRecurrence)
+			protected.DELETE("/:id/recurrence", middlewares.HasTaskPermission(policy.TaskUpdate, engine, taskBoardService, logger), recurrenceController.DeleteRecurrence)
+
+			protected.GET("/:id/activity", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), activityController.GetTaskActivity)
+
+			protected.GET("/:id/watchers", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), watcherController.GetTaskWatchers)
+			protected.POST("/:id/watch", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), watcherController.WatchTask)
+			protected.DELETE("/:id/watch", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), watcherController.UnwatchTask)
+
+			protected.GET("/:id/comments", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), commentController.GetComments)
+			protected.POST("/:id/comments", middlewares.HasTaskPermission(policy.CommentCreate, engine, taskBoardService, logger), commentController.CreateComment)
+			// comment.delete depends on the comment's author, so the service checks it
+			protected.DELETE("/:id/comments/:comment_id", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), commentController.DeleteComment)
+
+			// the service also checks task.create on the target board
+			protected.POST("/:id/move", middlewares.HasTaskPermission(policy.TaskUpdate, engine, taskBoardService, logger), taskController.MoveTask)
+			// tasks may span boards; the service checks each one
+			protected.POST("/bulk", taskController.BulkUpdateTasks)
+			protected.POST("/:id/archive", middlewares.HasTaskPermission(policy.TaskArchive, engine, taskBoardService, logger), taskController.ArchiveTask)
+			protected.POST("/:id/restore", middlewares.HasTaskPermission(policy.TaskArchive, engine, taskBoardService, logger), taskController.RestoreTask)
 		}
 	}
 
+	// board channels carry task payloads, so subscribing needs task.view
+	canViewBoard := func(userID uuid.UUID, taskBoardID uuid.UUID) bool {
+		userTaskBoard, err := taskBoardService.CheckUserRole(taskBoardID, userID)
+		if err != nil {
+			return false
+		}
+		subject := policy.NewSubject(userID, userTaskBoard.Role, userTaskBoard.CustomPermissions())
+		return engine.Allows(subject, policy.TaskView, policy.Resource{TaskBoardID: taskBoardID})
+	}
 	router.GET("/ws", func(c *gin.Context) {
-		wsService.HandleConnections(c.Writer, c.Request)
+		wsService.HandleConnections(c.Writer, c.Request, canViewBoard)
 	})
 }
@@ -2,9 +2,12 @@ package routes
 
 import (
 	"server/controllers"
+	"server/gateway"
 	"server/middlewares"
+	"server/policy"
 	"server/repositories"
 	"server/services"
+	"server/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,11 +15,52 @@ import (
 	"gorm.io/gorm"
 )
 
-func TaskBoardRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
+func TaskBoardRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, wsService *gateway.WebSocketService, engine *policy.Engine, mailer utils.Mailer) {
 	taskBoardRepository := repositories.NewTaskBoardRepository(db)
 	userRepository := repositories.NewUserRepository(db)
-	taskBoardService := services.NewTaskBoardService(taskBoardRepository, logger, userRepository)
+	customFieldRepository := repositories.NewCustomFieldRepository(db)
+	boardRoleRepository := repositories.NewBoardRoleRepository(db)
+	workspaceRepository := repositories.NewWorkspaceRepository(db)
+	teamRepository := repositories.NewTeamRepository(db)
+	invitationRepository := repositories.NewInvitationRepository(db)
+	savedViewRepository := repositories.NewSavedViewRepository(db)
+	labelRepository := repositories.NewLabelRepository(db)
+	auditLogRepository := repositories.NewAuditLogRepository(db)
+	transactor := repositories.NewTransactor(db)
+	watcherRepository := repositories.NewWatcherRepository(db)
+	notifier := services.NewNotifier(repositories.NewNotificationRepository(db), watcherRepository, userRepository, taskBoardRepository, wsService, logger)
+	taskBoardService := services.NewTaskBoardService(taskBoardRepository, logger, userRepository, customFieldRepository, boardRoleRepository, workspaceRepository, invitationRepository, savedViewRepository, labelRepository, auditLogRepository, notifier, transactor, engine)
 	taskBoardController := controllers.NewTaskBoardController(taskBoardService, logger)
+	customFieldService := services.NewCustomFieldService(customFieldRepository, taskBoardRepository, logger)
+	customFieldController := controllers.NewCustomFieldController(customFieldService, logger)
+	labelService := services.NewLabelService(labelRepository, taskBoardRepository, logger)
+	labelController := controllers.NewLabelController(labelService, logger)
+	boardTemplateService := services.NewBoardTemplateService(
+		taskBoardRepository,
+		repositories.NewTaskRepository(db),
+		customFieldRepository,
+		labelRepository,
+		boardRoleRepository,
+		teamRepository,
+		workspaceRepository,
+		transactor,
+		engine,
+		logger,
+	)
+	boardTemplateController := controllers.NewBoardTemplateController(boardTemplateService, logger)
+	boardRoleService := services.NewBoardRoleService(boardRoleRepository, taskBoardRepository, logger)
+	boardRoleController := controllers.NewBoardRoleController(boardRoleService, logger)
+	teamService := services.NewTeamService(teamRepository, workspaceRepository, taskBoardRepository, boardRoleRepository, logger)
+	teamController := controllers.NewTeamController(teamService, logger)
+	invitationService := services.NewInvitationService(invitationRepository, taskBoardRepository, userRepository, boardRoleRepository, transactor, mailer, logger)
+	invitationController := controllers.NewInvitationController(invitationService, logger)
+	shareLinkService := services.NewShareLinkService(repositories.NewShareLinkRepository(db), taskBoardRepository, customFieldRepository, logger)
+	shareLinkController := controllers.NewShareLinkController(shareLinkService, logger)
+	savedViewService := services.NewSavedViewService(savedViewRepository, taskBoardRepository, customFieldRepository, labelRepository, engine, logger)
+	savedViewController := controllers.NewSavedViewController(savedViewService, logger)
+	auditController := controllers.NewAuditController(services.NewAuditService(auditLogRepository, logger), logger)
+	activityController := controllers.NewActivityController(services.NewActivityService(repositories.NewTaskActivityRepository(db), logger), logger)
+	watcherController := controllers.NewWatcherController(services.NewWatcherService(watcherRepository, repositories.NewTaskRepository(db), logger), logger)
 
 	taskBoardGroup := router.Group("/task-boards")
 	{
@@ -28,19 +72,74 @@ func TaskBoardRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 		)
 		{	
 			// viewer editor owner
-			protected.GET("/:id", taskBoardController.GetTaskBoardByID)        
-			protected.GET("/user/:user_id", taskBoardController.GetTaskBoardsByUserID)
+			protected.GET("/:id", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), taskBoardController.GetTaskBoardByID)
+			protected.GET("/user/:user_id", middlewares.IsCurrentUser("user_id", logger), taskBoardController.GetTaskBoardsByUserID)
+			protected.GET("/templates", boardTemplateController.GetTemplates)
+			protected.GET("/trash", taskBoardController.GetTrash)
+			protected.POST("/:id/clone", middlewares.HasPermission(policy.BoardClone, engine, taskBoardService, logger), boardTemplateController.CloneTaskBoard)
 
 			// owner 
 			protected.POST("", taskBoardController.CreateTaskBoard)           
-			protected.PUT("/:id", taskBoardController.UpdateTaskBoard)         
-			protected.DELETE("/:id", taskBoardController.DeleteTaskBoard)      
+			protected.PUT("/:id", middlewares.HasPermission(policy.BoardUpdate, engine, taskBoardService, logger), taskBoardController.UpdateTaskBoard)
+			protected.DELETE("/:id", middlewares.HasPermission(policy.BoardDelete, engine, taskBoardService, logger), taskBoardController.DeleteTaskBoard)
+			protected.POST("/:id/archive", middlewares.HasPermission(policy.BoardArchive, engine, taskBoardService, logger), taskBoardController.ArchiveTaskBoard)
+			protected.POST("/:id/restore", middlewares.HasPermission(policy.BoardArchive, engine, taskBoardService, logger), taskBoardController.RestoreTaskBoard)
+			protected.GET("/:id/trash", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), taskBoardController.GetTaskTrash)
+
+			protected.GET("/:id/share-links", middlewares.HasPermission(policy.BoardShare, engine, taskBoardService, logger), shareLinkController.GetShareLinks)
+			protected.POST("/:id/share-links", middlewares.HasPermission(policy.BoardShare, engine, taskBoardService, logger), shareLinkController.CreateShareLink)
+			protected.DELETE("/:id/share-links/:link_id", middlewares.HasPermission(policy.BoardShare, engine, taskBoardService, logger), shareLinkController.RevokeShareLink)
+			protected.GET("/:id/share-links/:link_id/accesses", middlewares.HasPermission(policy.BoardShare, engine, taskBoardService, logger), shareLinkController.GetShareLinkAccesses)
 			
 
-			protected.POST("/:id/collaborators", taskBoardController.AddCollaborator) 
-			protected.GET("/:id/collaborators", taskBoardController.GetCollaboratorOnTaskBoard) 
+			protected.POST("/:id/collaborators", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), taskBoardController.AddCollaborator)
+			protected.GET("/:id/collaborators", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), taskBoardController.GetCollaboratorOnTaskBoard)
+			protected.PATCH("/:id/collaborators/:user_id", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), taskBoardController.UpdateCollaboratorRole)
+			// member.remove depends on whose membership it is, so the service checks it
+			protected.DELETE("/:id/collaborators/:user_id", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), taskBoardController.RemoveCollaborator)
+			protected.POST("/:id/transfer-ownership", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), taskBoardController.TransferOwnership)
+
+			protected.GET("/:id/invitations", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), invitationController.GetInvitations)
+			protected.POST("/:id/invitations", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), invitationController.Invite)
+			protected.DELETE("/:id/invitations/:invitation_id", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), invitationController.RevokeInvitation)
+
+			protected.GET("/:id/teams", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), teamController.GetBoardTeams)
+			protected.POST("/:id/teams", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), teamController.GrantTeam)
+			protected.PATCH("/:id/teams/:team_id", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), teamController.UpdateTeamGrant)
+			protected.DELETE("/:id/teams/:team_id", middlewares.HasPermission(policy.MemberManage, engine, taskBoardService, logger), teamController.RevokeTeam)
+
+			protected.GET("/:id/roles", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), boardRoleController.GetRoles)
+			protected.POST("/:id/roles", middlewares.HasPermission(policy.RoleManage, engine, taskBoardService, logger), boardRoleController.CreateRole)
+			protected.PUT("/:id/roles/:role_id", middlewares.HasPermission(policy.RoleManage, engine, taskBoardService, logger), boardRoleController.UpdateRole)
+			protected.DELETE("/:id/roles/:role_id", middlewares.HasPermission(policy.RoleManage, engine, taskBoardService, logger), boardRoleController.DeleteRole)
+
+			protected.GET("/:id/permissions", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), taskBoardController.GetPermissions)
+			protected.GET("/:id/check-collaborators-permission/user_id/:user_id", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), taskBoardController.CheckUserRole)
+
+			protected.PUT("/:id/wip-limits", middlewares.HasPermission(policy.BoardSettings, engine, taskBoardService, logger), taskBoardController.SetWIPLimits)
+
+			protected.GET("/:id/audit", middlewares.HasPermission(policy.AuditView, engine, taskBoardService, logger), auditController.GetTaskBoardAuditLog)
+			protected.GET("/:id/activity", middlewares.HasPermission(policy.TaskView, engine, taskBoardService, logger), activityController.GetTaskBoardActivity)
+
+			protected.GET("/:id/watch", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), watcherController.GetTaskBoardWatch)
+			protected.POST("/:id/watch", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), watcherController.WatchTaskBoard)
+			protected.DELETE("/:id/watch", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), watcherController.UnwatchTaskBoard)
+
+			protected.GET("/:id/custom-fields", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), customFieldController.GetCustomFields)
+			protected.POST("/:id/custom-fields", middlewares.HasPermission(policy.BoardSettings, engine, taskBoardService, logger), customFieldController.CreateCustomField)
+			protected.PUT("/:id/custom-fields/:field_id", middlewares.HasPermission(policy.BoardSettings, engine, taskBoardService, logger), customFieldController.UpdateCustomField)
+			protected.DELETE("/:id/custom-fields/:field_id", middlewares.HasPermission(policy.BoardSettings, engine, taskBoardService, logger), customFieldController.DeleteCustomField)
+
+			protected.GET("/:id/views", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), savedViewController.GetViews)
+			// sharing a view, and changing someone else's, is checked by the service
+			protected.POST("/:id/views", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), savedViewController.CreateView)
+			protected.PUT("/:id/views/:view_id", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), savedViewController.UpdateView)
+			protected.DELETE("/:id/views/:view_id", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), savedViewController.DeleteView)
 
-			protected.GET("/:id/check-collaborators-permission/user_id/:user_id", taskBoardController.CheckUserRole)
+			protected.GET("/:id/labels", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), labelController.GetLabels)
+			protected.POST("/:id/labels", middlewares.HasPermission(policy.LabelManage, engine, taskBoardService, logger), labelController.CreateLabel)
+			protected.PUT("/:id/labels/:label_id", middlewares.HasPermission(policy.LabelManage, engine, taskBoardService, logger), labelController.UpdateLabel)
+			protected.DELETE("/:id/labels/:label_id", middlewares.HasPermission(policy.LabelManage, engine, taskBoardService, logger), labelController.DeleteLabel)
 		}
 	}
 }
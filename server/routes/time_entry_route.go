@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"server/controllers"
+	"server/gateway"
+	"server/middlewares"
+	"server/policy"
+	"server/repositories"
+	"server/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TimeEntryRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, wsService *gateway.WebSocketService, engine *policy.Engine) {
+	timeEntryRepository := repositories.NewTimeEntryRepository(db)
+	taskRepository := repositories.NewTaskRepository(db)
+	taskBoardRepository := repositories.NewTaskBoardRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	customFieldRepository := repositories.NewCustomFieldRepository(db)
+	notifier := services.NewNotifier(repositories.NewNotificationRepository(db), repositories.NewWatcherRepository(db), userRepository, taskBoardRepository, wsService, logger)
+	taskBoardService := services.NewTaskBoardService(taskBoardRepository, logger, userRepository, customFieldRepository, repositories.NewBoardRoleRepository(db), repositories.NewWorkspaceRepository(db), repositories.NewInvitationRepository(db), repositories.NewSavedViewRepository(db), repositories.NewLabelRepository(db), repositories.NewAuditLogRepository(db), notifier, repositories.NewTransactor(db), engine)
+	timeTrackingService := services.NewTimeTrackingService(timeEntryRepository, taskRepository, taskBoardRepository, engine, logger)
+	timeTrackingController := controllers.NewTimeTrackingController(timeTrackingService, logger)
+
+	protected := router.Group("")
+	protected.Use(
+		middlewares.AuthMiddleware(logger),
+		middlewares.RequestLogger(logger),
+		middlewares.RateLimiter(100, time.Minute),
+	)
+	{
+		protected.GET("/tasks/:id/time-entries", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), timeTrackingController.GetTaskEntries)
+		protected.POST("/tasks/:id/time-entries", middlewares.HasTaskPermission(policy.TimeLog, engine, taskBoardService, logger), timeTrackingController.LogTime)
+		protected.POST("/tasks/:id/timer/start", middlewares.HasTaskPermission(policy.TimeLog, engine, taskBoardService, logger), timeTrackingController.StartTimer)
+		protected.GET("/tasks/:id/time-report", middlewares.HasTaskPermission(policy.TaskView, engine, taskBoardService, logger), timeTrackingController.TaskReport)
+
+		// timers and entries belong to the current user; the service checks
+		// time.delete against the entry's author
+		protected.GET("/time-entries/timer", timeTrackingController.GetRunningTimer)
+		protected.POST("/time-entries/timer/stop", timeTrackingController.StopTimer)
+		protected.DELETE("/time-entries/:id", timeTrackingController.DeleteEntry)
+
+		protected.GET("/task-boards/:id/time-report", middlewares.HasPermission(policy.BoardView, engine, taskBoardService, logger), timeTrackingController.TaskBoardReport)
+		protected.GET("/users/me/time-report", timeTrackingController.UserReport)
+	}
+}
@@ -2,9 +2,11 @@ package routes
 
 import (
 	"server/controllers"
+	"server/gateway"
 	"server/middlewares"
 	"server/repositories"
 	"server/services"
+	"server/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,10 +14,16 @@ import (
 	"gorm.io/gorm"
 )
 
-func UserRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
+func UserRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger, wsService *gateway.WebSocketService, mailer utils.Mailer) {
 	userRepository := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepository, logger)
+	taskBoardRepository := repositories.NewTaskBoardRepository(db)
+	userService := services.NewUserService(userRepository, repositories.NewInvitationRepository(db), taskBoardRepository, repositories.NewAuditLogRepository(db), repositories.NewTransactor(db), logger)
 	userController := controllers.NewUserController(userService, logger)
+	reminderRepository := repositories.NewReminderRepository(db)
+	reminderService := services.NewReminderService(reminderRepository, taskBoardRepository, wsService, mailer, logger)
+	reminderController := controllers.NewReminderController(reminderService, logger)
+	auditController := controllers.NewAuditController(services.NewAuditService(repositories.NewAuditLogRepository(db), logger), logger)
+	notificationController := controllers.NewNotificationController(services.NewNotificationService(repositories.NewNotificationRepository(db), wsService, logger), logger)
 
 	userGroup := router.Group("/users")
 	{
@@ -36,6 +44,15 @@ func UserRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
 		)
 		{
 			protected.GET("", userController.GetAllUsers)
+
+			protected.GET("/me/reminders", reminderController.ListReminders)
+			protected.GET("/me/reminder-settings", reminderController.GetSetting)
+			protected.PUT("/me/reminder-settings", reminderController.UpdateSetting)
+			protected.GET("/me/audit", auditController.GetMyAuditLog)
+			protected.GET("/me/notifications", notificationController.GetNotifications)
+			protected.GET("/me/notifications/unread-count", notificationController.GetUnreadCount)
+			protected.POST("/me/notifications/read-all", notificationController.MarkAllRead)
+			protected.POST("/me/notifications/:notification_id/read", notificationController.MarkRead)
 		}
 	}
 }
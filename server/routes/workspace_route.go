@@ -0,0 +1,73 @@
+package routes
+
+import (
+	"server/controllers"
+	"server/middlewares"
+	"server/policy"
+	"server/repositories"
+	"server/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func WorkspaceRoutes(router *gin.RouterGroup, db *gorm.DB, logger *zap.Logger) {
+	workspaceRepository := repositories.NewWorkspaceRepository(db)
+	taskBoardRepository := repositories.NewTaskBoardRepository(db)
+	workspaceService := services.NewWorkspaceService(
+		workspaceRepository,
+		taskBoardRepository,
+		repositories.NewUserRepository(db),
+		repositories.NewTransactor(db),
+		logger,
+	)
+	workspaceController := controllers.NewWorkspaceController(workspaceService, logger)
+	teamService := services.NewTeamService(
+		repositories.NewTeamRepository(db),
+		workspaceRepository,
+		taskBoardRepository,
+		repositories.NewBoardRoleRepository(db),
+		logger,
+	)
+	teamController := controllers.NewTeamController(teamService, logger)
+	auditController := controllers.NewAuditController(services.NewAuditService(repositories.NewAuditLogRepository(db), logger), logger)
+
+	workspaceGroup := router.Group("/workspaces")
+	{
+		protected := workspaceGroup.Group("")
+		protected.Use(
+			middlewares.AuthMiddleware(logger),
+			middlewares.RequestLogger(logger),
+			middlewares.RateLimiter(100, time.Minute),
+		)
+		{
+			protected.POST("", workspaceController.CreateWorkspace)
+			protected.GET("", workspaceController.GetWorkspaces)
+
+			// admin member guest
+			protected.GET("/:id", middlewares.HasWorkspaceRole(policy.WorkspaceGuest, workspaceService, logger), workspaceController.GetWorkspace)
+			protected.GET("/:id/boards", middlewares.HasWorkspaceRole(policy.WorkspaceGuest, workspaceService, logger), workspaceController.GetBoards)
+			// admins remove anyone and members leave, so the service checks it
+			protected.DELETE("/:id/members/:user_id", middlewares.HasWorkspaceRole(policy.WorkspaceGuest, workspaceService, logger), workspaceController.RemoveMember)
+
+			// admin member
+			protected.GET("/:id/members", middlewares.HasWorkspaceRole(policy.WorkspaceMember, workspaceService, logger), workspaceController.GetMembers)
+			protected.GET("/:id/teams", middlewares.HasWorkspaceRole(policy.WorkspaceMember, workspaceService, logger), teamController.GetTeams)
+			protected.GET("/:id/teams/:team_id/members", middlewares.HasWorkspaceRole(policy.WorkspaceMember, workspaceService, logger), teamController.GetTeamMembers)
+
+			// admin
+			protected.PUT("/:id", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), workspaceController.UpdateWorkspace)
+			protected.DELETE("/:id", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), workspaceController.DeleteWorkspace)
+			protected.POST("/:id/members", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), workspaceController.AddMember)
+			protected.PATCH("/:id/members/:user_id", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), workspaceController.UpdateMemberRole)
+			protected.POST("/:id/teams", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), teamController.CreateTeam)
+			protected.PUT("/:id/teams/:team_id", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), teamController.UpdateTeam)
+			protected.DELETE("/:id/teams/:team_id", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), teamController.DeleteTeam)
+			protected.POST("/:id/teams/:team_id/members", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), teamController.AddTeamMember)
+			protected.DELETE("/:id/teams/:team_id/members/:user_id", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), teamController.RemoveTeamMember)
+			protected.GET("/:id/audit", middlewares.HasWorkspaceRole(policy.WorkspaceAdmin, workspaceService, logger), auditController.GetWorkspaceAuditLog)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package search parses the task search language into an AST. A query is a
+// list of terms, all of which must match:
+//
+//	status:todo,in_progress priority:high assignee:me due:<7d "login bug" -label:wontfix
+//
+// A term is a filter (field:value), a quoted phrase or a bare word; a leading
+// "-" negates it. Comma-separated filter values match any of them. The AST
+// only holds validated values, so it can be compiled to parameterized SQL
+// without interpreting user input again.
+package search
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Field is a filterable task attribute.
+type Field string
+
+const (
+	FieldStatus   Field = "status"
+	FieldPriority Field = "priority"
+	FieldAssignee Field = "assignee"
+	FieldBoard    Field = "board"
+	FieldLabel    Field = "label"
+	FieldDue      Field = "due"
+	FieldCreated  Field = "created"
+	FieldIs       Field = "is"
+)
+
+// Op compares a date field with a Bound.
+type Op string
+
+const (
+	OpEq  Op = "="
+	OpLt  Op = "<"
+	OpLte Op = "<="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+)
+
+// States that is: filters on.
+const (
+	StateArchived   = "archived"
+	StateOverdue    = "overdue"
+	StateUnassigned = "unassigned"
+)
+
+// Assignee values with a special meaning.
+const (
+	AssigneeMe   = "me"
+	AssigneeNone = "none"
+)
+
+// Query is a parsed search. An empty query matches every task.
+type Query struct {
+	Filters []Filter
+	Text    []Text
+}
+
+// Filter restricts one field. Enumerated fields use Values; due and created
+// use Op and Bound.
+type Filter struct {
+	Field  Field
+	Negate bool
+	Values []string
+	// Assignees holds assignee values parsed as user IDs; Values keeps the
+	// rest (me, none and emails).
+	Assignees []uuid.UUID
+	// Boards holds board: values.
+	Boards []uuid.UUID
+	Op     Op
+	Bound  *Bound
+}
+
+// Bound is a point in time, either a calendar day or an offset from now:
+// ahead of now for due dates and back from now for creation dates.
+type Bound struct {
+	Date     time.Time
+	Relative time.Duration
+	// IsRelative tells which of Date and Relative is set.
+	IsRelative bool
+}
+
+// Text is a free-text term, matched against the task's title and
+// description with full-text search.
+type Text struct {
+	Value  string
+	Phrase bool
+	Negate bool
+}
+
+// Has reports whether the query holds a non-negated filter on field with
+// value, such as is:archived.
+func (query *Query) Has(field Field, value string) bool {
+	for _, filter := range query.Filters {
+		if filter.Field != field || filter.Negate {
+			continue
+		}
+		for _, candidate := range filter.Values {
+			if candidate == value {
+				return true
+			}
+		}
+	}
+	return false
+}
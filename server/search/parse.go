@@ -0,0 +1,258 @@
+package search
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxQueryLength = 500
+	maxTerms       = 20
+	maxValues      = 20
+)
+
+var (
+	statuses   = []string{"todo", "in_progress", "done"}
+	priorities = []string{"low", "medium", "high"}
+	states     = []string{StateArchived, StateOverdue, StateUnassigned}
+	// relativePattern matches offsets such as 12h, 7d or 2w.
+	relativePattern = regexp.MustCompile(`^(\d{1,4})([hdw])$`)
+)
+
+// ParseError reports a query the language does not accept.
+type ParseError struct {
+	message string
+}
+
+func (e *ParseError) Error() string {
+	return e.message
+}
+
+func parseErrorf(format string, args ...interface{}) *ParseError {
+	return &ParseError{message: fmt.Sprintf(format, args...)}
+}
+
+// Parse turns a query string into a Query, or returns a *ParseError.
+func Parse(input string) (*Query, error) {
+	if len(input) > maxQueryLength {
+		return nil, parseErrorf("query is longer than %d characters", maxQueryLength)
+	}
+
+	query := &Query{}
+	terms := 0
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		terms++
+		if terms > maxTerms {
+			return nil, parseErrorf("query has more than %d terms", maxTerms)
+		}
+
+		negate := false
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			negate = true
+			i++
+		}
+
+		if runes[i] == '"' {
+			phrase, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			i = next
+			if phrase = strings.TrimSpace(phrase); phrase != "" {
+				query.Text = append(query.Text, Text{Value: phrase, Phrase: true, Negate: negate})
+			}
+			continue
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != ':' && runes[i] != '"' {
+			i++
+		}
+		word := string(runes[start:i])
+		if i >= len(runes) || runes[i] != ':' {
+			query.Text = append(query.Text, Text{Value: word, Negate: negate})
+			continue
+		}
+
+		// field:value, where the value may be quoted
+		i++
+		var value string
+		if i < len(runes) && runes[i] == '"' {
+			quoted, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			value, i = quoted, next
+		} else {
+			start = i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				i++
+			}
+			value = string(runes[start:i])
+		}
+
+		filter, err := parseFilter(strings.ToLower(word), value)
+		if err != nil {
+			return nil, err
+		}
+		filter.Negate = negate
+		query.Filters = append(query.Filters, *filter)
+	}
+	return query, nil
+}
+
+// readQuoted reads the string opened by the quote at runes[start] and
+// returns it with the index just past the closing quote.
+func readQuoted(runes []rune, start int) (string, int, error) {
+	for end := start + 1; end < len(runes); end++ {
+		if runes[end] == '"' {
+			return string(runes[start+1 : end]), end + 1, nil
+		}
+	}
+	return "", 0, parseErrorf("unclosed quote")
+}
+
+func parseFilter(field string, value string) (*Filter, error) {
+	if value == "" {
+		return nil, parseErrorf("%s: needs a value", field)
+	}
+
+	filter := &Filter{Field: Field(field)}
+	switch filter.Field {
+	case FieldStatus:
+		return filter, parseEnum(filter, value, statuses)
+	case FieldPriority:
+		return filter, parseEnum(filter, value, priorities)
+	case FieldIs:
+		return filter, parseEnum(filter, value, states)
+	case FieldLabel:
+		values, err := splitValues(field, value)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range values {
+			filter.Values = append(filter.Values, strings.ToLower(label))
+		}
+		return filter, nil
+	case FieldBoard:
+		values, err := splitValues(field, value)
+		if err != nil {
+			return nil, err
+		}
+		for _, board := range values {
+			id, err := uuid.Parse(board)
+			if err != nil {
+				return nil, parseErrorf("board: %q is not a board ID", board)
+			}
+			filter.Boards = append(filter.Boards, id)
+		}
+		return filter, nil
+	case FieldAssignee:
+		values, err := splitValues(field, value)
+		if err != nil {
+			return nil, err
+		}
+		for _, assignee := range values {
+			lowered := strings.ToLower(assignee)
+			if id, err := uuid.Parse(assignee); err == nil {
+				filter.Assignees = append(filter.Assignees, id)
+			} else if lowered == AssigneeMe || lowered == AssigneeNone {
+				filter.Values = append(filter.Values, lowered)
+			} else if _, err := mail.ParseAddress(assignee); err == nil {
+				filter.Values = append(filter.Values, lowered)
+			} else {
+				return nil, parseErrorf("assignee: %q is not me, none, a user ID or an email", assignee)
+			}
+		}
+		return filter, nil
+	case FieldDue, FieldCreated:
+		op, bound, err := parseBound(field, value)
+		if err != nil {
+			return nil, err
+		}
+		filter.Op, filter.Bound = op, bound
+		return filter, nil
+	default:
+		return nil, parseErrorf("unknown filter %q; quote the term to search for it as text", field+":")
+	}
+}
+
+func parseEnum(filter *Filter, value string, allowed []string) error {
+	values, err := splitValues(string(filter.Field), value)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range values {
+		candidate = strings.ToLower(candidate)
+		if !contains(allowed, candidate) {
+			return parseErrorf("%s: %q is not one of %s", filter.Field, candidate, strings.Join(allowed, ", "))
+		}
+		filter.Values = append(filter.Values, candidate)
+	}
+	return nil
+}
+
+func splitValues(field string, value string) ([]string, error) {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	if len(values) == 0 {
+		return nil, parseErrorf("%s: needs a value", field)
+	}
+	if len(values) > maxValues {
+		return nil, parseErrorf("%s: takes at most %d values", field, maxValues)
+	}
+	return values, nil
+}
+
+// parseBound reads an optional comparison followed by a relative offset
+// (12h, 7d, 2w) or a date (2006-01-02). A relative offset without a
+// comparison means "within", as in due:7d.
+func parseBound(field string, value string) (Op, *Bound, error) {
+	op := OpEq
+	for _, candidate := range []Op{OpLte, OpGte, OpLt, OpGt, OpEq} {
+		if strings.HasPrefix(value, string(candidate)) {
+			op, value = candidate, value[len(candidate):]
+			break
+		}
+	}
+
+	if match := relativePattern.FindStringSubmatch(strings.ToLower(value)); match != nil {
+		amount, _ := strconv.Atoi(match[1])
+		unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[match[2]]
+		if op == OpEq {
+			op = OpLte
+		}
+		return op, &Bound{Relative: time.Duration(amount) * unit, IsRelative: true}, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return "", nil, parseErrorf("%s: %q is not an offset like 7d or a date like 2006-01-02", field, value)
+	}
+	return op, &Bound{Date: date}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParse(t *testing.T) {
+	boardID := uuid.MustParse("6f1c1a4e-3b8e-4d55-9a53-3a1f6b2c9d10")
+	userID := uuid.MustParse("0b7e4a52-9c1d-4f3e-8a2b-5d6c7e8f9a01")
+	day := func(value string) time.Time {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return date
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  *Query
+	}{
+		{
+			name:  "empty",
+			input: "   ",
+			want:  &Query{},
+		},
+		{
+			name:  "words",
+			input: "login bug",
+			want:  &Query{Text: []Text{{Value: "login"}, {Value: "bug"}}},
+		},
+		{
+			name:  "phrase",
+			input: `"login bug" crash`,
+			want:  &Query{Text: []Text{{Value: "login bug", Phrase: true}, {Value: "crash"}}},
+		},
+		{
+			name:  "empty phrase is dropped",
+			input: `"  " crash`,
+			want:  &Query{Text: []Text{{Value: "crash"}}},
+		},
+		{
+			name:  "negated word and phrase",
+			input: `-flaky -"known issue"`,
+			want:  &Query{Text: []Text{{Value: "flaky", Negate: true}, {Value: "known issue", Phrase: true, Negate: true}}},
+		},
+		{
+			name:  "lone dash is a word",
+			input: "a - b",
+			want:  &Query{Text: []Text{{Value: "a"}, {Value: "-"}, {Value: "b"}}},
+		},
+		{
+			name:  "comma list",
+			input: "status:todo,in_progress",
+			want:  &Query{Filters: []Filter{{Field: FieldStatus, Values: []string{"todo", "in_progress"}}}},
+		},
+		{
+			name:  "comma list skips empty values and lowercases",
+			input: "priority:,HIGH,,Low,",
+			want:  &Query{Filters: []Filter{{Field: FieldPriority, Values: []string{"high", "low"}}}},
+		},
+		{
+			name:  "negated filter",
+			input: "-label:wontfix",
+			want:  &Query{Filters: []Filter{{Field: FieldLabel, Negate: true, Values: []string{"wontfix"}}}},
+		},
+		{
+			name:  "quoted filter value",
+			input: `label:"needs review"`,
+			want:  &Query{Filters: []Filter{{Field: FieldLabel, Values: []string{"needs review"}}}},
+		},
+		{
+			name:  "field names are case-insensitive",
+			input: "IS:Overdue",
+			want:  &Query{Filters: []Filter{{Field: FieldIs, Values: []string{StateOverdue}}}},
+		},
+		{
+			name:  "assignee values",
+			input: "assignee:me,none,Ana@Example.com," + userID.String(),
+			want: &Query{Filters: []Filter{{
+				Field:     FieldAssignee,
+				Values:    []string{AssigneeMe, AssigneeNone, "ana@example.com"},
+				Assignees: []uuid.UUID{userID},
+			}}},
+		},
+		{
+			name:  "board",
+			input: "board:" + boardID.String(),
+			want:  &Query{Filters: []Filter{{Field: FieldBoard, Boards: []uuid.UUID{boardID}}}},
+		},
+		{
+			name:  "due within a relative offset",
+			input: "due:<7d",
+			want:  &Query{Filters: []Filter{{Field: FieldDue, Op: OpLt, Bound: &Bound{Relative: 7 * 24 * time.Hour, IsRelative: true}}}},
+		},
+		{
+			name:  "relative offset without a comparison means within",
+			input: "due:12h",
+			want:  &Query{Filters: []Filter{{Field: FieldDue, Op: OpLte, Bound: &Bound{Relative: 12 * time.Hour, IsRelative: true}}}},
+		},
+		{
+			name:  "weeks",
+			input: "created:>=2W",
+			want:  &Query{Filters: []Filter{{Field: FieldCreated, Op: OpGte, Bound: &Bound{Relative: 14 * 24 * time.Hour, IsRelative: true}}}},
+		},
+		{
+			name:  "created since a date",
+			input: "created:>=2026-01-01",
+			want:  &Query{Filters: []Filter{{Field: FieldCreated, Op: OpGte, Bound: &Bound{Date: day("2026-01-01")}}}},
+		},
+		{
+			name:  "date without a comparison",
+			input: "due:2026-02-28",
+			want:  &Query{Filters: []Filter{{Field: FieldDue, Op: OpEq, Bound: &Bound{Date: day("2026-02-28")}}}},
+		},
+		{
+			name:  "mixed",
+			input: `status:todo "login bug" -is:archived crash`,
+			want: &Query{
+				Filters: []Filter{
+					{Field: FieldStatus, Values: []string{"todo"}},
+					{Field: FieldIs, Negate: true, Values: []string{StateArchived}},
+				},
+				Text: []Text{{Value: "login bug", Phrase: true}, {Value: "crash"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Parse(test.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", test.input, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unclosed phrase", `"login bug`, "unclosed quote"},
+		{"unclosed filter value", `label:"needs review`, "unclosed quote"},
+		{"unknown field", "owner:me", `unknown filter "owner:"`},
+		{"missing value", "status:", "status: needs a value"},
+		{"only commas", "label:,,", "label: needs a value"},
+		{"bad status", "status:blocked", `status: "blocked" is not one of todo, in_progress, done`},
+		{"bad state", "is:open", `is: "open" is not one of`},
+		{"bad board", "board:roadmap", `board: "roadmap" is not a board ID`},
+		{"bad assignee", "assignee:ana", `assignee: "ana" is not me, none, a user ID or an email`},
+		{"bad date", "due:<2026-13-01", `due: "2026-13-01" is not an offset`},
+		{"bad offset unit", "due:7m", `due: "7m" is not an offset`},
+		{"offset too long", "due:12345d", `due: "12345d" is not an offset`},
+		{"too many values", "label:" + strings.Repeat("a,", maxValues) + "a", "label: takes at most 20 values"},
+		{"too many terms", strings.Repeat("word ", maxTerms+1), "query has more than 20 terms"},
+		{"too long", strings.Repeat("a", maxQueryLength+1), "query is longer than 500 characters"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want an error containing %q", test.input, test.want)
+			}
+			if _, ok := err.(*ParseError); !ok {
+				t.Errorf("Parse(%q) error is %T, want *ParseError", test.input, err)
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", test.input, err, test.want)
+			}
+		})
+	}
+}
+
+func TestParseLimitsAreInclusive(t *testing.T) {
+	inputs := []string{
+		strings.Repeat("word ", maxTerms),
+		"label:" + strings.Repeat("a,", maxValues-1) + "a",
+		strings.Repeat("a", maxQueryLength),
+	}
+	for _, input := range inputs {
+		if _, err := Parse(input); err != nil {
+			t.Errorf("Parse(%.40q...): %v", input, err)
+		}
+	}
+}
+
+func TestQueryHas(t *testing.T) {
+	query, err := Parse("is:archived -is:overdue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !query.Has(FieldIs, StateArchived) {
+		t.Error("Has(is, archived) = false, want true")
+	}
+	if query.Has(FieldIs, StateOverdue) {
+		t.Error("Has(is, overdue) = true for a negated filter, want false")
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"regexp"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ActivityService interface {
+	GetTaskActivity(taskID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error)
+	GetTaskBoardActivity(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error)
+}
+
+type ActivityServiceImpl struct {
+	activityRepo repositories.TaskActivityRepository
+	logger       *zap.Logger
+}
+
+func NewActivityService(activityRepo repositories.TaskActivityRepository, logger *zap.Logger) *ActivityServiceImpl {
+	return &ActivityServiceImpl{
+		activityRepo: activityRepo,
+		logger:       logger,
+	}
+}
+
+// GetTaskActivity lists the task's timeline.
+func (service *ActivityServiceImpl) GetTaskActivity(taskID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error) {
+	activities, page, err := service.activityRepo.FindByTaskID(taskID, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	addDescriptionDiffs(activities)
+	return activities, page, nil
+}
+
+// GetTaskBoardActivity lists the activity of every task on the board.
+func (service *ActivityServiceImpl) GetTaskBoardActivity(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskActivity, *pagination.Page, error) {
+	activities, page, err := service.activityRepo.FindByTaskBoardID(taskBoardID, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	addDescriptionDiffs(activities)
+	return activities, page, nil
+}
+
+func addDescriptionDiffs(activities []models.TaskActivity) {
+	for i := range activities {
+		activity := &activities[i]
+		if activity.Kind != models.ActivityChanged || activity.Field != "description" {
+			continue
+		}
+		var from, to string
+		if activity.OldValue != nil {
+			from = *activity.OldValue
+		}
+		if activity.NewValue != nil {
+			to = *activity.NewValue
+		}
+		activity.Diff = textDiff(from, to)
+	}
+}
+
+// diffTokens splits text into words and the whitespace between them, so the
+// kept and deleted runs of a diff join back into the old text and the kept
+// and inserted runs into the new one.
+var diffTokens = regexp.MustCompile(`\s+|\S+`)
+
+// maxDiffCells bounds the work of a diff; longer edits show as a whole
+// deletion followed by a whole insertion.
+const maxDiffCells = 1 << 20
+
+// textDiff compares two texts word by word.
+func textDiff(from string, to string) []models.TextDiff {
+	a := diffTokens.FindAllString(from, -1)
+	b := diffTokens.FindAllString(to, -1)
+
+	var diff []models.TextDiff
+	add := func(op string, text string) {
+		if last := len(diff) - 1; last >= 0 && diff[last].Op == op {
+			diff[last].Text += text
+			return
+		}
+		diff = append(diff, models.TextDiff{Op: op, Text: text})
+	}
+
+	if len(a)*len(b) > maxDiffCells {
+		if from != "" {
+			add("delete", from)
+		}
+		if to != "" {
+			add("insert", to)
+		}
+		return diff
+	}
+
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:].
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add("equal", a[i])
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			add("delete", a[i])
+			i++
+		default:
+			add("insert", b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add("delete", a[i])
+	}
+	for ; j < len(b); j++ {
+		add("insert", b[j])
+	}
+	return diff
+}
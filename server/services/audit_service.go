@@ -0,0 +1,252 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type AuditService interface {
+	GetTaskBoardAuditLog(taskBoardID uuid.UUID, query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error)
+	GetWorkspaceAuditLog(workspaceID uuid.UUID, query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error)
+	GetUserAuditLog(userID uuid.UUID, query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error)
+}
+
+type AuditServiceImpl struct {
+	auditRepo repositories.AuditLogRepository
+	logger    *zap.Logger
+}
+
+func NewAuditService(auditRepo repositories.AuditLogRepository, logger *zap.Logger) *AuditServiceImpl {
+	return &AuditServiceImpl{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// GetTaskBoardAuditLog lists the changes made to the board and everything on
+// it.
+func (service *AuditServiceImpl) GetTaskBoardAuditLog(taskBoardID uuid.UUID, query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+	filter, err := auditLogFilter(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return service.auditRepo.FindByTaskBoardID(taskBoardID, filter, params)
+}
+
+// GetWorkspaceAuditLog lists the changes made across the workspace's boards.
+func (service *AuditServiceImpl) GetWorkspaceAuditLog(workspaceID uuid.UUID, query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+	filter, err := auditLogFilter(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return service.auditRepo.FindByWorkspaceID(workspaceID, filter, params)
+}
+
+// GetUserAuditLog lists what the user did, sign-ins included.
+func (service *AuditServiceImpl) GetUserAuditLog(userID uuid.UUID, query *dto.AuditLogQuery, params *pagination.Params) ([]models.AuditLog, *pagination.Page, error) {
+	filter, err := auditLogFilter(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return service.auditRepo.FindByActorID(userID, filter, params)
+}
+
+func auditLogFilter(query *dto.AuditLogQuery) (repositories.AuditLogFilter, error) {
+	filter := repositories.AuditLogFilter{EntityType: query.EntityType}
+	if query.ActorID != "" {
+		actorID := uuid.MustParse(query.ActorID)
+		filter.ActorID = &actorID
+	}
+	if query.EntityID != "" {
+		entityID := uuid.MustParse(query.EntityID)
+		filter.EntityID = &entityID
+	}
+	if query.TaskBoardID != "" {
+		taskBoardID := uuid.MustParse(query.TaskBoardID)
+		filter.TaskBoardID = &taskBoardID
+	}
+	for _, action := range strings.Split(query.Action, ",") {
+		if action = strings.TrimSpace(action); action != "" {
+			filter.Actions = append(filter.Actions, action)
+		}
+	}
+	if query.From != "" {
+		from, _ := time.Parse(time.RFC3339, query.From)
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, _ := time.Parse(time.RFC3339, query.To)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, &ValidationError{message: "from must be before to"}
+	}
+	return filter, nil
+}
+
+// auditFields is the audited state of an entity, keyed by field name.
+type auditFields map[string]interface{}
+
+// auditEntry describes a change for writeAudit. before is nil for a created
+// entity and after for a deleted one.
+type auditEntry struct {
+	action      string
+	actorID     *uuid.UUID
+	entityType  string
+	entityID    *uuid.UUID
+	taskBoardID *uuid.UUID
+	before      auditFields
+	after       auditFields
+}
+
+// writeAudit appends the change to the audit log through auditRepo, which
+// should be bound to the transaction that made the change so the two commit
+// or roll back together.
+func writeAudit(auditRepo repositories.AuditLogRepository, meta models.RequestMeta, entry auditEntry) error {
+	changes, err := diffAuditFields(entry.before, entry.after)
+	if err != nil {
+		return err
+	}
+	userAgent := meta.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	return auditRepo.Create(&models.AuditLog{
+		ActorID:     entry.actorID,
+		Action:      entry.action,
+		EntityType:  entry.entityType,
+		EntityID:    entry.entityID,
+		TaskBoardID: entry.taskBoardID,
+		Changes:     changes,
+		IPAddress:   meta.IPAddress,
+		UserAgent:   userAgent,
+		RequestID:   meta.RequestID,
+	})
+}
+
+// diffAuditFields keeps the fields whose JSON differs between before and
+// after. A field missing on one side and null or empty on the other is
+// unchanged.
+func diffAuditFields(before auditFields, after auditFields) (models.AuditChanges, error) {
+	changes := models.AuditChanges{}
+	for _, fields := range []auditFields{before, after} {
+		for field := range fields {
+			if _, done := changes[field]; done {
+				continue
+			}
+			from, err := auditJSON(before, field)
+			if err != nil {
+				return nil, err
+			}
+			to, err := auditJSON(after, field)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Equal(from, to) {
+				continue
+			}
+			changes[field] = models.AuditChange{From: from, To: to}
+		}
+	}
+	return changes, nil
+}
+
+// auditJSON returns the field's JSON, or nil when it is missing, null or an
+// empty list.
+func auditJSON(fields auditFields, field string) (json.RawMessage, error) {
+	value, ok := fields[field]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" || string(data) == "[]" {
+		return nil, err
+	}
+	return data, nil
+}
+
+// auditTime normalizes a time to what the database keeps, so that a value
+// read back compares equal to the one written.
+func auditTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	normalized := t.UTC().Truncate(time.Microsecond)
+	return &normalized
+}
+
+func taskAuditFields(task *models.Task) auditFields {
+	if task == nil {
+		return nil
+	}
+	labelIDs := make([]string, 0, len(task.Labels))
+	for _, label := range task.Labels {
+		labelIDs = append(labelIDs, label.ID.String())
+	}
+	sort.Strings(labelIDs)
+
+	fields := auditFields{
+		"task_board_id":    task.TaskBoardID,
+		"title":            task.Title,
+		"description":      task.Description,
+		"status":           task.Status,
+		"priority":         task.Priority,
+		"start_date":       auditTime(&task.StartDate),
+		"end_date":         auditTime(&task.EndDate),
+		"estimate_minutes": task.EstimateMinutes,
+		"assignee_id":      task.AssigneeID,
+		"archived_at":      auditTime(task.ArchivedAt),
+		"labels":           labelIDs,
+	}
+	for _, value := range task.CustomFieldValues {
+		key := "cf:" + value.CustomFieldID.String()
+		switch {
+		case value.TextValue != nil:
+			fields[key] = *value.TextValue
+		case value.NumberValue != nil:
+			fields[key] = *value.NumberValue
+		case value.DateValue != nil:
+			fields[key] = auditTime(value.DateValue)
+		}
+	}
+	return fields
+}
+
+func taskBoardAuditFields(taskBoard *models.TaskBoard) auditFields {
+	if taskBoard == nil {
+		return nil
+	}
+	return auditFields{
+		"title":        taskBoard.Title,
+		"description":  taskBoard.Description,
+		"workspace_id": taskBoard.WorkspaceID,
+		"archived_at":  auditTime(taskBoard.ArchivedAt),
+	}
+}
+
+func collaboratorAuditFields(role string, boardRoleID *uuid.UUID) auditFields {
+	return auditFields{"role": role, "board_role_id": boardRoleID}
+}
+
+// wipLimitAuditFields keys the limits by status.
+func wipLimitAuditFields(limits []models.TaskBoardStatusLimit) auditFields {
+	fields := auditFields{}
+	for _, limit := range limits {
+		fields[limit.Status] = limit.Limit
+	}
+	return fields
+}
+
+func userAuditFields(user *models.User) auditFields {
+	return auditFields{"name": user.Name, "email": user.Email}
+}
@@ -2,36 +2,43 @@ package services
 
 import (
 	"fmt"
+	"server/models"
 	"server/repositories"
 	"server/utils"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type AuthService interface {
-	Login(email, password string) (string, error)
+	Login(email, password string, meta models.RequestMeta) (string, error)
 }
 
 type AuthServiceImpl struct {
-	userRepo repositories.UserRepository
-	logger   *zap.Logger
+	userRepo  repositories.UserRepository
+	auditRepo repositories.AuditLogRepository
+	logger    *zap.Logger
 }
 
-func NewAuthService(userRepo repositories.UserRepository, logger *zap.Logger) AuthService {
+func NewAuthService(userRepo repositories.UserRepository, auditRepo repositories.AuditLogRepository, logger *zap.Logger) AuthService {
 	return &AuthServiceImpl{
-		userRepo: userRepo,
-		logger:   logger,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		logger:    logger,
 	}
 }
 
-func (s *AuthServiceImpl) Login(email, password string) (string, error) {
+// Login signs the user in. Every attempt on an existing account is audited,
+// whether the password was right or not.
+func (s *AuthServiceImpl) Login(email, password string, meta models.RequestMeta) (string, error) {
 	if email == "" || password == "" {
 		return "", fmt.Errorf("email and password are required")
 	}
 
 	user, err := s.userRepo.FindByEmail(email)
 
-	if err != nil {
+	// FindByEmail returns no user and no error for an unknown email.
+	if err != nil || user == nil {
 		s.logger.Warn("Failed to find user by email", zap.String("email", email), zap.Error(err))
 		return "", fmt.Errorf("email or password is incorrect")
 	}
@@ -39,6 +46,7 @@ func (s *AuthServiceImpl) Login(email, password string) (string, error) {
 	passwordMatch := utils.VerifyPassword(user.Password, password)
 	
 	if !passwordMatch {
+		s.audit(models.AuditUserLoginRejected, user.ID, meta)
 		return "", fmt.Errorf("email or password is incorrect")
 	}
 
@@ -52,7 +60,22 @@ func (s *AuthServiceImpl) Login(email, password string) (string, error) {
 		return "", fmt.Errorf("authentication failed")
 	}
 
+	s.audit(models.AuditUserLogin, user.ID, meta)
 	s.logger.Info("User logged in successfully", zap.String("email", email))
 
 	return token, nil
 }
+
+// audit records a sign-in attempt. Signing in changes nothing, so a failure to
+// record is logged but does not change the response.
+func (s *AuthServiceImpl) audit(action string, userID uuid.UUID, meta models.RequestMeta) {
+	err := writeAudit(s.auditRepo, meta, auditEntry{
+		action:     action,
+		actorID:    &userID,
+		entityType: models.AuditEntityUser,
+		entityID:   &userID,
+	})
+	if err != nil {
+		s.logger.Error("Error recording sign-in", zap.String("userID", userID.String()), zap.Error(err))
+	}
+}
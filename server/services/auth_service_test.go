@@ -0,0 +1,31 @@
+package services
+
+import (
+	"server/models"
+	"server/repositories"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// emailUserRepository finds the users it holds by email, and no one else.
+type emailUserRepository struct {
+	repositories.UserRepository
+	users map[string]*models.User
+}
+
+func (repo *emailUserRepository) FindByEmail(email string) (*models.User, error) {
+	return repo.users[email], nil
+}
+
+func TestLoginWithUnknownEmail(t *testing.T) {
+	service := NewAuthService(&emailUserRepository{}, nil, zap.NewNop())
+
+	token, err := service.Login("nobody@example.com", "secret", models.RequestMeta{})
+	if err == nil || err.Error() != "email or password is incorrect" {
+		t.Errorf("Login error = %v, want email or password is incorrect", err)
+	}
+	if token != "" {
+		t.Errorf("Login returned token %q", token)
+	}
+}
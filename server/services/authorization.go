@@ -0,0 +1,34 @@
+package services
+
+import (
+	"fmt"
+	"server/policy"
+	"server/repositories"
+
+	"github.com/google/uuid"
+)
+
+// subjectOn describes the actor by the most privileged role they hold on the
+// board, directly, through a team or through their workspace, and the
+// permissions their grants carry. Users without access get an empty role,
+// which the policy never grants anything.
+func subjectOn(taskBoardRepo repositories.TaskBoardRepository, taskBoardID uuid.UUID, actorID uuid.UUID) policy.Subject {
+	userTaskBoard, err := taskBoardRepo.CheckUserRole(taskBoardID, actorID)
+	if err != nil {
+		return policy.Subject{UserID: actorID}
+	}
+	return policy.NewSubject(actorID, userTaskBoard.Role, userTaskBoard.CustomPermissions())
+}
+
+// authorize returns a ForbiddenError unless the policy lets the actor perform
+// action on resource.
+func authorize(engine *policy.Engine, taskBoardRepo repositories.TaskBoardRepository, actorID uuid.UUID, action policy.Action, resource policy.Resource) error {
+	if !engine.Allows(subjectOn(taskBoardRepo, resource.TaskBoardID, actorID), action, resource) {
+		return forbidden(action)
+	}
+	return nil
+}
+
+func forbidden(action policy.Action) *ForbiddenError {
+	return &ForbiddenError{message: fmt.Sprintf("%s is not permitted on this task board", action)}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/policy"
+	"server/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type BoardRoleService interface {
+	GetRoles(taskBoardID uuid.UUID) ([]models.BoardRole, error)
+	CreateRole(taskBoardID uuid.UUID, roleDTO *dto.BoardRoleRequest) (*models.BoardRole, error)
+	UpdateRole(taskBoardID uuid.UUID, boardRoleID uuid.UUID, roleDTO *dto.BoardRoleRequest) (*models.BoardRole, error)
+	DeleteRole(taskBoardID uuid.UUID, boardRoleID uuid.UUID) error
+}
+
+type BoardRoleServiceImpl struct {
+	boardRoleRepo repositories.BoardRoleRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	logger        *zap.Logger
+}
+
+func NewBoardRoleService(
+	boardRoleRepo repositories.BoardRoleRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	logger *zap.Logger,
+) *BoardRoleServiceImpl {
+	return &BoardRoleServiceImpl{
+		boardRoleRepo: boardRoleRepo,
+		taskBoardRepo: taskBoardRepo,
+		logger:        logger,
+	}
+}
+
+// GetRoles lists the preset roles followed by the board's custom roles.
+func (service *BoardRoleServiceImpl) GetRoles(taskBoardID uuid.UUID) ([]models.BoardRole, error) {
+	custom, err := service.boardRoleRepo.FindByTaskBoardID(taskBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]models.BoardRole, 0, len(policy.Roles)+len(custom))
+	for _, name := range policy.Roles {
+		permissions := make(models.StringList, 0, len(policy.Presets[name]))
+		for _, permission := range policy.Presets[name] {
+			permissions = append(permissions, string(permission))
+		}
+		roles = append(roles, models.BoardRole{
+			TaskBoardID: taskBoardID,
+			Name:        name,
+			Permissions: permissions,
+			Builtin:     true,
+		})
+	}
+	return append(roles, custom...), nil
+}
+
+func (service *BoardRoleServiceImpl) CreateRole(taskBoardID uuid.UUID, roleDTO *dto.BoardRoleRequest) (*models.BoardRole, error) {
+	if _, err := service.taskBoardRepo.FindByID(taskBoardID); err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+
+	boardRole := &models.BoardRole{TaskBoardID: taskBoardID}
+	if err := applyBoardRole(boardRole, roleDTO); err != nil {
+		return nil, err
+	}
+
+	created, err := service.boardRoleRepo.Create(boardRole)
+	if err != nil {
+		service.logger.Error("Error creating board role", zap.Error(err))
+		return nil, boardRoleConflict(boardRole.Name, err)
+	}
+	return created, nil
+}
+
+// UpdateRole renames the role or changes its permissions; members holding it
+// are affected immediately.
+func (service *BoardRoleServiceImpl) UpdateRole(taskBoardID uuid.UUID, boardRoleID uuid.UUID, roleDTO *dto.BoardRoleRequest) (*models.BoardRole, error) {
+	boardRole, err := service.findBoardRole(taskBoardID, boardRoleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyBoardRole(boardRole, roleDTO); err != nil {
+		return nil, err
+	}
+
+	updated, err := service.boardRoleRepo.Update(boardRole)
+	if err != nil {
+		service.logger.Error("Error updating board role", zap.Error(err))
+		return nil, boardRoleConflict(boardRole.Name, err)
+	}
+	return updated, nil
+}
+
+// DeleteRole refuses while collaborators, teams or pending invitations still
+// hold the role.
+func (service *BoardRoleServiceImpl) DeleteRole(taskBoardID uuid.UUID, boardRoleID uuid.UUID) error {
+	if _, err := service.findBoardRole(taskBoardID, boardRoleID); err != nil {
+		return err
+	}
+
+	members, err := service.boardRoleRepo.CountMembers(boardRoleID)
+	if err != nil {
+		return err
+	}
+	if members > 0 {
+		return &ConflictError{message: fmt.Sprintf("%d collaborators, teams or invitations still hold this role; assign them another role first", members)}
+	}
+	return service.boardRoleRepo.Delete(boardRoleID)
+}
+
+func (service *BoardRoleServiceImpl) findBoardRole(taskBoardID uuid.UUID, boardRoleID uuid.UUID) (*models.BoardRole, error) {
+	boardRole, err := service.boardRoleRepo.FindByID(boardRoleID)
+	if err != nil || boardRole.TaskBoardID != taskBoardID {
+		return nil, fmt.Errorf("role not found")
+	}
+	return boardRole, nil
+}
+
+// applyBoardRole validates the request against the catalogue and copies it
+// onto the role. Preset names are reserved.
+func applyBoardRole(boardRole *models.BoardRole, roleDTO *dto.BoardRoleRequest) error {
+	name := strings.TrimSpace(roleDTO.Name)
+	if _, ok := policy.Presets[strings.ToLower(name)]; ok || strings.EqualFold(name, policy.RoleCustom) {
+		return &ValidationError{message: fmt.Sprintf("%q is a reserved role name", name)}
+	}
+
+	permissions := models.StringList{}
+	seen := make(map[string]bool)
+	for _, permission := range roleDTO.Permissions {
+		if !policy.IsPermission(permission) {
+			return &ValidationError{message: fmt.Sprintf("unknown permission %q", permission)}
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+
+	boardRole.Name = name
+	boardRole.Permissions = permissions
+	return nil
+}
+
+func boardRoleConflict(name string, err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+		return &ConflictError{message: fmt.Sprintf("role %q already exists on this task board", name)}
+	}
+	return err
+}
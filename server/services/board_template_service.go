@@ -0,0 +1,361 @@
+package services
+
+import (
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type BoardTemplateService interface {
+	CloneTaskBoard(sourceID uuid.UUID, cloneDTO *dto.CloneTaskBoardRequest, actorID uuid.UUID) (*models.TaskBoard, error)
+	GetTemplates(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+}
+
+type BoardTemplateServiceImpl struct {
+	taskBoardRepo   repositories.TaskBoardRepository
+	taskRepo        repositories.TaskRepository
+	customFieldRepo repositories.CustomFieldRepository
+	labelRepo       repositories.LabelRepository
+	boardRoleRepo   repositories.BoardRoleRepository
+	teamRepo        repositories.TeamRepository
+	workspaceRepo   repositories.WorkspaceRepository
+	transactor      repositories.Transactor
+	engine          *policy.Engine
+	logger          *zap.Logger
+}
+
+func NewBoardTemplateService(
+	taskBoardRepo repositories.TaskBoardRepository,
+	taskRepo repositories.TaskRepository,
+	customFieldRepo repositories.CustomFieldRepository,
+	labelRepo repositories.LabelRepository,
+	boardRoleRepo repositories.BoardRoleRepository,
+	teamRepo repositories.TeamRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	transactor repositories.Transactor,
+	engine *policy.Engine,
+	logger *zap.Logger,
+) *BoardTemplateServiceImpl {
+	return &BoardTemplateServiceImpl{
+		taskBoardRepo:   taskBoardRepo,
+		taskRepo:        taskRepo,
+		customFieldRepo: customFieldRepo,
+		labelRepo:       labelRepo,
+		boardRoleRepo:   boardRoleRepo,
+		teamRepo:        teamRepo,
+		workspaceRepo:   workspaceRepo,
+		transactor:      transactor,
+		engine:          engine,
+		logger:          logger,
+	}
+}
+
+// boardSnapshot is everything read from the source board before cloning.
+type boardSnapshot struct {
+	board         *models.TaskBoard
+	limits        []models.TaskBoardStatusLimit
+	fields        []models.CustomField
+	labels        []models.Label
+	roles         []models.BoardRole
+	collaborators []models.UserTaskBoard
+	teams         []models.TaskBoardTeam
+}
+
+// CloneTaskBoard copies a board, or creates a board from a template, in one
+// transaction: either the whole copy exists afterwards or nothing does.
+// Cloning takes board.clone on the source; copying the collaborators also
+// takes member.manage there. Templates are shared with everyone who can open
+// them, which includes members of the template's workspace.
+func (service *BoardTemplateServiceImpl) CloneTaskBoard(sourceID uuid.UUID, cloneDTO *dto.CloneTaskBoardRequest, actorID uuid.UUID) (*models.TaskBoard, error) {
+	subject := subjectOn(service.taskBoardRepo, sourceID, actorID)
+	source := policy.Resource{TaskBoardID: sourceID}
+	if !service.engine.Allows(subject, policy.BoardClone, source) {
+		return nil, forbidden(policy.BoardClone)
+	}
+	if cloneDTO.IncludeCollaborators && !service.engine.Allows(subject, policy.MemberManage, source) {
+		return nil, forbidden(policy.MemberManage)
+	}
+
+	workspaceID, err := boardWorkspace(service.workspaceRepo, cloneDTO.WorkspaceID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := service.snapshot(sourceID, cloneDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	shift, err := dateShift(snapshot.board.Tasks, cloneDTO.StartOn)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(cloneDTO.Title)
+	if title == "" {
+		title = snapshot.board.Title
+		if !snapshot.board.IsTemplate && !cloneDTO.AsTemplate {
+			title += " (copy)"
+		}
+	}
+
+	var cloneID uuid.UUID
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		taskBoardRepo := service.taskBoardRepo.WithTx(tx)
+
+		clone, err := taskBoardRepo.Create(&models.TaskBoard{
+			Title:       title,
+			Description: snapshot.board.Description,
+			IsTemplate:  cloneDTO.AsTemplate,
+			WorkspaceID: &workspaceID,
+		})
+		if err != nil {
+			return err
+		}
+		cloneID = clone.ID
+
+		roleIDs, err := service.cloneBoardRoles(tx, clone.ID, snapshot.roles)
+		if err != nil {
+			return err
+		}
+
+		members := map[uuid.UUID]bool{actorID: true}
+		if _, err := taskBoardRepo.CreateUserBoard(&models.UserTaskBoard{UserID: actorID, TaskBoardID: clone.ID, Role: policy.RoleOwner}); err != nil {
+			return err
+		}
+		for _, collaborator := range snapshot.collaborators {
+			if members[collaborator.UserID] {
+				continue
+			}
+			members[collaborator.UserID] = true
+			var boardRoleID *uuid.UUID
+			if collaborator.BoardRoleID != nil {
+				mapped := roleIDs[*collaborator.BoardRoleID]
+				boardRoleID = &mapped
+			}
+			if _, err := taskBoardRepo.AddCollaborator(collaborator.UserID, clone.ID, repositories.Role(collaborator.Role), boardRoleID); err != nil {
+				return err
+			}
+		}
+		// Teams belong to a workspace, so their grants only follow a copy
+		// that stays in the source's workspace.
+		if snapshot.board.WorkspaceID != nil && *snapshot.board.WorkspaceID == workspaceID {
+			teamRepo := service.teamRepo.WithTx(tx)
+			for _, grant := range snapshot.teams {
+				var boardRoleID *uuid.UUID
+				if grant.BoardRoleID != nil {
+					mapped := roleIDs[*grant.BoardRoleID]
+					boardRoleID = &mapped
+				}
+				if _, err := teamRepo.GrantBoard(&models.TaskBoardTeam{TaskBoardID: clone.ID, TeamID: grant.TeamID, Role: grant.Role, BoardRoleID: boardRoleID}); err != nil {
+					return err
+				}
+			}
+		}
+
+		limits := make([]models.TaskBoardStatusLimit, 0, len(snapshot.limits))
+		for _, limit := range snapshot.limits {
+			limits = append(limits, models.TaskBoardStatusLimit{Status: limit.Status, Limit: limit.Limit})
+		}
+		if err := taskBoardRepo.SetStatusLimits(clone.ID, limits); err != nil {
+			return err
+		}
+
+		fieldIDs, err := service.cloneCustomFields(tx, clone.ID, snapshot.fields)
+		if err != nil {
+			return err
+		}
+		labels, err := service.cloneLabels(tx, clone.ID, snapshot.labels)
+		if err != nil {
+			return err
+		}
+
+		taskRepo := service.taskRepo.WithTx(tx)
+		for _, task := range snapshot.board.Tasks {
+			if _, err := taskRepo.Create(cloneTask(task, clone.ID, actorID, shift, fieldIDs, labels, members)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		service.logger.Error("Error cloning task board", zap.String("taskBoardID", sourceID.String()), zap.Error(err))
+		return nil, err
+	}
+
+	service.logger.Info("Task board cloned",
+		zap.String("fromTaskBoardID", sourceID.String()),
+		zap.String("toTaskBoardID", cloneID.String()),
+		zap.Bool("template", cloneDTO.AsTemplate),
+	)
+
+	cloned, _, err := service.taskBoardRepo.FindByIDWithFilter(cloneID, repositories.TaskFilter{}, nil)
+	return cloned, err
+}
+
+func (service *BoardTemplateServiceImpl) GetTemplates(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	return service.taskBoardRepo.FindTemplatesByUserID(userID, params)
+}
+
+func (service *BoardTemplateServiceImpl) snapshot(sourceID uuid.UUID, cloneDTO *dto.CloneTaskBoardRequest) (*boardSnapshot, error) {
+	board, _, err := service.taskBoardRepo.FindByIDWithFilter(sourceID, repositories.TaskFilter{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+	if !cloneDTO.IncludeTasks {
+		board.Tasks = nil
+	}
+
+	snapshot := &boardSnapshot{board: board}
+	if snapshot.limits, err = service.taskBoardRepo.GetStatusLimits(sourceID); err != nil {
+		return nil, err
+	}
+	if snapshot.fields, err = service.customFieldRepo.FindByTaskBoardID(sourceID); err != nil {
+		return nil, err
+	}
+	if snapshot.labels, err = service.labelRepo.FindByTaskBoardID(sourceID); err != nil {
+		return nil, err
+	}
+	if snapshot.roles, err = service.boardRoleRepo.FindByTaskBoardID(sourceID); err != nil {
+		return nil, err
+	}
+	if cloneDTO.IncludeCollaborators {
+		if snapshot.collaborators, err = service.taskBoardRepo.FindCollaborators(sourceID); err != nil {
+			return nil, err
+		}
+		if snapshot.teams, _, err = service.teamRepo.FindBoardGrants(sourceID, pagination.All(repositories.BoardTeamPages)); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// cloneCustomFields copies the field definitions and maps old field IDs to new ones.
+func (service *BoardTemplateServiceImpl) cloneCustomFields(tx *gorm.DB, taskBoardID uuid.UUID, fields []models.CustomField) (map[uuid.UUID]uuid.UUID, error) {
+	customFieldRepo := service.customFieldRepo.WithTx(tx)
+	fieldIDs := make(map[uuid.UUID]uuid.UUID, len(fields))
+	for _, field := range fields {
+		created, err := customFieldRepo.Create(&models.CustomField{
+			TaskBoardID: taskBoardID,
+			Name:        field.Name,
+			Type:        field.Type,
+			Options:     field.Options,
+			Required:    field.Required,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fieldIDs[field.ID] = created.ID
+	}
+	return fieldIDs, nil
+}
+
+// cloneBoardRoles copies the custom roles and maps old role IDs to new ones.
+func (service *BoardTemplateServiceImpl) cloneBoardRoles(tx *gorm.DB, taskBoardID uuid.UUID, roles []models.BoardRole) (map[uuid.UUID]uuid.UUID, error) {
+	boardRoleRepo := service.boardRoleRepo.WithTx(tx)
+	roleIDs := make(map[uuid.UUID]uuid.UUID, len(roles))
+	for _, role := range roles {
+		created, err := boardRoleRepo.Create(&models.BoardRole{
+			TaskBoardID: taskBoardID,
+			Name:        role.Name,
+			Permissions: role.Permissions,
+		})
+		if err != nil {
+			return nil, err
+		}
+		roleIDs[role.ID] = created.ID
+	}
+	return roleIDs, nil
+}
+
+// cloneLabels copies the labels and maps old label IDs to the new labels.
+func (service *BoardTemplateServiceImpl) cloneLabels(tx *gorm.DB, taskBoardID uuid.UUID, labels []models.Label) (map[uuid.UUID]models.Label, error) {
+	labelRepo := service.labelRepo.WithTx(tx)
+	cloned := make(map[uuid.UUID]models.Label, len(labels))
+	for _, label := range labels {
+		created, err := labelRepo.Create(&models.Label{
+			TaskBoardID: taskBoardID,
+			Name:        label.Name,
+			Color:       label.Color,
+		})
+		if err != nil {
+			return nil, err
+		}
+		cloned[label.ID] = *created
+	}
+	return cloned, nil
+}
+
+// cloneTask copies a task onto the new board with shifted dates and remapped
+// custom field values and labels. The copy counts as created by the user
+// cloning, and the assignee is kept only when they are a member of the new
+// board.
+func cloneTask(task models.Task, taskBoardID uuid.UUID, createdByID uuid.UUID, shift time.Duration, fieldIDs map[uuid.UUID]uuid.UUID, labels map[uuid.UUID]models.Label, members map[uuid.UUID]bool) *models.Task {
+	clone := &models.Task{
+		TaskBoardID:     taskBoardID,
+		Title:           task.Title,
+		Description:     task.Description,
+		Status:          task.Status,
+		Priority:        task.Priority,
+		StartDate:       task.StartDate.Add(shift),
+		EndDate:         task.EndDate.Add(shift),
+		EstimateMinutes: task.EstimateMinutes,
+		CreatedByID:     &createdByID,
+	}
+	if task.AssigneeID != nil && members[*task.AssigneeID] {
+		clone.AssigneeID = task.AssigneeID
+	}
+
+	for _, value := range task.CustomFieldValues {
+		fieldID, ok := fieldIDs[value.CustomFieldID]
+		if !ok {
+			continue
+		}
+		clone.CustomFieldValues = append(clone.CustomFieldValues, models.TaskCustomFieldValue{
+			CustomFieldID: fieldID,
+			TextValue:     value.TextValue,
+			NumberValue:   value.NumberValue,
+			DateValue:     value.DateValue,
+		})
+	}
+
+	for _, label := range task.Labels {
+		if cloned, ok := labels[label.ID]; ok {
+			clone.Labels = append(clone.Labels, cloned)
+		}
+	}
+	return clone
+}
+
+// dateShift returns how far to move task dates so that the earliest start
+// falls on startOn, or zero when no start day was asked for.
+func dateShift(tasks []models.Task, startOn string) (time.Duration, error) {
+	if startOn == "" || len(tasks) == 0 {
+		return 0, nil
+	}
+
+	day, err := time.Parse("2006-01-02", startOn)
+	if err != nil {
+		return 0, &ValidationError{message: "start_on must look like 2006-01-02"}
+	}
+
+	earliest := tasks[0].StartDate
+	for _, task := range tasks[1:] {
+		if task.StartDate.Before(earliest) {
+			earliest = task.StartDate
+		}
+	}
+	earliest = earliest.UTC()
+	earliestDay := time.Date(earliest.Year(), earliest.Month(), earliest.Day(), 0, 0, 0, 0, time.UTC)
+
+	return day.Sub(earliestDay), nil
+}
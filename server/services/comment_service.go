@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"server/dto"
+	"server/gateway"
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CommentService interface {
+	GetComments(taskID uuid.UUID, params *pagination.Params) ([]models.Comment, *pagination.Page, error)
+	CreateComment(taskID uuid.UUID, commentDTO *dto.CommentRequest, actorID uuid.UUID) (*models.Comment, error)
+	DeleteComment(taskID uuid.UUID, commentID uuid.UUID, actorID uuid.UUID) error
+}
+
+type CommentServiceImpl struct {
+	commentRepo   repositories.CommentRepository
+	taskRepo      repositories.TaskRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	watcherRepo   repositories.WatcherRepository
+	notifier      *Notifier
+	engine        *policy.Engine
+	wsService     *gateway.WebSocketService
+	logger        *zap.Logger
+}
+
+func NewCommentService(
+	commentRepo repositories.CommentRepository,
+	taskRepo repositories.TaskRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	watcherRepo repositories.WatcherRepository,
+	notifier *Notifier,
+	engine *policy.Engine,
+	wsService *gateway.WebSocketService,
+	logger *zap.Logger,
+) *CommentServiceImpl {
+	return &CommentServiceImpl{
+		commentRepo:   commentRepo,
+		taskRepo:      taskRepo,
+		taskBoardRepo: taskBoardRepo,
+		watcherRepo:   watcherRepo,
+		notifier:      notifier,
+		engine:        engine,
+		wsService:     wsService,
+		logger:        logger,
+	}
+}
+
+func (service *CommentServiceImpl) GetComments(taskID uuid.UUID, params *pagination.Params) ([]models.Comment, *pagination.Page, error) {
+	return service.commentRepo.FindByTaskID(taskID, params)
+}
+
+// CreateComment adds a comment to a task that is not archived, makes the
+// commenter watch the task, and notifies the people it mentions and the
+// task's other watchers.
+func (service *CommentServiceImpl) CreateComment(taskID uuid.UUID, commentDTO *dto.CommentRequest, actorID uuid.UUID) (*models.Comment, error) {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := checkTaskWritable(task); err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(commentDTO.Body)
+	if body == "" {
+		return nil, &ValidationError{message: "comment must not be empty"}
+	}
+
+	comment, err := service.commentRepo.Create(&models.Comment{
+		TaskID: taskID,
+		UserID: actorID,
+		Body:   body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.watcherRepo.AutoWatchTask(taskID, actorID); err != nil {
+		service.logger.Error("Failed to watch commented task", zap.String("taskID", taskID.String()), zap.Error(err))
+	}
+
+	service.wsService.BroadcastToBoard(task.TaskBoardID.String(), "comment", comment)
+	service.notifier.Commented(actorID, task, comment)
+
+	return comment, nil
+}
+
+// DeleteComment is authorized as comment.delete on the comment, which
+// belongs to its author.
+func (service *CommentServiceImpl) DeleteComment(taskID uuid.UUID, commentID uuid.UUID, actorID uuid.UUID) error {
+	comment, err := service.commentRepo.FindByID(commentID)
+	if err != nil || comment.TaskID != taskID {
+		return fmt.Errorf("comment not found")
+	}
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return fmt.Errorf("task not found")
+	}
+
+	resource := policy.Resource{TaskBoardID: task.TaskBoardID, OwnerID: &comment.UserID}
+	if err := authorize(service.engine, service.taskBoardRepo, actorID, policy.CommentDelete, resource); err != nil {
+		return err
+	}
+
+	if err := service.commentRepo.Delete(commentID); err != nil {
+		return err
+	}
+
+	service.wsService.BroadcastToBoard(task.TaskBoardID.String(), "comment_delete", commentID)
+
+	return nil
+}
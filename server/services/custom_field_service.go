@@ -0,0 +1,388 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type CustomFieldService interface {
+	CreateCustomField(taskBoardID uuid.UUID, fieldDTO *dto.CustomFieldRequest) (*models.CustomField, error)
+	GetCustomFields(taskBoardID uuid.UUID, params *pagination.Params) ([]models.CustomField, *pagination.Page, error)
+	UpdateCustomField(taskBoardID uuid.UUID, fieldID uuid.UUID, fieldDTO *dto.CustomFieldRequest) (*models.CustomField, error)
+	DeleteCustomField(taskBoardID uuid.UUID, fieldID uuid.UUID) error
+}
+
+type CustomFieldServiceImpl struct {
+	customFieldRepo repositories.CustomFieldRepository
+	taskBoardRepo   repositories.TaskBoardRepository
+	logger          *zap.Logger
+}
+
+func NewCustomFieldService(
+	customFieldRepo repositories.CustomFieldRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	logger *zap.Logger,
+) *CustomFieldServiceImpl {
+	return &CustomFieldServiceImpl{
+		customFieldRepo: customFieldRepo,
+		taskBoardRepo:   taskBoardRepo,
+		logger:          logger,
+	}
+}
+
+func (service *CustomFieldServiceImpl) CreateCustomField(taskBoardID uuid.UUID, fieldDTO *dto.CustomFieldRequest) (*models.CustomField, error) {
+	if _, err := service.taskBoardRepo.FindByID(taskBoardID); err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+
+	options, err := normalizeFieldOptions(fieldDTO.Type, fieldDTO.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	field := &models.CustomField{
+		TaskBoardID: taskBoardID,
+		Name:        strings.TrimSpace(fieldDTO.Name),
+		Type:        fieldDTO.Type,
+		Options:     options,
+		Required:    fieldDTO.Required,
+	}
+
+	created, err := service.customFieldRepo.Create(field)
+	if err != nil {
+		service.logger.Error("Error creating custom field", zap.Error(err))
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+			return nil, &ConflictError{message: fmt.Sprintf("custom field %q already exists on this task board", field.Name)}
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+func (service *CustomFieldServiceImpl) GetCustomFields(taskBoardID uuid.UUID, params *pagination.Params) ([]models.CustomField, *pagination.Page, error) {
+	return service.customFieldRepo.ListByTaskBoardID(taskBoardID, params)
+}
+
+func (service *CustomFieldServiceImpl) UpdateCustomField(taskBoardID uuid.UUID, fieldID uuid.UUID, fieldDTO *dto.CustomFieldRequest) (*models.CustomField, error) {
+	field, err := service.findBoardField(taskBoardID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.Type != fieldDTO.Type {
+		return nil, &ValidationError{message: "the type of a custom field cannot be changed"}
+	}
+
+	options, err := normalizeFieldOptions(fieldDTO.Type, fieldDTO.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	field.Name = strings.TrimSpace(fieldDTO.Name)
+	field.Options = options
+	field.Required = fieldDTO.Required
+
+	updated, err := service.customFieldRepo.Update(field)
+	if err != nil {
+		service.logger.Error("Error updating custom field", zap.Error(err))
+		return nil, err
+	}
+
+	if isSelectField(field.Type) {
+		if err := service.customFieldRepo.DeleteValuesNotIn(field.ID, options); err != nil {
+			service.logger.Error("Error removing stale custom field values", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+func (service *CustomFieldServiceImpl) DeleteCustomField(taskBoardID uuid.UUID, fieldID uuid.UUID) error {
+	if _, err := service.findBoardField(taskBoardID, fieldID); err != nil {
+		return err
+	}
+	return service.customFieldRepo.Delete(fieldID)
+}
+
+func (service *CustomFieldServiceImpl) findBoardField(taskBoardID uuid.UUID, fieldID uuid.UUID) (*models.CustomField, error) {
+	field, err := service.customFieldRepo.FindByID(fieldID)
+	if err != nil || field.TaskBoardID != taskBoardID {
+		return nil, fmt.Errorf("custom field not found")
+	}
+	return field, nil
+}
+
+func isSelectField(fieldType string) bool {
+	return fieldType == models.CustomFieldSingleSelect || fieldType == models.CustomFieldMultiSelect
+}
+
+func normalizeFieldOptions(fieldType string, options []string) (models.StringList, error) {
+	if !isSelectField(fieldType) {
+		if len(options) > 0 {
+			return nil, &ValidationError{message: fmt.Sprintf("%s fields do not take options", fieldType)}
+		}
+		return models.StringList{}, nil
+	}
+
+	normalized := make(models.StringList, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if normalized.Contains(option) {
+			return nil, &ValidationError{message: fmt.Sprintf("duplicate option %q", option)}
+		}
+		normalized = append(normalized, option)
+	}
+	if len(normalized) == 0 {
+		return nil, &ValidationError{message: "select fields need at least one option"}
+	}
+	return normalized, nil
+}
+
+// buildCustomFieldValues validates raw values keyed by field ID against the
+// board's field definitions. isMember reports whether a user may be picked in
+// a user field.
+func buildCustomFieldValues(
+	fields []models.CustomField,
+	input map[string]json.RawMessage,
+	isMember func(userID uuid.UUID) bool,
+) ([]models.TaskCustomFieldValue, error) {
+	fieldsByID := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.ID.String()] = field
+	}
+	for key := range input {
+		if _, ok := fieldsByID[key]; !ok {
+			return nil, &ValidationError{message: fmt.Sprintf("unknown custom field %s", key)}
+		}
+	}
+
+	var values []models.TaskCustomFieldValue
+	for _, field := range fields {
+		raw, ok := input[field.ID.String()]
+		if !ok || string(raw) == "null" {
+			if field.Required {
+				return nil, &ValidationError{message: fmt.Sprintf("custom field %s is required", field.Name)}
+			}
+			continue
+		}
+
+		fieldValues, err := parseCustomFieldValue(field, raw, isMember)
+		if err != nil {
+			return nil, err
+		}
+		if len(fieldValues) == 0 && field.Required {
+			return nil, &ValidationError{message: fmt.Sprintf("custom field %s is required", field.Name)}
+		}
+		values = append(values, fieldValues...)
+	}
+	return values, nil
+}
+
+func parseCustomFieldValue(field models.CustomField, raw json.RawMessage, isMember func(userID uuid.UUID) bool) ([]models.TaskCustomFieldValue, error) {
+	invalid := func(reason string) error {
+		return &ValidationError{message: fmt.Sprintf("custom field %s %s", field.Name, reason)}
+	}
+	value := models.TaskCustomFieldValue{CustomFieldID: field.ID}
+
+	if field.Type == models.CustomFieldMultiSelect {
+		var selected []string
+		if err := json.Unmarshal(raw, &selected); err != nil {
+			return nil, invalid("must be a list of options")
+		}
+		seen := make(map[string]bool, len(selected))
+		values := make([]models.TaskCustomFieldValue, 0, len(selected))
+		for _, option := range selected {
+			if !field.Options.Contains(option) {
+				return nil, invalid(fmt.Sprintf("has no option %q", option))
+			}
+			if seen[option] {
+				continue
+			}
+			seen[option] = true
+			option := option
+			values = append(values, models.TaskCustomFieldValue{CustomFieldID: field.ID, TextValue: &option})
+		}
+		return values, nil
+	}
+
+	if field.Type == models.CustomFieldNumber {
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return nil, invalid("must be a number")
+		}
+		value.NumberValue = &number
+		return []models.TaskCustomFieldValue{value}, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return nil, invalid("must be a string")
+	}
+
+	switch field.Type {
+	case models.CustomFieldText:
+		if len(text) > 2048 {
+			return nil, invalid("must have at most 2048 characters")
+		}
+	case models.CustomFieldDate:
+		date, err := parseFieldDate(text)
+		if err != nil {
+			return nil, invalid("must be a date (YYYY-MM-DD or RFC 3339)")
+		}
+		value.DateValue = &date
+		return []models.TaskCustomFieldValue{value}, nil
+	case models.CustomFieldSingleSelect:
+		if !field.Options.Contains(text) {
+			return nil, invalid(fmt.Sprintf("has no option %q", text))
+		}
+	case models.CustomFieldUser:
+		userID, err := uuid.Parse(text)
+		if err != nil {
+			return nil, invalid("must be a user ID")
+		}
+		if !isMember(userID) {
+			return nil, invalid("must reference a collaborator of the task board")
+		}
+		text = userID.String()
+	case models.CustomFieldURL:
+		parsed, err := url.ParseRequestURI(text)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, invalid("must be an http or https URL")
+		}
+	}
+
+	value.TextValue = &text
+	return []models.TaskCustomFieldValue{value}, nil
+}
+
+func parseFieldDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseFieldScalar converts a query string value into the Go type stored in
+// the field's value column.
+func parseFieldScalar(field models.CustomField, value string) (interface{}, error) {
+	switch field.Type {
+	case models.CustomFieldNumber:
+		return strconv.ParseFloat(value, 64)
+	case models.CustomFieldDate:
+		return parseFieldDate(value)
+	case models.CustomFieldUser:
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		return userID.String(), nil
+	default:
+		return value, nil
+	}
+}
+
+// buildTaskFilter turns the query string filters of a board request into a
+// repository filter, resolving custom field IDs against the board's fields.
+func buildTaskFilter(fields []models.CustomField, query *dto.TaskBoardQuery) (repositories.TaskFilter, error) {
+	filter := repositories.TaskFilter{
+		ListOptions: repositories.ListOptions{
+			IncludeArchived: query.IncludeArchived,
+			IncludeDeleted:  query.IncludeDeleted,
+		},
+		Status:   query.Status,
+		Priority: query.Priority,
+	}
+
+	for _, raw := range query.Labels {
+		labelID, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, &ValidationError{message: fmt.Sprintf("invalid label %s", raw)}
+		}
+		filter.Labels = append(filter.Labels, labelID)
+	}
+
+	for _, raw := range query.Assignees {
+		if raw == "none" {
+			filter.Unassigned = true
+			continue
+		}
+		userID, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, &ValidationError{message: fmt.Sprintf("invalid assignee %s", raw)}
+		}
+		filter.Assignees = append(filter.Assignees, userID)
+	}
+
+	fieldsByID := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.ID.String()] = field
+	}
+
+	for key, expression := range query.CustomFields {
+		field, ok := fieldsByID[key]
+		if !ok {
+			return filter, &ValidationError{message: fmt.Sprintf("unknown custom field %s", key)}
+		}
+		fieldFilter := repositories.CustomFieldFilter{FieldID: field.ID, Column: field.ValueColumn()}
+
+		if bounds := strings.SplitN(expression, "..", 2); len(bounds) == 2 {
+			if field.Type != models.CustomFieldNumber && field.Type != models.CustomFieldDate {
+				return filter, &ValidationError{message: fmt.Sprintf("custom field %s does not support ranges", field.Name)}
+			}
+			if bounds[0] != "" {
+				min, err := parseFieldScalar(field, bounds[0])
+				if err != nil {
+					return filter, &ValidationError{message: fmt.Sprintf("invalid filter value for custom field %s", field.Name)}
+				}
+				fieldFilter.Min = min
+			}
+			if bounds[1] != "" {
+				max, err := parseFieldScalar(field, bounds[1])
+				if err != nil {
+					return filter, &ValidationError{message: fmt.Sprintf("invalid filter value for custom field %s", field.Name)}
+				}
+				fieldFilter.Max = max
+			}
+		} else {
+			for _, raw := range strings.Split(expression, ",") {
+				value, err := parseFieldScalar(field, strings.TrimSpace(raw))
+				if err != nil {
+					return filter, &ValidationError{message: fmt.Sprintf("invalid filter value for custom field %s", field.Name)}
+				}
+				fieldFilter.Values = append(fieldFilter.Values, value)
+			}
+		}
+
+		filter.CustomFields = append(filter.CustomFields, fieldFilter)
+	}
+
+	return filter, nil
+}
+
+// taskPage reads the board query's paging parameters against the sort keys
+// of the board's tasks.
+func taskPage(fields []models.CustomField, query *dto.TaskBoardQuery) (*pagination.Params, error) {
+	params, err := pagination.Parse(pagination.Query{
+		Limit:  query.Limit,
+		Cursor: query.Cursor,
+		Sort:   query.Sort,
+	}, repositories.TaskPages(fields))
+	if err != nil {
+		return nil, &ValidationError{message: err.Error()}
+	}
+	return params, nil
+}
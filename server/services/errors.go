@@ -0,0 +1,19 @@
+package services
+
+// ForbiddenError is returned when the caller is known but lacks the rights for an action.
+type ForbiddenError struct {
+	message string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.message
+}
+
+// ValidationError is returned when input is well-formed JSON but breaks a business rule.
+type ValidationError struct {
+	message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.message
+}
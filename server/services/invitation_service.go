@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+	"server/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// invitationTTL is how long an invitation link stays valid.
+const invitationTTL = 7 * 24 * time.Hour
+
+type InvitationService interface {
+	Invite(taskBoardID uuid.UUID, invitationDTO *dto.InvitationRequest, actorID uuid.UUID) (*models.Invitation, error)
+	GetInvitations(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Invitation, *pagination.Page, error)
+	RevokeInvitation(taskBoardID uuid.UUID, invitationID uuid.UUID, actorID uuid.UUID) error
+	GetInvitation(token string) (*models.Invitation, error)
+	AcceptInvitation(token string, userID uuid.UUID) (*models.UserTaskBoard, error)
+	DeclineInvitation(token string) error
+}
+
+type InvitationServiceImpl struct {
+	invitationRepo repositories.InvitationRepository
+	taskBoardRepo  repositories.TaskBoardRepository
+	userRepo       repositories.UserRepository
+	boardRoleRepo  repositories.BoardRoleRepository
+	transactor     repositories.Transactor
+	mailer         utils.Mailer
+	logger         *zap.Logger
+}
+
+func NewInvitationService(
+	invitationRepo repositories.InvitationRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	userRepo repositories.UserRepository,
+	boardRoleRepo repositories.BoardRoleRepository,
+	transactor repositories.Transactor,
+	mailer utils.Mailer,
+	logger *zap.Logger,
+) *InvitationServiceImpl {
+	return &InvitationServiceImpl{
+		invitationRepo: invitationRepo,
+		taskBoardRepo:  taskBoardRepo,
+		userRepo:       userRepo,
+		boardRoleRepo:  boardRoleRepo,
+		transactor:     transactor,
+		mailer:         mailer,
+		logger:         logger,
+	}
+}
+
+// Invite emails a link to join the board. As with collaborators, only owners
+// may invite an owner. The invitation is only kept if the email goes out.
+func (service *InvitationServiceImpl) Invite(taskBoardID uuid.UUID, invitationDTO *dto.InvitationRequest, actorID uuid.UUID) (*models.Invitation, error) {
+	taskBoard, err := service.taskBoardRepo.FindByID(taskBoardID)
+	if err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+	email := strings.ToLower(strings.TrimSpace(invitationDTO.Email))
+
+	user, err := service.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		if _, err := service.taskBoardRepo.FindCollaborator(taskBoardID, user.ID); err == nil {
+			return nil, &ConflictError{message: "user already exists on this task board"}
+		}
+	}
+	if _, err := service.invitationRepo.FindPending(taskBoardID, email); err == nil {
+		return nil, &ConflictError{message: "an invitation is already pending for this email"}
+	}
+
+	if invitationDTO.Role == policy.RoleOwner && subjectOn(service.taskBoardRepo, taskBoardID, actorID).Role != policy.RoleOwner {
+		return nil, &ForbiddenError{message: "only board owners can invite owners"}
+	}
+	boardRoleID, err := customRoleID(service.boardRoleRepo, taskBoardID, invitationDTO.Role, invitationDTO.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	inviter, err := service.userRepo.GetUserByID(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	token, err := utils.NewLinkToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var created *models.Invitation
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		created, err = service.invitationRepo.WithTx(tx).Create(&models.Invitation{
+			TaskBoardID: taskBoardID,
+			Email:       email,
+			Role:        invitationDTO.Role,
+			BoardRoleID: boardRoleID,
+			InvitedByID: actorID,
+			TokenHash:   utils.HashLinkToken(token),
+			Status:      models.InvitationPending,
+			ExpiresAt:   time.Now().Add(invitationTTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		subject := fmt.Sprintf("%s invited you to %q", inviter.Name, taskBoard.Title)
+		body := fmt.Sprintf("%s invited you to join the task board %q as %s.\n\nAccept the invitation: %s\n\nThe link expires on %s.",
+			inviter.Name, taskBoard.Title, invitationDTO.Role, utils.InviteLink(token), created.ExpiresAt.Format(time.RFC1123))
+		return service.mailer.Send(email, subject, body)
+	})
+	if err != nil {
+		service.logger.Error("Error sending invitation",
+			zap.String("taskBoardID", taskBoardID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	service.logger.Info("Invitation sent",
+		zap.String("taskBoardID", taskBoardID.String()),
+		zap.String("invitationID", created.ID.String()),
+		zap.String("actorID", actorID.String()))
+	return created, nil
+}
+
+// GetInvitations lists the board's pending, declined and expired invitations.
+func (service *InvitationServiceImpl) GetInvitations(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Invitation, *pagination.Page, error) {
+	invitations, page, err := service.invitationRepo.FindByTaskBoardID(taskBoardID, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	for i := range invitations {
+		invitations[i].Status = invitations[i].CurrentStatus(now)
+	}
+	return invitations, page, nil
+}
+
+// RevokeInvitation cancels a pending invitation so its link stops working.
+// Only owners may revoke an invitation to the owner role.
+func (service *InvitationServiceImpl) RevokeInvitation(taskBoardID uuid.UUID, invitationID uuid.UUID, actorID uuid.UUID) error {
+	invitation, err := service.invitationRepo.FindByID(invitationID)
+	if err != nil || invitation.TaskBoardID != taskBoardID {
+		return fmt.Errorf("invitation not found")
+	}
+	if invitation.Role == policy.RoleOwner && subjectOn(service.taskBoardRepo, taskBoardID, actorID).Role != policy.RoleOwner {
+		return &ForbiddenError{message: "only board owners can revoke invitations to the owner role"}
+	}
+
+	revoked, err := service.invitationRepo.Respond(invitationID, models.InvitationRevoked)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return &ConflictError{message: fmt.Sprintf("invitation has already been %s", invitation.Status)}
+	}
+	return nil
+}
+
+// GetInvitation looks up an invitation by the token in its link, so the
+// invitee can see the board and role before answering.
+func (service *InvitationServiceImpl) GetInvitation(token string) (*models.Invitation, error) {
+	invitation, err := service.findByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	invitation.Status = invitation.CurrentStatus(time.Now())
+	return invitation, nil
+}
+
+// AcceptInvitation joins the board with the invited role. The user must be
+// signed in with the email the invitation was sent to.
+func (service *InvitationServiceImpl) AcceptInvitation(token string, userID uuid.UUID) (*models.UserTaskBoard, error) {
+	invitation, err := service.findByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	user, err := service.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	var userTaskBoard *models.UserTaskBoard
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		userTaskBoard, err = acceptInvitation(service.invitationRepo.WithTx(tx), service.taskBoardRepo.WithTx(tx), invitation, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("Invitation accepted",
+		zap.String("taskBoardID", invitation.TaskBoardID.String()),
+		zap.String("invitationID", invitation.ID.String()),
+		zap.String("userID", userID.String()))
+	return userTaskBoard, nil
+}
+
+// DeclineInvitation needs no account: holding the link is enough.
+func (service *InvitationServiceImpl) DeclineInvitation(token string) error {
+	invitation, err := service.findByToken(token)
+	if err != nil {
+		return err
+	}
+	if err := checkInvitationOpen(invitation); err != nil {
+		return err
+	}
+
+	declined, err := service.invitationRepo.Respond(invitation.ID, models.InvitationDeclined)
+	if err != nil {
+		return err
+	}
+	if !declined {
+		return &ConflictError{message: "invitation has already been answered"}
+	}
+	return nil
+}
+
+func (service *InvitationServiceImpl) findByToken(token string) (*models.Invitation, error) {
+	invitation, err := service.invitationRepo.FindByTokenHash(utils.HashLinkToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invitation not found")
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// acceptInvitation marks the invitation accepted and adds the user to the
+// board with its role. A user who already collaborates keeps their role.
+// Registration shares it, so it takes repositories bound to the caller's
+// transaction.
+func acceptInvitation(invitationRepo repositories.InvitationRepository, taskBoardRepo repositories.TaskBoardRepository, invitation *models.Invitation, user *models.User) (*models.UserTaskBoard, error) {
+	if err := checkInvitationOpen(invitation); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, &ForbiddenError{message: "this invitation was sent to another email address"}
+	}
+	if invitation.Role == policy.RoleCustom && invitation.BoardRoleID == nil {
+		return nil, &ValidationError{message: "the invited role no longer exists"}
+	}
+
+	accepted, err := invitationRepo.Respond(invitation.ID, models.InvitationAccepted)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, &ConflictError{message: "invitation has already been answered"}
+	}
+
+	if existing, err := taskBoardRepo.FindCollaborator(invitation.TaskBoardID, user.ID); err == nil {
+		return existing, nil
+	}
+	return taskBoardRepo.AddCollaborator(user.ID, invitation.TaskBoardID, repositories.Role(invitation.Role), invitation.BoardRoleID)
+}
+
+func checkInvitationOpen(invitation *models.Invitation) error {
+	switch status := invitation.CurrentStatus(time.Now()); status {
+	case models.InvitationPending:
+		return nil
+	case models.InvitationExpired:
+		return &ValidationError{message: "invitation has expired"}
+	default:
+		return &ConflictError{message: fmt.Sprintf("invitation has already been %s", status)}
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type LabelService interface {
+	CreateLabel(taskBoardID uuid.UUID, labelDTO *dto.LabelRequest) (*models.Label, error)
+	GetLabels(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Label, *pagination.Page, error)
+	UpdateLabel(taskBoardID uuid.UUID, labelID uuid.UUID, labelDTO *dto.LabelRequest) (*models.Label, error)
+	DeleteLabel(taskBoardID uuid.UUID, labelID uuid.UUID) error
+}
+
+type LabelServiceImpl struct {
+	labelRepo     repositories.LabelRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	logger        *zap.Logger
+}
+
+func NewLabelService(
+	labelRepo repositories.LabelRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	logger *zap.Logger,
+) *LabelServiceImpl {
+	return &LabelServiceImpl{
+		labelRepo:     labelRepo,
+		taskBoardRepo: taskBoardRepo,
+		logger:        logger,
+	}
+}
+
+func (service *LabelServiceImpl) CreateLabel(taskBoardID uuid.UUID, labelDTO *dto.LabelRequest) (*models.Label, error) {
+	if _, err := service.taskBoardRepo.FindByID(taskBoardID); err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+
+	label := &models.Label{
+		TaskBoardID: taskBoardID,
+		Name:        strings.TrimSpace(labelDTO.Name),
+		Color:       labelColor(labelDTO.Color),
+	}
+
+	created, err := service.labelRepo.Create(label)
+	if err != nil {
+		service.logger.Error("Error creating label", zap.Error(err))
+		return nil, labelConflict(label.Name, err)
+	}
+	return created, nil
+}
+
+func (service *LabelServiceImpl) GetLabels(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Label, *pagination.Page, error) {
+	return service.labelRepo.ListByTaskBoardID(taskBoardID, params)
+}
+
+func (service *LabelServiceImpl) UpdateLabel(taskBoardID uuid.UUID, labelID uuid.UUID, labelDTO *dto.LabelRequest) (*models.Label, error) {
+	label, err := service.findBoardLabel(taskBoardID, labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	label.Name = strings.TrimSpace(labelDTO.Name)
+	label.Color = labelColor(labelDTO.Color)
+
+	updated, err := service.labelRepo.Update(label)
+	if err != nil {
+		service.logger.Error("Error updating label", zap.Error(err))
+		return nil, labelConflict(label.Name, err)
+	}
+	return updated, nil
+}
+
+func (service *LabelServiceImpl) DeleteLabel(taskBoardID uuid.UUID, labelID uuid.UUID) error {
+	if _, err := service.findBoardLabel(taskBoardID, labelID); err != nil {
+		return err
+	}
+	return service.labelRepo.Delete(labelID)
+}
+
+func (service *LabelServiceImpl) findBoardLabel(taskBoardID uuid.UUID, labelID uuid.UUID) (*models.Label, error) {
+	label, err := service.labelRepo.FindByID(labelID)
+	if err != nil || label.TaskBoardID != taskBoardID {
+		return nil, fmt.Errorf("label not found")
+	}
+	return label, nil
+}
+
+func labelColor(color string) string {
+	if color == "" {
+		return "#808080"
+	}
+	return strings.ToLower(color)
+}
+
+func labelConflict(name string, err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+		return &ConflictError{message: fmt.Sprintf("label %q already exists on this task board", name)}
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"server/gateway"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type NotificationService interface {
+	GetNotifications(userID uuid.UUID, unreadOnly bool, params *pagination.Params) ([]models.Notification, *pagination.Page, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	MarkRead(userID uuid.UUID, notificationID uuid.UUID) (int64, error)
+	MarkAllRead(userID uuid.UUID) (int64, error)
+}
+
+type NotificationServiceImpl struct {
+	notificationRepo repositories.NotificationRepository
+	wsService        *gateway.WebSocketService
+	logger           *zap.Logger
+}
+
+func NewNotificationService(notificationRepo repositories.NotificationRepository, wsService *gateway.WebSocketService, logger *zap.Logger) *NotificationServiceImpl {
+	return &NotificationServiceImpl{
+		notificationRepo: notificationRepo,
+		wsService:        wsService,
+		logger:           logger,
+	}
+}
+
+// GetNotifications lists the user's inbox, or only what they have not read.
+func (service *NotificationServiceImpl) GetNotifications(userID uuid.UUID, unreadOnly bool, params *pagination.Params) ([]models.Notification, *pagination.Page, error) {
+	return service.notificationRepo.FindByUserID(userID, unreadOnly, params)
+}
+
+func (service *NotificationServiceImpl) CountUnread(userID uuid.UUID) (int64, error) {
+	return service.notificationRepo.CountUnread(userID)
+}
+
+// MarkRead marks one of the user's notifications read and returns how many
+// remain unread. The user's other connections hear about it so that every
+// open inbox stays in step.
+func (service *NotificationServiceImpl) MarkRead(userID uuid.UUID, notificationID uuid.UUID) (int64, error) {
+	if err := service.notificationRepo.MarkRead(userID, notificationID); err != nil {
+		return 0, err
+	}
+	unread, err := service.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	service.wsService.SendToUser(userID.String(), "notification_read", map[string]interface{}{
+		"notification_id": notificationID,
+		"unread_count":    unread,
+	})
+
+	return unread, nil
+}
+
+// MarkAllRead marks the user's whole inbox read and returns how many
+// notifications that covered.
+func (service *NotificationServiceImpl) MarkAllRead(userID uuid.UUID) (int64, error) {
+	marked, err := service.notificationRepo.MarkAllRead(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	service.wsService.SendToUser(userID.String(), "notification_read", map[string]interface{}{
+		"all":          true,
+		"unread_count": 0,
+	})
+
+	return marked, nil
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"regexp"
+	"server/gateway"
+	"server/models"
+	"server/repositories"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxMentions bounds how many people one text can notify.
+const maxMentions = 20
+
+// mentionPattern matches an @-mention, which names a user by email, such as
+// "@ana@example.com".
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// Notifier decides who hears about a change, files the notifications in
+// their inboxes and pushes each one over the WebSocket gateway to its
+// recipient alone. Nobody is notified of their own actions. Notifying runs
+// after the change is committed and never fails it; errors are logged.
+type Notifier struct {
+	notificationRepo repositories.NotificationRepository
+	watcherRepo      repositories.WatcherRepository
+	userRepo         repositories.UserRepository
+	taskBoardRepo    repositories.TaskBoardRepository
+	wsService        *gateway.WebSocketService
+	logger           *zap.Logger
+}
+
+func NewNotifier(
+	notificationRepo repositories.NotificationRepository,
+	watcherRepo repositories.WatcherRepository,
+	userRepo repositories.UserRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	wsService *gateway.WebSocketService,
+	logger *zap.Logger,
+) *Notifier {
+	return &Notifier{
+		notificationRepo: notificationRepo,
+		watcherRepo:      watcherRepo,
+		userRepo:         userRepo,
+		taskBoardRepo:    taskBoardRepo,
+		wsService:        wsService,
+		logger:           logger,
+	}
+}
+
+// AddedToBoard tells a user they were made a collaborator on a board.
+func (notifier *Notifier) AddedToBoard(actorID uuid.UUID, userID uuid.UUID, taskBoardID uuid.UUID) {
+	notifier.send(actorID, []models.Notification{{
+		UserID:      userID,
+		Kind:        models.NotificationBoardAdded,
+		TaskBoardID: &taskBoardID,
+	}})
+}
+
+// TaskCreated tells the assignee, anyone mentioned in the description and
+// the board's watchers about a new task.
+func (notifier *Notifier) TaskCreated(actorID uuid.UUID, task *models.Task) {
+	notifier.send(actorID, notifier.taskNotifications(actorID, nil, task))
+}
+
+// TaskChanged tells a new assignee about their assignment, people newly
+// mentioned in the description about the mention, and the task's other
+// watchers which fields changed.
+func (notifier *Notifier) TaskChanged(actorID uuid.UUID, before *models.Task, after *models.Task) {
+	notifier.send(actorID, notifier.taskNotifications(actorID, before, after))
+}
+
+// taskNotifications works out who hears about a task going from before to
+// after; before is nil for a new task, which its board's watchers hear of.
+func (notifier *Notifier) taskNotifications(actorID uuid.UUID, before *models.Task, after *models.Task) []models.Notification {
+	var notifications []models.Notification
+	notified := map[uuid.UUID]bool{actorID: true}
+
+	var previousAssigneeID *uuid.UUID
+	previous := make(map[uuid.UUID]bool)
+	if before != nil {
+		previousAssigneeID = before.AssigneeID
+		for _, userID := range notifier.mentioned(before.TaskBoardID, before.Description) {
+			previous[userID] = true
+		}
+	}
+
+	if after.AssigneeID != nil && (previousAssigneeID == nil || *previousAssigneeID != *after.AssigneeID) {
+		notifications = append(notifications, taskNotification(*after.AssigneeID, models.NotificationTaskAssigned, after))
+		notified[*after.AssigneeID] = true
+	}
+
+	for _, userID := range notifier.mentioned(after.TaskBoardID, after.Description) {
+		if previous[userID] || notified[userID] {
+			continue
+		}
+		notifications = append(notifications, taskNotification(userID, models.NotificationMentioned, after))
+		notified[userID] = true
+	}
+
+	kind := models.NotificationTaskCreated
+	var fields models.StringList
+	if before != nil {
+		kind = models.NotificationTaskUpdated
+		if fields = changedTaskFields(before, after); len(fields) == 0 {
+			return notifications
+		}
+	}
+	for _, userID := range notifier.watchers(after) {
+		if notified[userID] {
+			continue
+		}
+		notification := taskNotification(userID, kind, after)
+		notification.Fields = fields
+		notifications = append(notifications, notification)
+		notified[userID] = true
+	}
+	return notifications
+}
+
+// Commented tells the people mentioned in a comment about the mention, and
+// the task's other watchers that it has a new comment.
+func (notifier *Notifier) Commented(actorID uuid.UUID, task *models.Task, comment *models.Comment) {
+	var notifications []models.Notification
+	notified := map[uuid.UUID]bool{actorID: true}
+
+	for _, userID := range notifier.mentioned(task.TaskBoardID, comment.Body) {
+		if notified[userID] {
+			continue
+		}
+		notification := taskNotification(userID, models.NotificationMentioned, task)
+		notification.CommentID = &comment.ID
+		notifications = append(notifications, notification)
+		notified[userID] = true
+	}
+
+	for _, userID := range notifier.watchers(task) {
+		if notified[userID] {
+			continue
+		}
+		notification := taskNotification(userID, models.NotificationTaskUpdated, task)
+		notification.Fields = models.StringList{"comments"}
+		notifications = append(notifications, notification)
+		notified[userID] = true
+	}
+
+	notifier.send(actorID, notifications)
+}
+
+// send files the notifications, leaving out any addressed to the actor, and
+// pushes each as stored to its recipient.
+func (notifier *Notifier) send(actorID uuid.UUID, notifications []models.Notification) {
+	for i := range notifications {
+		notification := &notifications[i]
+		if notification.UserID == actorID {
+			continue
+		}
+		notification.ActorID = &actorID
+
+		stored, err := notifier.notificationRepo.Add(notification)
+		if err != nil {
+			notifier.logger.Error("Failed to store notification",
+				zap.String("kind", notification.Kind),
+				zap.String("userID", notification.UserID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		notifier.wsService.SendToUser(stored.UserID.String(), "notification", stored)
+	}
+}
+
+// mentioned returns the users mentioned in text who can see the board.
+func (notifier *Notifier) mentioned(taskBoardID uuid.UUID, text string) []uuid.UUID {
+	var userIDs []uuid.UUID
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		email := strings.TrimRight(match[1], ".")
+		if seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+		if len(seen) > maxMentions {
+			break
+		}
+
+		user, err := notifier.userRepo.FindByEmail(email)
+		if err != nil || user == nil {
+			continue
+		}
+		if _, err := notifier.taskBoardRepo.CheckUserRole(taskBoardID, user.ID); err != nil {
+			continue
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs
+}
+
+func taskNotification(userID uuid.UUID, kind string, task *models.Task) models.Notification {
+	return models.Notification{
+		UserID:      userID,
+		Kind:        kind,
+		TaskBoardID: &task.TaskBoardID,
+		TaskID:      &task.ID,
+	}
+}
+
+// watchers returns the task's watchers who can still see its board.
+func (notifier *Notifier) watchers(task *models.Task) []uuid.UUID {
+	userIDs, err := notifier.watcherRepo.FindTaskWatcherIDs(task.ID, task.TaskBoardID)
+	if err != nil {
+		notifier.logger.Error("Failed to find task watchers", zap.String("taskID", task.ID.String()), zap.Error(err))
+		return nil
+	}
+
+	var watchers []uuid.UUID
+	for _, userID := range userIDs {
+		if _, err := notifier.taskBoardRepo.CheckUserRole(task.TaskBoardID, userID); err != nil {
+			continue
+		}
+		watchers = append(watchers, userID)
+	}
+	return watchers
+}
+
+// changedTaskFields names the fields a change touched, custom fields
+// reported together as "custom_fields".
+func changedTaskFields(before *models.Task, after *models.Task) models.StringList {
+	changes, err := diffAuditFields(taskAuditFields(before), taskAuditFields(after))
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	fields := models.StringList{}
+	for field := range changes {
+		if strings.HasPrefix(field, "cf:") {
+			field = "custom_fields"
+		}
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/repositories"
+	"server/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RecurrenceService interface {
+	SetRecurrence(taskID uuid.UUID, recurrenceDTO *dto.RecurrenceRequest) (*models.TaskRecurrence, error)
+	GetRecurrence(taskID uuid.UUID) (*models.TaskRecurrence, error)
+	DeleteRecurrence(taskID uuid.UUID) error
+	CreateDueInstances(now time.Time) (int, error)
+}
+
+// RecurrenceServiceImpl creates instances through taskService, so they are
+// created like any other task.
+type RecurrenceServiceImpl struct {
+	recurrenceRepo repositories.TaskRecurrenceRepository
+	taskRepo       repositories.TaskRepository
+	taskService    *TaskServiceImpl
+	logger         *zap.Logger
+}
+
+func NewRecurrenceService(
+	recurrenceRepo repositories.TaskRecurrenceRepository,
+	taskRepo repositories.TaskRepository,
+	taskService *TaskServiceImpl,
+	logger *zap.Logger,
+) *RecurrenceServiceImpl {
+	return &RecurrenceServiceImpl{
+		recurrenceRepo: recurrenceRepo,
+		taskRepo:       taskRepo,
+		taskService:    taskService,
+		logger:         logger,
+	}
+}
+
+func (service *RecurrenceServiceImpl) SetRecurrence(taskID uuid.UUID, recurrenceDTO *dto.RecurrenceRequest) (*models.TaskRecurrence, error) {
+	rule, err := utils.ParseRecurrenceRule(recurrenceDTO.Rule)
+	if err != nil {
+		return nil, &ValidationError{message: err.Error()}
+	}
+
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	recurrence, err := service.recurrenceRepo.FindByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence == nil {
+		recurrence = &models.TaskRecurrence{
+			TaskID:      taskID,
+			SeriesStart: task.StartDate,
+			Occurrences: 1,
+		}
+	}
+
+	recurrence.Rule = rule.String()
+	recurrence.NextRunAt = service.advance(recurrence, rule, task.StartDate, time.Now())
+
+	return service.recurrenceRepo.Save(recurrence)
+}
+
+func (service *RecurrenceServiceImpl) GetRecurrence(taskID uuid.UUID) (*models.TaskRecurrence, error) {
+	recurrence, err := service.recurrenceRepo.FindByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence == nil {
+		return nil, fmt.Errorf("task does not recur")
+	}
+	return recurrence, nil
+}
+
+func (service *RecurrenceServiceImpl) DeleteRecurrence(taskID uuid.UUID) error {
+	if _, err := service.GetRecurrence(taskID); err != nil {
+		return err
+	}
+	return service.recurrenceRepo.DeleteByTaskID(taskID)
+}
+
+// CreateDueInstances creates the next instance of every due series. It is
+// safe to call from several replicas at once: only the one holding the
+// scheduler lock does any work, the others return immediately. A series
+// whose column is at its WIP limit is tried again on the next run.
+func (service *RecurrenceServiceImpl) CreateDueInstances(now time.Time) (int, error) {
+	var created []*models.Task
+
+	acquired, err := service.recurrenceRepo.WithSchedulerLock(func(tx *gorm.DB) error {
+		repo := service.recurrenceRepo.WithTx(tx)
+		taskService := service.taskService.withTx(tx)
+		due, err := repo.FindDue(now)
+		if err != nil {
+			return err
+		}
+
+		for i := range due {
+			recurrence := &due[i]
+			rule, err := utils.ParseRecurrenceRule(recurrence.Rule)
+			if err != nil {
+				service.logger.Error("Skipping recurrence with invalid rule",
+					zap.String("recurrenceID", recurrence.ID.String()), zap.Error(err))
+				recurrence.NextRunAt = nil
+				if _, err := repo.Save(recurrence); err != nil {
+					return err
+				}
+				continue
+			}
+
+			instance := nextInstance(&recurrence.Task, *recurrence.NextRunAt)
+			if err := taskService.checkAssignee(instance.TaskBoardID, instance.AssigneeID); err != nil {
+				instance.AssigneeID = nil
+			}
+			instance, err = taskService.createTask(instance, false, models.RequestMeta{})
+			if conflict, ok := err.(*ConflictError); ok {
+				service.logger.Warn("Postponing recurring task instance",
+					zap.String("recurrenceID", recurrence.ID.String()), zap.Error(conflict))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			recurrence.TaskID = instance.ID
+			recurrence.Occurrences++
+			recurrence.NextRunAt = service.advance(recurrence, rule, instance.StartDate, now)
+			if _, err := repo.Save(recurrence); err != nil {
+				return err
+			}
+			created = append(created, instance)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		return 0, nil
+	}
+
+	for _, instance := range created {
+		service.taskService.announceCreated(instance)
+	}
+	return len(created), nil
+}
+
+// advance returns when the series' next instance, after the one starting at
+// current, is due. Occurrences that are already past by now are skipped, so a
+// series that fell behind does not create one stale instance per run; they
+// still count against COUNT and UNTIL.
+func (service *RecurrenceServiceImpl) advance(recurrence *models.TaskRecurrence, rule *utils.RecurrenceRule, current, now time.Time) *time.Time {
+	next, skipped := nextRun(rule, current, recurrence.SeriesStart, now, recurrence.Occurrences)
+	if skipped > 0 {
+		service.logger.Info("Skipping past occurrences of recurring task",
+			zap.String("recurrenceID", recurrence.ID.String()), zap.Int("skipped", skipped))
+	}
+	recurrence.Occurrences += skipped
+	return next
+}
+
+// nextRun returns the first occurrence after now that follows the one starting
+// at current, and how many occurrences before it were skipped for being past.
+// It returns nil when the rule does not allow another occurrence.
+func nextRun(rule *utils.RecurrenceRule, current, seriesStart, now time.Time, occurrences int) (*time.Time, int) {
+	next := rule.Next(current, seriesStart)
+	for skipped := 0; ; skipped++ {
+		if !rule.Allows(next, occurrences+skipped) {
+			return nil, skipped
+		}
+		if next.After(now) {
+			return &next, skipped
+		}
+		next = rule.Next(next, seriesStart)
+	}
+}
+
+// nextInstance copies a task onto a new start date, keeping the distance
+// between StartDate and EndDate, with its assignee, labels and custom field
+// values.
+func nextInstance(task *models.Task, startDate time.Time) *models.Task {
+	instance := &models.Task{
+		TaskBoardID:     task.TaskBoardID,
+		Title:           task.Title,
+		Description:     task.Description,
+		Status:          "todo",
+		Priority:        task.Priority,
+		StartDate:       startDate,
+		EndDate:         startDate.Add(task.EndDate.Sub(task.StartDate)),
+		EstimateMinutes: task.EstimateMinutes,
+		AssigneeID:      task.AssigneeID,
+		CreatedByID:     task.CreatedByID,
+		Labels:          task.Labels,
+	}
+	for _, value := range task.CustomFieldValues {
+		value.ID = uuid.Nil
+		value.TaskID = uuid.Nil
+		value.CreatedAt = time.Time{}
+		instance.CustomFieldValues = append(instance.CustomFieldValues, value)
+	}
+	return instance
+}
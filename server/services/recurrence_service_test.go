@@ -0,0 +1,150 @@
+package services
+
+import (
+	"server/models"
+	"server/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNextRunSkipsPastOccurrences(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	day := func(value string) time.Time {
+		at, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+
+	tests := []struct {
+		name        string
+		rule        string
+		current     time.Time
+		occurrences int
+		want        *time.Time
+		wantSkipped int
+	}{
+		{
+			name:        "future occurrence is kept",
+			rule:        "FREQ=DAILY",
+			current:     day("2026-10-19 09:00"),
+			occurrences: 1,
+			want:        ptr(day("2026-10-20 09:00")),
+		},
+		{
+			name:        "start date months ago catches up to now",
+			rule:        "FREQ=DAILY",
+			current:     day("2026-07-01 09:00"),
+			occurrences: 1,
+			want:        ptr(day("2026-10-20 09:00")),
+			wantSkipped: 110,
+		},
+		{
+			name:        "occurrence later today is next",
+			rule:        "FREQ=WEEKLY;BYDAY=MO,TH",
+			current:     day("2026-09-28 15:00"),
+			occurrences: 1,
+			want:        ptr(day("2026-10-19 15:00")),
+			wantSkipped: 5,
+		},
+		{
+			name:        "skipped occurrences use up COUNT",
+			rule:        "FREQ=DAILY;COUNT=5",
+			current:     day("2026-10-01 09:00"),
+			occurrences: 1,
+			want:        nil,
+			wantSkipped: 4,
+		},
+		{
+			name:        "series whose UNTIL is past ends",
+			rule:        "FREQ=DAILY;UNTIL=20261010",
+			current:     day("2026-10-01 09:00"),
+			occurrences: 1,
+			want:        nil,
+			wantSkipped: 9,
+		},
+		{
+			name:        "UNTIL after now leaves the next occurrence",
+			rule:        "FREQ=WEEKLY;UNTIL=20261231",
+			current:     day("2026-10-01 09:00"),
+			occurrences: 1,
+			want:        ptr(day("2026-10-22 09:00")),
+			wantSkipped: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := utils.ParseRecurrenceRule(test.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, skipped := nextRun(rule, test.current, test.current, now, test.occurrences)
+			if (got == nil) != (test.want == nil) || (got != nil && !got.Equal(*test.want)) {
+				t.Errorf("next run = %v, want %v", got, test.want)
+			}
+			if skipped != test.wantSkipped {
+				t.Errorf("skipped %d occurrences, want %d", skipped, test.wantSkipped)
+			}
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestNextInstanceCopiesTask(t *testing.T) {
+	assigneeID, creatorID := uuid.New(), uuid.New()
+	text, number, date := "ops", 3.5, time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	task := &models.Task{
+		ID:          uuid.New(),
+		TaskBoardID: uuid.New(),
+		Title:       "Rotate keys",
+		Status:      "done",
+		Priority:    "high",
+		StartDate:   start,
+		EndDate:     start.Add(2 * time.Hour),
+		AssigneeID:  &assigneeID,
+		CreatedByID: &creatorID,
+		Labels:      []models.Label{{ID: uuid.New(), Name: "security"}},
+		CustomFieldValues: []models.TaskCustomFieldValue{
+			{ID: uuid.New(), CustomFieldID: uuid.New(), TextValue: &text},
+			{ID: uuid.New(), CustomFieldID: uuid.New(), NumberValue: &number},
+			{ID: uuid.New(), CustomFieldID: uuid.New(), DateValue: &date},
+		},
+	}
+	for i := range task.CustomFieldValues {
+		task.CustomFieldValues[i].TaskID = task.ID
+		task.CustomFieldValues[i].CreatedAt = start
+	}
+
+	next := start.AddDate(0, 0, 7)
+	instance := nextInstance(task, next)
+
+	if instance.Status != "todo" || !instance.StartDate.Equal(next) || !instance.EndDate.Equal(next.Add(2*time.Hour)) {
+		t.Errorf("instance = %s %v-%v, want todo %v-%v", instance.Status, instance.StartDate, instance.EndDate, next, next.Add(2*time.Hour))
+	}
+	if instance.AssigneeID == nil || *instance.AssigneeID != assigneeID {
+		t.Errorf("assignee = %v, want %s", instance.AssigneeID, assigneeID)
+	}
+	if len(instance.Labels) != 1 || instance.Labels[0].ID != task.Labels[0].ID {
+		t.Errorf("labels = %+v, want %+v", instance.Labels, task.Labels)
+	}
+	if len(instance.CustomFieldValues) != len(task.CustomFieldValues) {
+		t.Fatalf("%d custom field values, want %d", len(instance.CustomFieldValues), len(task.CustomFieldValues))
+	}
+	for i, value := range instance.CustomFieldValues {
+		original := task.CustomFieldValues[i]
+		if value.ID != uuid.Nil || value.TaskID != uuid.Nil || !value.CreatedAt.IsZero() {
+			t.Errorf("value %d keeps the row of the original task: %+v", i, value)
+		}
+		if value.CustomFieldID != original.CustomFieldID || value.TextValue != original.TextValue ||
+			value.NumberValue != original.NumberValue || value.DateValue != original.DateValue {
+			t.Errorf("value %d = %+v, want the columns of %+v", i, value, original)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"fmt"
+	"server/dto"
+	"server/gateway"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+	"server/utils"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxReminderOffset bounds how far ahead the worker looks for tasks; it
+	// matches the largest offset a user may configure.
+	maxReminderOffset = 7 * 24 * time.Hour
+	// overdueLookback stops tasks that were already long overdue when the
+	// worker first saw them from all firing at once.
+	overdueLookback = 7 * 24 * time.Hour
+)
+
+type ReminderService interface {
+	GetSetting(userID uuid.UUID) (*models.ReminderSetting, error)
+	UpdateSetting(userID uuid.UUID, settingDTO *dto.ReminderSettingRequest) (*models.ReminderSetting, error)
+	ListReminders(userID uuid.UUID, params *pagination.Params) ([]models.TaskReminder, *pagination.Page, error)
+	SendDueReminders(now time.Time) (int, error)
+}
+
+type ReminderServiceImpl struct {
+	reminderRepo  repositories.ReminderRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	wsService     *gateway.WebSocketService
+	mailer        utils.Mailer
+	logger        *zap.Logger
+}
+
+func NewReminderService(
+	reminderRepo repositories.ReminderRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	wsService *gateway.WebSocketService,
+	mailer utils.Mailer,
+	logger *zap.Logger,
+) *ReminderServiceImpl {
+	return &ReminderServiceImpl{
+		reminderRepo:  reminderRepo,
+		taskBoardRepo: taskBoardRepo,
+		wsService:     wsService,
+		mailer:        mailer,
+		logger:        logger,
+	}
+}
+
+func (service *ReminderServiceImpl) GetSetting(userID uuid.UUID) (*models.ReminderSetting, error) {
+	setting, err := service.reminderRepo.GetSetting(userID)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		defaults := models.DefaultReminderSetting(userID)
+		return &defaults, nil
+	}
+	return setting, nil
+}
+
+func (service *ReminderServiceImpl) UpdateSetting(userID uuid.UUID, settingDTO *dto.ReminderSettingRequest) (*models.ReminderSetting, error) {
+	offsets := make(models.IntList, 0, len(settingDTO.OffsetsMinutes))
+	seen := make(map[int]bool, len(settingDTO.OffsetsMinutes))
+	for _, offset := range settingDTO.OffsetsMinutes {
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+
+	channels := models.StringList{}
+	for _, channel := range settingDTO.Channels {
+		if !channels.Contains(channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	return service.reminderRepo.SaveSetting(&models.ReminderSetting{
+		UserID:         userID,
+		OffsetsMinutes: offsets,
+		Channels:       channels,
+	})
+}
+
+func (service *ReminderServiceImpl) ListReminders(userID uuid.UUID, params *pagination.Params) ([]models.TaskReminder, *pagination.Page, error) {
+	return service.reminderRepo.FindByUserID(userID, params)
+}
+
+// SendDueReminders claims and delivers every reminder that has become due.
+// Claims are unique in the database, so running it concurrently on several
+// replicas or again after a restart never sends a reminder twice.
+func (service *ReminderServiceImpl) SendDueReminders(now time.Time) (int, error) {
+	tasks, err := service.reminderRepo.FindOpenTasksDueBetween(now.Add(-overdueLookback), now.Add(maxReminderOffset))
+	if err != nil {
+		return 0, err
+	}
+
+	collaboratorsByBoard := make(map[uuid.UUID][]models.UserTaskBoard)
+	settings := make(map[uuid.UUID]models.ReminderSetting)
+	sent := 0
+
+	for i := range tasks {
+		task := &tasks[i]
+
+		collaborators, ok := collaboratorsByBoard[task.TaskBoardID]
+		if !ok {
+			if collaborators, err = service.boardCollaborators(task.TaskBoardID, settings); err != nil {
+				return sent, err
+			}
+			collaboratorsByBoard[task.TaskBoardID] = collaborators
+		}
+
+		for _, user := range reminderRecipients(task, collaborators) {
+			delivered, err := service.remind(task, user, settings[user.ID], now)
+			if err != nil {
+				return sent, err
+			}
+			if delivered {
+				sent++
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+// boardCollaborators returns everyone on the board, loading their reminder
+// settings into settings.
+func (service *ReminderServiceImpl) boardCollaborators(taskBoardID uuid.UUID, settings map[uuid.UUID]models.ReminderSetting) ([]models.UserTaskBoard, error) {
+	collaborators, err := service.taskBoardRepo.GetUsersOnTaskBoard(taskBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []uuid.UUID
+	for _, collaborator := range collaborators {
+		if _, ok := settings[collaborator.UserID]; !ok {
+			missing = append(missing, collaborator.UserID)
+			settings[collaborator.UserID] = models.DefaultReminderSetting(collaborator.UserID)
+		}
+	}
+
+	saved, err := service.reminderRepo.FindSettings(missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range saved {
+		settings[setting.UserID] = setting
+	}
+
+	return collaborators, nil
+}
+
+// reminderRecipients picks who hears about a task: its assignee while they
+// are still on the board, otherwise the board's owners and editors.
+func reminderRecipients(task *models.Task, collaborators []models.UserTaskBoard) []models.User {
+	var recipients []models.User
+	for _, collaborator := range collaborators {
+		if task.AssigneeID != nil && collaborator.UserID == *task.AssigneeID {
+			return []models.User{collaborator.User}
+		}
+		if collaborator.Role == "owner" || collaborator.Role == "editor" {
+			recipients = append(recipients, collaborator.User)
+		}
+	}
+	return recipients
+}
+
+// remind claims the reminders the user is due for on this task and delivers
+// one message if any claim is new. When several offsets have passed at once,
+// all are claimed but only the closest one is announced.
+func (service *ReminderServiceImpl) remind(task *models.Task, user models.User, setting models.ReminderSetting, now time.Time) (bool, error) {
+	var claims []models.TaskReminder
+	if !now.Before(task.EndDate) {
+		claims = append(claims, models.TaskReminder{Kind: models.ReminderOverdue})
+	} else {
+		for _, offset := range setting.OffsetsMinutes {
+			if !now.Before(task.EndDate.Add(-time.Duration(offset) * time.Minute)) {
+				claims = append(claims, models.TaskReminder{Kind: models.ReminderDueSoon, OffsetMinutes: offset})
+			}
+		}
+	}
+
+	var announce *models.TaskReminder
+	for i := range claims {
+		claim := &claims[i]
+		claim.TaskID = task.ID
+		claim.UserID = user.ID
+		claim.DueAt = task.EndDate
+		claim.SentAt = now
+
+		claimed, err := service.reminderRepo.Claim(claim)
+		if err != nil {
+			return false, err
+		}
+		if claimed && (announce == nil || claim.OffsetMinutes < announce.OffsetMinutes) {
+			announce = claim
+		}
+	}
+	if announce == nil {
+		return false, nil
+	}
+
+	announce.Task = *task
+	service.deliver(announce, user, setting)
+	return true, nil
+}
+
+func (service *ReminderServiceImpl) deliver(reminder *models.TaskReminder, user models.User, setting models.ReminderSetting) {
+	subject := fmt.Sprintf("Task %q is overdue", reminder.Task.Title)
+	if reminder.Kind == models.ReminderDueSoon {
+		subject = fmt.Sprintf("Task %q is due in %s", reminder.Task.Title, formatOffset(reminder.OffsetMinutes))
+	}
+
+	if setting.Channels.Contains(models.ReminderChannelWebSocket) {
+		service.wsService.SendToUser(user.ID.String(), "reminder", reminder)
+	}
+
+	if setting.Channels.Contains(models.ReminderChannelEmail) {
+		body := fmt.Sprintf("%s\n\nDue: %s", subject, reminder.Task.EndDate.Format(time.RFC1123))
+		if err := service.mailer.Send(user.Email, subject, body); err != nil {
+			service.logger.Error("Failed to email reminder",
+				zap.String("taskID", reminder.TaskID.String()),
+				zap.String("userID", user.ID.String()),
+				zap.Error(err))
+		}
+	}
+}
+
+func formatOffset(minutes int) string {
+	switch {
+	case minutes%(24*60) == 0:
+		return pluralize(minutes/(24*60), "day")
+	case minutes%60 == 0:
+		return pluralize(minutes/60, "hour")
+	default:
+		return pluralize(minutes, "minute")
+	}
+}
+
+func pluralize(count int, unit string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", count, unit)
+}
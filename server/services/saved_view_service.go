@@ -0,0 +1,382 @@
+package services
+
+import (
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var taskPriorities = []string{"low", "medium", "high"}
+
+// viewColumns are the task columns a saved view can show, besides custom
+// fields given as "cf:<field id>".
+var viewColumns = []string{
+	"title", "description", "status", "priority", "assignee", "labels",
+	"start_date", "end_date", "created_at", "updated_at",
+}
+
+// viewGroupings are what a saved view can group tasks by, besides select and
+// user custom fields given as "cf:<field id>".
+var viewGroupings = []string{"status", "priority", "assignee", "label"}
+
+type SavedViewService interface {
+	GetViews(taskBoardID uuid.UUID, actorID uuid.UUID, params *pagination.Params) ([]models.SavedView, *pagination.Page, error)
+	CreateView(taskBoardID uuid.UUID, viewDTO *dto.SavedViewRequest, actorID uuid.UUID) (*models.SavedView, error)
+	UpdateView(taskBoardID uuid.UUID, viewID uuid.UUID, viewDTO *dto.SavedViewRequest, actorID uuid.UUID) (*models.SavedView, error)
+	DeleteView(taskBoardID uuid.UUID, viewID uuid.UUID, actorID uuid.UUID) error
+}
+
+type SavedViewServiceImpl struct {
+	savedViewRepo   repositories.SavedViewRepository
+	taskBoardRepo   repositories.TaskBoardRepository
+	customFieldRepo repositories.CustomFieldRepository
+	labelRepo       repositories.LabelRepository
+	engine          *policy.Engine
+	logger          *zap.Logger
+}
+
+func NewSavedViewService(
+	savedViewRepo repositories.SavedViewRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	customFieldRepo repositories.CustomFieldRepository,
+	labelRepo repositories.LabelRepository,
+	engine *policy.Engine,
+	logger *zap.Logger,
+) *SavedViewServiceImpl {
+	return &SavedViewServiceImpl{
+		savedViewRepo:   savedViewRepo,
+		taskBoardRepo:   taskBoardRepo,
+		customFieldRepo: customFieldRepo,
+		labelRepo:       labelRepo,
+		engine:          engine,
+		logger:          logger,
+	}
+}
+
+// GetViews lists the board's shared views and the actor's private ones. Each
+// view notes what it refers to that is no longer on the board.
+func (service *SavedViewServiceImpl) GetViews(taskBoardID uuid.UUID, actorID uuid.UUID, params *pagination.Params) ([]models.SavedView, *pagination.Page, error) {
+	views, page, err := service.savedViewRepo.FindVisible(taskBoardID, actorID, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields, labels, err := service.boardVocabulary(taskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range views {
+		views[i].Stale = resolveView(&views[i], fields, labels).stale
+	}
+	return views, page, nil
+}
+
+// CreateView saves a view for the actor. Sharing it on the board takes
+// view.share.
+func (service *SavedViewServiceImpl) CreateView(taskBoardID uuid.UUID, viewDTO *dto.SavedViewRequest, actorID uuid.UUID) (*models.SavedView, error) {
+	view := &models.SavedView{TaskBoardID: taskBoardID, OwnerID: actorID}
+	applySavedViewRequest(view, viewDTO)
+	if err := service.checkView(view, actorID); err != nil {
+		return nil, err
+	}
+
+	created, err := service.savedViewRepo.Create(view)
+	if err != nil {
+		service.logger.Error("Error creating saved view", zap.Error(err))
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateView replaces a view. Owners edit their own views; a shared view of
+// someone else's takes board.settings.
+func (service *SavedViewServiceImpl) UpdateView(taskBoardID uuid.UUID, viewID uuid.UUID, viewDTO *dto.SavedViewRequest, actorID uuid.UUID) (*models.SavedView, error) {
+	view, err := findVisibleView(service.savedViewRepo, taskBoardID, viewID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.authorizeOthers(view, actorID); err != nil {
+		return nil, err
+	}
+	if view.OwnerID != actorID && viewDTO.Visibility != models.ViewShared {
+		return nil, &ValidationError{message: "only the owner can make a shared view private"}
+	}
+
+	applySavedViewRequest(view, viewDTO)
+	if err := service.checkView(view, actorID); err != nil {
+		return nil, err
+	}
+
+	updated, err := service.savedViewRepo.Update(view)
+	if err != nil {
+		service.logger.Error("Error updating saved view", zap.Error(err))
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (service *SavedViewServiceImpl) DeleteView(taskBoardID uuid.UUID, viewID uuid.UUID, actorID uuid.UUID) error {
+	view, err := findVisibleView(service.savedViewRepo, taskBoardID, viewID, actorID)
+	if err != nil {
+		return err
+	}
+	if err := service.authorizeOthers(view, actorID); err != nil {
+		return err
+	}
+	return service.savedViewRepo.Delete(view.ID)
+}
+
+// authorizeOthers lets the actor change a view they do not own only when it
+// is shared and they manage the board's settings.
+func (service *SavedViewServiceImpl) authorizeOthers(view *models.SavedView, actorID uuid.UUID) error {
+	if view.OwnerID == actorID {
+		return nil
+	}
+	return authorize(service.engine, service.taskBoardRepo, actorID, policy.BoardSettings, policy.Resource{TaskBoardID: view.TaskBoardID})
+}
+
+// checkView rejects a view the board cannot apply in full, and a shared view
+// the actor may not share.
+func (service *SavedViewServiceImpl) checkView(view *models.SavedView, actorID uuid.UUID) error {
+	if view.Visibility == models.ViewShared {
+		if err := authorize(service.engine, service.taskBoardRepo, actorID, policy.ViewShare, policy.Resource{TaskBoardID: view.TaskBoardID}); err != nil {
+			return err
+		}
+	}
+
+	fields, labels, err := service.boardVocabulary(view.TaskBoardID)
+	if err != nil {
+		return err
+	}
+	if stale := resolveView(view, fields, labels).stale; len(stale) > 0 {
+		return &ValidationError{message: strings.Join(stale, "; ")}
+	}
+	return nil
+}
+
+func (service *SavedViewServiceImpl) boardVocabulary(taskBoardID uuid.UUID) ([]models.CustomField, []models.Label, error) {
+	fields, err := service.customFieldRepo.FindByTaskBoardID(taskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	labels, err := service.labelRepo.FindByTaskBoardID(taskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fields, labels, nil
+}
+
+func applySavedViewRequest(view *models.SavedView, viewDTO *dto.SavedViewRequest) {
+	view.Name = strings.TrimSpace(viewDTO.Name)
+	view.Visibility = viewDTO.Visibility
+	if view.Visibility == "" {
+		view.Visibility = models.ViewPrivate
+	}
+	view.Filter = models.ViewFilter{
+		Status:       viewDTO.Filter.Status,
+		Priority:     viewDTO.Filter.Priority,
+		Labels:       viewDTO.Filter.Labels,
+		Assignees:    viewDTO.Filter.Assignees,
+		CustomFields: viewDTO.Filter.CustomFields,
+	}
+	view.Sort = viewDTO.Sort
+	view.GroupBy = viewDTO.GroupBy
+	view.Columns = viewDTO.Columns
+}
+
+// findVisibleView returns a view of the board that the actor can see; other
+// users' private views are reported as missing.
+func findVisibleView(savedViewRepo repositories.SavedViewRepository, taskBoardID uuid.UUID, viewID uuid.UUID, actorID uuid.UUID) (*models.SavedView, error) {
+	view, err := savedViewRepo.FindByID(viewID)
+	if err != nil || view.TaskBoardID != taskBoardID {
+		return nil, fmt.Errorf("saved view not found")
+	}
+	if view.Visibility != models.ViewShared && view.OwnerID != actorID {
+		return nil, fmt.Errorf("saved view not found")
+	}
+	return view, nil
+}
+
+// resolvedView is what of a saved view the board can still apply.
+type resolvedView struct {
+	query   dto.TaskBoardQuery
+	groupBy string
+	columns []string
+	// stale notes each part of the view left out.
+	stale []string
+}
+
+// resolveView checks a view against the board's current custom fields and
+// labels. Whatever it refers to that is gone, such as a deleted label or
+// select option, is left out and noted, so the rest of the view keeps
+// working.
+func resolveView(view *models.SavedView, fields []models.CustomField, labels []models.Label) resolvedView {
+	var resolved resolvedView
+	stalef := func(format string, args ...interface{}) {
+		resolved.stale = append(resolved.stale, fmt.Sprintf(format, args...))
+	}
+
+	fieldsByID := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.ID.String()] = field
+	}
+	labelIDs := make(map[uuid.UUID]bool, len(labels))
+	for _, label := range labels {
+		labelIDs[label.ID] = true
+	}
+
+	for _, status := range view.Filter.Status {
+		if !containsString(models.TaskStatuses, status) {
+			stalef("unknown status %q", status)
+			continue
+		}
+		resolved.query.Status = append(resolved.query.Status, status)
+	}
+	for _, priority := range view.Filter.Priority {
+		if !containsString(taskPriorities, priority) {
+			stalef("unknown priority %q", priority)
+			continue
+		}
+		resolved.query.Priority = append(resolved.query.Priority, priority)
+	}
+	for _, labelID := range view.Filter.Labels {
+		if !labelIDs[labelID] {
+			stalef("unknown label %s", labelID)
+			continue
+		}
+		resolved.query.Labels = append(resolved.query.Labels, labelID.String())
+	}
+	for _, assignee := range view.Filter.Assignees {
+		if _, err := uuid.Parse(assignee); err != nil && assignee != "me" && assignee != "none" {
+			stalef("invalid assignee %q", assignee)
+			continue
+		}
+		resolved.query.Assignees = append(resolved.query.Assignees, assignee)
+	}
+
+	for key, expression := range view.Filter.CustomFields {
+		field, ok := fieldsByID[key]
+		if !ok {
+			stalef("unknown custom field %s", key)
+			continue
+		}
+		if field.Type == models.CustomFieldSingleSelect || field.Type == models.CustomFieldMultiSelect {
+			var kept []string
+			for _, option := range strings.Split(expression, ",") {
+				option = strings.TrimSpace(option)
+				if !field.Options.Contains(option) {
+					stalef("unknown option %q of custom field %s", option, field.Name)
+					continue
+				}
+				kept = append(kept, option)
+			}
+			if len(kept) == 0 {
+				continue
+			}
+			expression = strings.Join(kept, ",")
+		}
+		single := &dto.TaskBoardQuery{CustomFields: map[string]string{key: expression}}
+		if _, err := buildTaskFilter(fields, single); err != nil {
+			stalef("%s", err.Error())
+			continue
+		}
+		if resolved.query.CustomFields == nil {
+			resolved.query.CustomFields = make(map[string]string)
+		}
+		resolved.query.CustomFields[key] = expression
+	}
+
+	if view.Sort != "" {
+		if _, err := taskPage(fields, &dto.TaskBoardQuery{Sort: view.Sort}); err != nil {
+			stalef("%s", err.Error())
+		} else {
+			resolved.query.Sort = view.Sort
+		}
+	}
+
+	if view.GroupBy != "" {
+		if viewCustomField(fieldsByID, view.GroupBy, models.CustomFieldSingleSelect, models.CustomFieldMultiSelect, models.CustomFieldUser) || containsString(viewGroupings, view.GroupBy) {
+			resolved.groupBy = view.GroupBy
+		} else {
+			stalef("cannot group by %s", view.GroupBy)
+		}
+	}
+
+	for _, column := range view.Columns {
+		if viewCustomField(fieldsByID, column) || containsString(viewColumns, column) {
+			resolved.columns = append(resolved.columns, column)
+		} else {
+			stalef("unknown column %s", column)
+		}
+	}
+	return resolved
+}
+
+// viewCustomField reports whether key is "cf:<field id>" for one of the
+// board's fields, of one of types when any are given.
+func viewCustomField(fieldsByID map[string]models.CustomField, key string, types ...string) bool {
+	fieldKey, ok := strings.CutPrefix(key, "cf:")
+	if !ok {
+		return false
+	}
+	field, ok := fieldsByID[fieldKey]
+	if !ok {
+		return false
+	}
+	return len(types) == 0 || containsString(types, field.Type)
+}
+
+// withView fills in whatever the board query leaves out from the view's
+// resolved settings, and replaces "me" among the assignees with the actor.
+func withView(query dto.TaskBoardQuery, resolved *resolvedView, actorID uuid.UUID) dto.TaskBoardQuery {
+	if resolved != nil {
+		if len(query.Status) == 0 {
+			query.Status = resolved.query.Status
+		}
+		if len(query.Priority) == 0 {
+			query.Priority = resolved.query.Priority
+		}
+		if len(query.Labels) == 0 {
+			query.Labels = resolved.query.Labels
+		}
+		if len(query.Assignees) == 0 {
+			query.Assignees = resolved.query.Assignees
+		}
+		if query.Sort == "" {
+			query.Sort = resolved.query.Sort
+		}
+		customFields := make(map[string]string, len(resolved.query.CustomFields)+len(query.CustomFields))
+		for key, expression := range resolved.query.CustomFields {
+			customFields[key] = expression
+		}
+		for key, expression := range query.CustomFields {
+			customFields[key] = expression
+		}
+		query.CustomFields = customFields
+	}
+
+	assignees := make([]string, 0, len(query.Assignees))
+	for _, assignee := range query.Assignees {
+		if assignee == "me" {
+			assignee = actorID.String()
+		}
+		assignees = append(assignees, assignee)
+	}
+	query.Assignees = assignees
+	return query
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+	"server/search"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type SearchService interface {
+	SearchTasks(rawQuery string, params *pagination.Params, actorID uuid.UUID) ([]models.TaskSearchHit, *pagination.Page, error)
+}
+
+type SearchServiceImpl struct {
+	taskRepo      repositories.TaskRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	engine        *policy.Engine
+	logger        *zap.Logger
+}
+
+func NewSearchService(taskRepo repositories.TaskRepository, taskBoardRepo repositories.TaskBoardRepository, engine *policy.Engine, logger *zap.Logger) *SearchServiceImpl {
+	return &SearchServiceImpl{
+		taskRepo:      taskRepo,
+		taskBoardRepo: taskBoardRepo,
+		engine:        engine,
+		logger:        logger,
+	}
+}
+
+// SearchTasks runs a query in the search language over every board the
+// actor may view tasks on. Archived boards are only searched for
+// is:archived.
+func (service *SearchServiceImpl) SearchTasks(rawQuery string, params *pagination.Params, actorID uuid.UUID) ([]models.TaskSearchHit, *pagination.Page, error) {
+	query, err := search.Parse(rawQuery)
+	if err != nil {
+		return nil, nil, &ValidationError{message: err.Error()}
+	}
+
+	taskBoards, _, err := service.taskBoardRepo.FindByUserID(actorID, repositories.ListOptions{
+		IncludeArchived: query.Has(search.FieldIs, search.StateArchived),
+	}, pagination.All(repositories.TaskBoardPages))
+	if err != nil {
+		return nil, nil, err
+	}
+	taskBoardIDs := make([]uuid.UUID, 0, len(taskBoards))
+	for _, taskBoard := range taskBoards {
+		subject := subjectOn(service.taskBoardRepo, taskBoard.ID, actorID)
+		if service.engine.Allows(subject, policy.TaskView, policy.Resource{TaskBoardID: taskBoard.ID}) {
+			taskBoardIDs = append(taskBoardIDs, taskBoard.ID)
+		}
+	}
+
+	hits, page, err := service.taskRepo.Search(repositories.TaskSearch{
+		Query:        query,
+		TaskBoardIDs: taskBoardIDs,
+		ActorID:      actorID,
+		Now:          time.Now(),
+	}, params)
+	if err != nil {
+		service.logger.Error("Error searching tasks", zap.String("query", rawQuery), zap.Error(err))
+		return nil, nil, err
+	}
+	return hits, page, nil
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/repositories"
+	"server/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// A password-protected link stops accepting passwords for
+	// shareLockoutWindow once it has seen shareLockoutAttempts wrong ones.
+	shareLockoutAttempts = 10
+	shareLockoutWindow   = 15 * time.Minute
+)
+
+// ShareAccessRefusedError is returned when a share link exists but cannot be
+// used: it was revoked or expired, or its password is missing or wrong.
+type ShareAccessRefusedError struct {
+	Outcome string
+	message string
+}
+
+func (e *ShareAccessRefusedError) Error() string {
+	return e.message
+}
+
+type ShareLinkService interface {
+	CreateShareLink(taskBoardID uuid.UUID, shareDTO *dto.ShareLinkRequest, actorID uuid.UUID) (*models.ShareLink, error)
+	GetShareLinks(taskBoardID uuid.UUID, params *pagination.Params) ([]models.ShareLink, *pagination.Page, error)
+	RevokeShareLink(taskBoardID uuid.UUID, linkID uuid.UUID) error
+	GetShareLinkAccesses(taskBoardID uuid.UUID, linkID uuid.UUID, params *pagination.Params) ([]models.ShareLinkAccess, *pagination.Page, error)
+	GetPublicBoard(token string, password string, query *dto.TaskBoardQuery, ipAddress string, userAgent string) (*models.PublicTaskBoard, *pagination.Page, error)
+}
+
+type ShareLinkServiceImpl struct {
+	shareLinkRepo   repositories.ShareLinkRepository
+	taskBoardRepo   repositories.TaskBoardRepository
+	customFieldRepo repositories.CustomFieldRepository
+	logger          *zap.Logger
+}
+
+func NewShareLinkService(shareLinkRepo repositories.ShareLinkRepository, taskBoardRepo repositories.TaskBoardRepository, customFieldRepo repositories.CustomFieldRepository, logger *zap.Logger) *ShareLinkServiceImpl {
+	return &ShareLinkServiceImpl{
+		shareLinkRepo:   shareLinkRepo,
+		taskBoardRepo:   taskBoardRepo,
+		customFieldRepo: customFieldRepo,
+		logger:          logger,
+	}
+}
+
+// CreateShareLink returns the new link with its token and URL, which are not
+// shown again.
+func (service *ShareLinkServiceImpl) CreateShareLink(taskBoardID uuid.UUID, shareDTO *dto.ShareLinkRequest, actorID uuid.UUID) (*models.ShareLink, error) {
+	if shareDTO.ExpiresAt != nil && !shareDTO.ExpiresAt.After(time.Now()) {
+		return nil, &ValidationError{message: "expires_at must be in the future"}
+	}
+
+	token, err := utils.NewLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	link := &models.ShareLink{
+		TaskBoardID: taskBoardID,
+		TokenHash:   utils.HashLinkToken(token),
+		ExpiresAt:   shareDTO.ExpiresAt,
+		CreatedByID: actorID,
+	}
+	if shareDTO.Password != "" {
+		link.PasswordHash, err = utils.HashPassword(shareDTO.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := service.shareLinkRepo.Create(link)
+	if err != nil {
+		service.logger.Error("Error creating share link", zap.Error(err))
+		return nil, err
+	}
+	created.PasswordProtected = created.PasswordHash != ""
+	created.Token = token
+	created.URL = utils.ShareLink(token)
+
+	service.logger.Info("Share link created",
+		zap.String("taskBoardID", taskBoardID.String()),
+		zap.String("shareLinkID", created.ID.String()),
+		zap.String("actorID", actorID.String()))
+	return created, nil
+}
+
+func (service *ShareLinkServiceImpl) GetShareLinks(taskBoardID uuid.UUID, params *pagination.Params) ([]models.ShareLink, *pagination.Page, error) {
+	links, page, err := service.shareLinkRepo.FindByTaskBoardID(taskBoardID, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range links {
+		links[i].PasswordProtected = links[i].PasswordHash != ""
+	}
+	return links, page, nil
+}
+
+func (service *ShareLinkServiceImpl) RevokeShareLink(taskBoardID uuid.UUID, linkID uuid.UUID) error {
+	link, err := service.findShareLink(taskBoardID, linkID)
+	if err != nil {
+		return err
+	}
+	if link.RevokedAt != nil {
+		return &ConflictError{message: "share link is already revoked"}
+	}
+	return service.shareLinkRepo.Revoke(linkID)
+}
+
+// GetShareLinkAccesses returns the link's audit entries.
+func (service *ShareLinkServiceImpl) GetShareLinkAccesses(taskBoardID uuid.UUID, linkID uuid.UUID, params *pagination.Params) ([]models.ShareLinkAccess, *pagination.Page, error) {
+	if _, err := service.findShareLink(taskBoardID, linkID); err != nil {
+		return nil, nil, err
+	}
+	return service.shareLinkRepo.FindAccesses(linkID, params)
+}
+
+// GetPublicBoard serves a share link. Tasks are filtered like an authenticated
+// board request, minus archived and deleted ones, and collaborators are
+// listed without their emails. Every attempt on an existing link is audited.
+func (service *ShareLinkServiceImpl) GetPublicBoard(token string, password string, query *dto.TaskBoardQuery, ipAddress string, userAgent string) (*models.PublicTaskBoard, *pagination.Page, error) {
+	link, err := service.shareLinkRepo.FindByTokenHash(utils.HashLinkToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("share link not found")
+		}
+		return nil, nil, err
+	}
+
+	if err := service.checkAccess(link, password); err != nil {
+		if refused, ok := err.(*ShareAccessRefusedError); ok {
+			service.audit(link, refused.Outcome, ipAddress, userAgent)
+		}
+		return nil, nil, err
+	}
+
+	fields, err := service.customFieldRepo.FindByTaskBoardID(link.TaskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	query.IncludeArchived = false
+	query.IncludeDeleted = false
+	filter, err := buildTaskFilter(fields, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	params, err := taskPage(fields, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	taskBoard, page, err := service.taskBoardRepo.FindByIDWithFilter(link.TaskBoardID, filter, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("share link not found")
+	}
+	columns, err := boardColumns(service.taskBoardRepo, link.TaskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	users, err := service.taskBoardRepo.GetUsersOnTaskBoard(link.TaskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	collaborators := make([]models.PublicCollaborator, 0, len(users))
+	for _, user := range users {
+		collaborators = append(collaborators, models.PublicCollaborator{
+			UserID: user.UserID,
+			Name:   user.User.Name,
+			Role:   user.Role,
+		})
+	}
+
+	service.audit(link, models.ShareAccessGranted, ipAddress, userAgent)
+	return &models.PublicTaskBoard{
+		ID:            taskBoard.ID,
+		Title:         taskBoard.Title,
+		Description:   taskBoard.Description,
+		ArchivedAt:    taskBoard.ArchivedAt,
+		Columns:       columns,
+		Tasks:         taskBoard.Tasks,
+		Collaborators: collaborators,
+	}, page, nil
+}
+
+func (service *ShareLinkServiceImpl) checkAccess(link *models.ShareLink, password string) error {
+	now := time.Now()
+	if link.RevokedAt != nil {
+		return &ShareAccessRefusedError{Outcome: models.ShareAccessRevoked, message: "share link has been revoked"}
+	}
+	if !link.Active(now) {
+		return &ShareAccessRefusedError{Outcome: models.ShareAccessExpired, message: "share link has expired"}
+	}
+	if link.PasswordHash == "" {
+		return nil
+	}
+
+	failures, err := service.shareLinkRepo.CountAccesses(link.ID, models.ShareAccessWrongPassword, now.Add(-shareLockoutWindow))
+	if err != nil {
+		return err
+	}
+	if failures >= shareLockoutAttempts {
+		return &ShareAccessRefusedError{Outcome: models.ShareAccessLocked, message: "too many wrong passwords; try again later"}
+	}
+	if password == "" {
+		return &ShareAccessRefusedError{Outcome: models.ShareAccessPasswordRequired, message: "password required"}
+	}
+	if !utils.VerifyPassword(link.PasswordHash, password) {
+		return &ShareAccessRefusedError{Outcome: models.ShareAccessWrongPassword, message: "incorrect password"}
+	}
+	return nil
+}
+
+// audit records the attempt. A failure to record is logged but does not
+// change the response.
+func (service *ShareLinkServiceImpl) audit(link *models.ShareLink, outcome string, ipAddress string, userAgent string) {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	access := &models.ShareLinkAccess{
+		ShareLinkID: link.ID,
+		Outcome:     outcome,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+	}
+	if err := service.shareLinkRepo.RecordAccess(access); err != nil {
+		service.logger.Error("Error recording share link access",
+			zap.String("shareLinkID", link.ID.String()),
+			zap.Error(err))
+	}
+	service.logger.Info("Share link accessed",
+		zap.String("shareLinkID", link.ID.String()),
+		zap.String("taskBoardID", link.TaskBoardID.String()),
+		zap.String("outcome", outcome),
+		zap.String("ip", ipAddress))
+}
+
+func (service *ShareLinkServiceImpl) findShareLink(taskBoardID uuid.UUID, linkID uuid.UUID) (*models.ShareLink, error) {
+	link, err := service.shareLinkRepo.FindByID(linkID)
+	if err != nil || link.TaskBoardID != taskBoardID {
+		return nil, fmt.Errorf("share link not found")
+	}
+	link.PasswordProtected = link.PasswordHash != ""
+	return link, nil
+}
@@ -4,116 +4,312 @@ import (
 	"fmt"
 	"server/dto"
 	"server/models"
+	"server/pagination"
+	"server/policy"
 	"server/repositories"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type TaskBoardService interface {
-	CreateTaskBoard(taskBoardDTO *dto.TaskBoardRequest) (*models.UserTaskBoard, error)
-	FindTaskBoardByIDExtendTasks(taskBoardID uuid.UUID, status []string, priority []string) (*models.TaskBoard, error)
-    FindTaskBoardByUserID(userID uuid.UUID) ([]models.TaskBoard, error)
-	UpdateTaskBoard(taskID uuid.UUID, taskDTO *dto.TaskBoardRequest) (*models.TaskBoard, error)
-	DeleteTaskBoard(taskBoardID uuid.UUID) error
-	AddCollaboratorOnTaskBoard(addCollaboratorDTO dto.AddCollaborator) (*models.UserTaskBoard, error)
-	GetCollaboratorOnTaskBoard(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error)
+	CreateTaskBoard(taskBoardDTO *dto.TaskBoardRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.UserTaskBoard, error)
+	FindTaskBoardByIDExtendTasks(taskBoardID uuid.UUID, query *dto.TaskBoardQuery, actorID uuid.UUID) (*models.TaskBoard, *pagination.Page, error)
+    FindTaskBoardByUserID(userID uuid.UUID, options repositories.ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+	UpdateTaskBoard(taskID uuid.UUID, taskDTO *dto.TaskBoardRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.TaskBoard, error)
+	DeleteTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) error
+	AddCollaboratorOnTaskBoard(addCollaboratorDTO dto.AddCollaborator, actorID uuid.UUID, meta models.RequestMeta) (*models.UserTaskBoard, error)
+	GetCollaboratorOnTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID) ([]models.UserTaskBoard, error)
 	CheckUserRole(taskBoardID uuid.UUID, userID uuid.UUID) (*models.UserTaskBoard, error)
+	FindTaskResource(taskID uuid.UUID) (*policy.Resource, error)
+	GetPermissions(taskBoardID uuid.UUID, actorID uuid.UUID, taskID *uuid.UUID) (*models.BoardPermissions, error)
+	SetWIPLimits(taskBoardID uuid.UUID, limitsDTO *dto.SetWIPLimitsRequest, actorID uuid.UUID, meta models.RequestMeta) ([]models.TaskBoardColumn, error)
+	ArchiveTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.TaskBoard, error)
+	RestoreTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.TaskBoard, error)
+	GetTrash(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+	GetTaskTrash(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Task, *pagination.Page, error)
+	UpdateCollaboratorRole(taskBoardID uuid.UUID, userID uuid.UUID, roleDTO *dto.UpdateCollaboratorRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.UserTaskBoard, error)
+	RemoveCollaborator(taskBoardID uuid.UUID, userID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) error
+	TransferOwnership(taskBoardID uuid.UUID, transferDTO *dto.TransferOwnershipRequest, actorID uuid.UUID, meta models.RequestMeta) ([]models.UserTaskBoard, error)
 }
 
 type TaskBoardServiceImpl struct {
 	taskBoardRepo repositories.TaskBoardRepository
 	userRepo      repositories.UserRepository
+	customFieldRepo repositories.CustomFieldRepository
+	boardRoleRepo repositories.BoardRoleRepository
+	workspaceRepo repositories.WorkspaceRepository
+	invitationRepo repositories.InvitationRepository
+	savedViewRepo repositories.SavedViewRepository
+	labelRepo     repositories.LabelRepository
+	auditRepo     repositories.AuditLogRepository
+	notifier      *Notifier
+	transactor    repositories.Transactor
+	engine        *policy.Engine
 	logger   *zap.Logger
 }
 
-func NewTaskBoardService(taskBoardRepo repositories.TaskBoardRepository, logger *zap.Logger, userRepo repositories.UserRepository) *TaskBoardServiceImpl {
+func NewTaskBoardService(taskBoardRepo repositories.TaskBoardRepository, logger *zap.Logger, userRepo repositories.UserRepository, customFieldRepo repositories.CustomFieldRepository, boardRoleRepo repositories.BoardRoleRepository, workspaceRepo repositories.WorkspaceRepository, invitationRepo repositories.InvitationRepository, savedViewRepo repositories.SavedViewRepository, labelRepo repositories.LabelRepository, auditRepo repositories.AuditLogRepository, notifier *Notifier, transactor repositories.Transactor, engine *policy.Engine) *TaskBoardServiceImpl {
 	return &TaskBoardServiceImpl{
 		taskBoardRepo: taskBoardRepo,
 		userRepo:      userRepo,
+		customFieldRepo: customFieldRepo,
+		transactor:    transactor,
+		boardRoleRepo: boardRoleRepo,
+		workspaceRepo: workspaceRepo,
+		invitationRepo: invitationRepo,
+		savedViewRepo: savedViewRepo,
+		labelRepo:     labelRepo,
+		auditRepo:     auditRepo,
+		notifier:      notifier,
+		engine:        engine,
 		logger:   logger,
 	}
 }
 
-func (service *TaskBoardServiceImpl) CreateTaskBoard(taskBoardDTO *dto.TaskBoardRequest) (*models.UserTaskBoard, error) {
-	userID, err := uuid.Parse(taskBoardDTO.User)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user ID format")
-	}
-	
-	if _, err := service.userRepo.GetUserByID(userID); err != nil {
+// CreateTaskBoard makes a board owned by the actor, in the workspace asked
+// for if the actor may create boards there.
+func (service *TaskBoardServiceImpl) CreateTaskBoard(taskBoardDTO *dto.TaskBoardRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.UserTaskBoard, error) {
+	if _, err := service.userRepo.GetUserByID(actorID); err != nil {
 		service.logger.Error("User not found", zap.Error(err))
 		return nil, fmt.Errorf("user does not exist")
 	}
+
+	workspaceID, err := boardWorkspace(service.workspaceRepo, taskBoardDTO.WorkspaceID, actorID)
+	if err != nil {
+		return nil, err
+	}
 	
 	taskBoard := &models.TaskBoard{
 		Title:       taskBoardDTO.Title,
 		Description: taskBoardDTO.Description,
+		WorkspaceID: &workspaceID,
 	}
 
-	taskBoardResponse, err := service.taskBoardRepo.Create(taskBoard)
+	var userTaskBoardResponse *models.UserTaskBoard
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		repo := service.taskBoardRepo.WithTx(tx)
+		auditRepo := service.auditRepo.WithTx(tx)
+
+		taskBoardResponse, err := repo.Create(taskBoard)
+		if err != nil {
+			service.logger.Error("Error creating task board", zap.Error(err))
+			return err
+		}
+
+		userTaskBoard := &models.UserTaskBoard{
+			UserID:      actorID,
+			TaskBoardID: taskBoardResponse.ID,
+			Role:        policy.RoleOwner,
+		}
+
+		// Create the user-task board association
+		if userTaskBoardResponse, err = repo.CreateUserBoard(userTaskBoard); err != nil {
+			return err
+		}
+
+		err = writeAudit(auditRepo, meta, auditEntry{
+			action:      models.AuditBoardCreate,
+			actorID:     &actorID,
+			entityType:  models.AuditEntityTaskBoard,
+			entityID:    &taskBoardResponse.ID,
+			taskBoardID: &taskBoardResponse.ID,
+			after:       taskBoardAuditFields(taskBoardResponse),
+		})
+		if err != nil {
+			return err
+		}
+		return writeAudit(auditRepo, meta, auditEntry{
+			action:      models.AuditMemberAdd,
+			actorID:     &actorID,
+			entityType:  models.AuditEntityCollaborator,
+			entityID:    &actorID,
+			taskBoardID: &taskBoardResponse.ID,
+			after:       collaboratorAuditFields(policy.RoleOwner, nil),
+		})
+	})
 	if err != nil {
-		service.logger.Error("Error creating task board", zap.Error(err))
 		return nil, err
 	}
-	service.logger.Info("Task board created successfully", zap.String("taskBoardID", taskBoardResponse.ID.String()))
+	service.logger.Info("Task board created successfully", zap.String("taskBoardID", userTaskBoardResponse.TaskBoardID.String()))
+
+	return userTaskBoardResponse, nil
+}
+
 
-	userTaskBoard := &models.UserTaskBoard{
-		UserID:      uuid.MustParse(taskBoardDTO.User),
-		TaskBoardID: taskBoardResponse.ID,
-		Role:        "owner", 
+// FindTaskBoardByIDExtendTasks returns the board with the tasks matching the
+// query. With a saved view, the parts of the view the board can still apply
+// fill in whatever the query leaves out, and the view is returned with the
+// board.
+func (service *TaskBoardServiceImpl) FindTaskBoardByIDExtendTasks(taskBoardID uuid.UUID, query *dto.TaskBoardQuery, actorID uuid.UUID) (*models.TaskBoard, *pagination.Page, error) {
+	fields, err := service.customFieldRepo.FindByTaskBoardID(taskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var view *models.SavedView
+	var resolved *resolvedView
+	if query.View != "" {
+		viewID, err := uuid.Parse(query.View)
+		if err != nil {
+			return nil, nil, &ValidationError{message: "invalid view ID"}
+		}
+		view, err = findVisibleView(service.savedViewRepo, taskBoardID, viewID, actorID)
+		if err != nil {
+			return nil, nil, err
+		}
+		labels, err := service.labelRepo.FindByTaskBoardID(taskBoardID)
+		if err != nil {
+			return nil, nil, err
+		}
+		applied := resolveView(view, fields, labels)
+		resolved = &applied
+		view.GroupBy, view.Columns, view.Stale = applied.groupBy, applied.columns, applied.stale
 	}
 
-	// Create the user-task board association
-	userTaskBoardResponse, err := service.taskBoardRepo.CreateUserBoard(userTaskBoard)
+	merged := withView(*query, resolved, actorID)
+	filter, err := buildTaskFilter(fields, &merged)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return userTaskBoardResponse, nil
-}
+	params, err := taskPage(fields, &merged)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	taskBoard, page, err := service.taskBoardRepo.FindByIDWithFilter(taskBoardID, filter, params)
+	if err != nil {
+		return nil, nil, err
+	}
 
-func (service *TaskBoardServiceImpl) FindTaskBoardByIDExtendTasks(taskBoardID uuid.UUID, status []string, priority []string) (*models.TaskBoard, error) {
-	taskBoard, err := service.taskBoardRepo.FindByIDWithFilter(taskBoardID, status, priority)
+	columns, err := boardColumns(service.taskBoardRepo, taskBoardID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return taskBoard, nil
+	taskBoard.Columns = columns
+	taskBoard.View = view
+
+	return taskBoard, page, nil
 }
 
 
 
-func (service *TaskBoardServiceImpl) FindTaskBoardByUserID(userID uuid.UUID) ([]models.TaskBoard, error) {
-	taskBoards, err := service.taskBoardRepo.FindByUserID(userID)
+func (service *TaskBoardServiceImpl) FindTaskBoardByUserID(userID uuid.UUID, options repositories.ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	taskBoards, page, err := service.taskBoardRepo.FindByUserID(userID, options, params)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return taskBoards, nil
+	return taskBoards, page, nil
 }
 
-func (service *TaskBoardServiceImpl) UpdateTaskBoard(taskID uuid.UUID, taskDTO *dto.TaskBoardRequest) (*models.TaskBoard, error) {
+func (service *TaskBoardServiceImpl) UpdateTaskBoard(taskID uuid.UUID, taskDTO *dto.TaskBoardRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.TaskBoard, error) {
 	taskBoards, err := service.taskBoardRepo.FindByID(taskID)
 	if err != nil {
 		return nil, err
 	}
+	if taskBoards.ArchivedAt != nil {
+		return nil, &ConflictError{message: "task board is archived; restore it first"}
+	}
 
+	before := *taskBoards
 	taskBoards.Title = taskDTO.Title
 	taskBoards.Description = taskDTO.Description
 
-	updatedTaskBoard, err := service.taskBoardRepo.Update(taskID, taskBoards)
+	var updatedTaskBoard *models.TaskBoard
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if updatedTaskBoard, err = service.taskBoardRepo.WithTx(tx).Update(taskID, taskBoards); err != nil {
+			return err
+		}
+		return service.auditBoard(tx, models.AuditBoardUpdate, actorID, &before, updatedTaskBoard, meta)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return updatedTaskBoard, nil
 }
 
-func (service *TaskBoardServiceImpl) DeleteTaskBoard(taskBoardID uuid.UUID) error {
-	return service.taskBoardRepo.Delete(taskBoardID)
+// DeleteTaskBoard moves the board to the trash, from which it can be restored
+// until the retention job purges it.
+func (service *TaskBoardServiceImpl) DeleteTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) error {
+	taskBoard, err := service.taskBoardRepo.FindByID(taskBoardID)
+	if err != nil {
+		return fmt.Errorf("task board not found")
+	}
+	return service.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := service.taskBoardRepo.WithTx(tx).Delete(taskBoardID); err != nil {
+			return err
+		}
+		return service.auditBoard(tx, models.AuditBoardDelete, actorID, taskBoard, nil, meta)
+	})
+}
+
+func (service *TaskBoardServiceImpl) ArchiveTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.TaskBoard, error) {
+	taskBoard, err := service.taskBoardRepo.FindByID(taskBoardID)
+	if err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+	if taskBoard.ArchivedAt != nil {
+		return taskBoard, nil
+	}
+
+	now := time.Now().UTC()
+	before := *taskBoard
+	taskBoard.ArchivedAt = &now
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := service.taskBoardRepo.WithTx(tx).SetArchived(taskBoardID, &now); err != nil {
+			return err
+		}
+		return service.auditBoard(tx, models.AuditBoardArchive, actorID, &before, taskBoard, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taskBoard, nil
+}
+
+// RestoreTaskBoard brings a board back from the trash or the archive.
+func (service *TaskBoardServiceImpl) RestoreTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.TaskBoard, error) {
+	before, err := service.taskBoardRepo.FindByID(taskBoardID)
+	if err != nil {
+		if before, err = service.taskBoardRepo.FindDeletedByID(taskBoardID); err != nil {
+			return nil, fmt.Errorf("task board not found")
+		}
+	}
+
+	var taskBoard *models.TaskBoard
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		repo := service.taskBoardRepo.WithTx(tx)
+		if err := repo.Restore(taskBoardID); err != nil {
+			return err
+		}
+		var err error
+		if taskBoard, err = repo.FindByID(taskBoardID); err != nil {
+			return err
+		}
+		return service.auditBoard(tx, models.AuditBoardRestore, actorID, before, taskBoard, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taskBoard, nil
+}
+
+func (service *TaskBoardServiceImpl) GetTrash(userID uuid.UUID, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	return service.taskBoardRepo.FindDeletedByOwnerID(userID, params)
+}
+
+func (service *TaskBoardServiceImpl) GetTaskTrash(taskBoardID uuid.UUID, params *pagination.Params) ([]models.Task, *pagination.Page, error) {
+	return service.taskBoardRepo.FindDeletedTasks(taskBoardID, params)
 }
 
-func (service *TaskBoardServiceImpl) AddCollaboratorOnTaskBoard(addCollaboratorDTO dto.AddCollaborator) (*models.UserTaskBoard, error) {
+// AddCollaboratorOnTaskBoard adds a user by email. Only owners may add
+// another owner.
+func (service *TaskBoardServiceImpl) AddCollaboratorOnTaskBoard(addCollaboratorDTO dto.AddCollaborator, actorID uuid.UUID, meta models.RequestMeta) (*models.UserTaskBoard, error) {
 	user, err := service.userRepo.FindByEmail(addCollaboratorDTO.Email)
 	if err != nil {
 		return nil, fmt.Errorf("error finding user: %v", err)
@@ -126,11 +322,42 @@ func (service *TaskBoardServiceImpl) AddCollaboratorOnTaskBoard(addCollaboratorD
 		return nil, fmt.Errorf("task board not found")
 	}
 
-	if _, err := service.taskBoardRepo.CheckUserRole(addCollaboratorDTO.TaskBoardID, user.ID); err == nil {
+	if _, err := service.taskBoardRepo.FindCollaborator(addCollaboratorDTO.TaskBoardID, user.ID); err == nil {
 		return nil, fmt.Errorf("user already exists on this task board")
 	}
 
-	return service.taskBoardRepo.AddCollaborator(user.ID, addCollaboratorDTO.TaskBoardID, repositories.Role(addCollaboratorDTO.Role))
+	actor := subjectOn(service.taskBoardRepo, addCollaboratorDTO.TaskBoardID, actorID)
+	if addCollaboratorDTO.Role == policy.RoleOwner && actor.Role != policy.RoleOwner {
+		return nil, &ForbiddenError{message: "only board owners can add owners"}
+	}
+	boardRoleID, err := customRoleID(service.boardRoleRepo, addCollaboratorDTO.TaskBoardID, addCollaboratorDTO.Role, addCollaboratorDTO.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var collaborator *models.UserTaskBoard
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		var err error
+		collaborator, err = service.taskBoardRepo.WithTx(tx).AddCollaborator(user.ID, addCollaboratorDTO.TaskBoardID, repositories.Role(addCollaboratorDTO.Role), boardRoleID)
+		if err != nil {
+			return err
+		}
+		return writeAudit(service.auditRepo.WithTx(tx), meta, auditEntry{
+			action:      models.AuditMemberAdd,
+			actorID:     &actorID,
+			entityType:  models.AuditEntityCollaborator,
+			entityID:    &user.ID,
+			taskBoardID: &addCollaboratorDTO.TaskBoardID,
+			after:       collaboratorAuditFields(addCollaboratorDTO.Role, boardRoleID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.notifier.AddedToBoard(actorID, user.ID, addCollaboratorDTO.TaskBoardID)
+
+	return collaborator, nil
 }
 
 func (service *TaskBoardServiceImpl) CheckUserRole(taskBoardID uuid.UUID, userID uuid.UUID) (*models.UserTaskBoard, error) {
@@ -141,10 +368,378 @@ func (service *TaskBoardServiceImpl) CheckUserRole(taskBoardID uuid.UUID, userID
 	return userTaskBoard, nil
 }
 
-func (service *TaskBoardServiceImpl) GetCollaboratorOnTaskBoard(taskBoardID uuid.UUID) ([]models.UserTaskBoard, error) {
+// FindTaskResource describes a task to the policy: its board, and its creator
+// as the user it belongs to.
+func (service *TaskBoardServiceImpl) FindTaskResource(taskID uuid.UUID) (*policy.Resource, error) {
+	task, err := service.taskBoardRepo.FindTaskRef(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	return &policy.Resource{TaskBoardID: task.TaskBoardID, OwnerID: task.CreatedByID}, nil
+}
+
+// GetPermissions lists what the actor may do on the board, or on one of its
+// tasks when taskID is given, so clients can hide controls up front.
+func (service *TaskBoardServiceImpl) GetPermissions(taskBoardID uuid.UUID, actorID uuid.UUID, taskID *uuid.UUID) (*models.BoardPermissions, error) {
+	resource := &policy.Resource{TaskBoardID: taskBoardID}
+	if taskID != nil {
+		taskResource, err := service.FindTaskResource(*taskID)
+		if err != nil || taskResource.TaskBoardID != taskBoardID {
+			return nil, fmt.Errorf("task not found")
+		}
+		resource = taskResource
+	}
+
+	subject := subjectOn(service.taskBoardRepo, taskBoardID, actorID)
+	permissions := []string{}
+	for _, permission := range subject.Permissions {
+		permissions = append(permissions, string(permission))
+	}
+	actions := []string{}
+	for _, action := range service.engine.Allowed(subject, *resource) {
+		actions = append(actions, string(action))
+	}
+
+	return &models.BoardPermissions{
+		TaskBoardID: taskBoardID,
+		TaskID:      taskID,
+		Role:        subject.Role,
+		Permissions: permissions,
+		Actions:     actions,
+	}, nil
+}
+
+// GetCollaboratorOnTaskBoard lists the board's members. Those who may manage
+// members also see open invitations, with their status, after the members.
+func (service *TaskBoardServiceImpl) GetCollaboratorOnTaskBoard(taskBoardID uuid.UUID, actorID uuid.UUID) ([]models.UserTaskBoard, error) {
 	users, err := service.taskBoardRepo.GetUsersOnTaskBoard(taskBoardID)
 	if err != nil {
 		return nil, err 
 	}
+	for i := range users {
+		users[i].Status = models.CollaboratorActive
+	}
+
+	subject := subjectOn(service.taskBoardRepo, taskBoardID, actorID)
+	if !service.engine.Allows(subject, policy.MemberManage, policy.Resource{TaskBoardID: taskBoardID}) {
+		return users, nil
+	}
+	invitations, _, err := service.invitationRepo.FindByTaskBoardID(taskBoardID, pagination.All(repositories.InvitationPages))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range invitations {
+		invitation := &invitations[i]
+		invitation.Status = invitation.CurrentStatus(now)
+		users = append(users, models.UserTaskBoard{
+			TaskBoardID: taskBoardID,
+			Role:        invitation.Role,
+			BoardRoleID: invitation.BoardRoleID,
+			BoardRole:   invitation.BoardRole,
+			CreatedAt:   invitation.CreatedAt,
+			UpdatedAt:   invitation.UpdatedAt,
+			User:        models.User{Email: invitation.Email},
+			Status:      invitation.Status,
+			Invitation:  invitation,
+		})
+	}
 	return users, nil 
 }
+
+// UpdateCollaboratorRole changes a collaborator's role. It takes
+// member.manage, only owners may grant or take away the owner role, and the
+// board must keep at least one owner afterwards.
+func (service *TaskBoardServiceImpl) UpdateCollaboratorRole(taskBoardID uuid.UUID, userID uuid.UUID, roleDTO *dto.UpdateCollaboratorRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.UserTaskBoard, error) {
+	role := roleDTO.Role
+	boardRoleID, err := customRoleID(service.boardRoleRepo, taskBoardID, role, roleDTO.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.changeMembership(taskBoardID, func(repo repositories.TaskBoardRepository, auditRepo repositories.AuditLogRepository, roles map[uuid.UUID]string) error {
+		if !service.allowsMember(repo, taskBoardID, actorID, policy.MemberManage, userID) {
+			return forbidden(policy.MemberManage)
+		}
+		current, ok := roles[userID]
+		if !ok {
+			return fmt.Errorf("collaborator not found")
+		}
+		if (role == policy.RoleOwner || current == policy.RoleOwner) && subjectOn(repo, taskBoardID, actorID).Role != policy.RoleOwner {
+			return &ForbiddenError{message: "only board owners can grant or take away the owner role"}
+		}
+		previous, err := repo.FindCollaborator(taskBoardID, userID)
+		if err != nil {
+			return err
+		}
+		if err := repo.UpdateCollaboratorRole(taskBoardID, userID, role, boardRoleID); err != nil {
+			return err
+		}
+		return writeAudit(auditRepo, meta, auditEntry{
+			action:      models.AuditMemberUpdate,
+			actorID:     &actorID,
+			entityType:  models.AuditEntityCollaborator,
+			entityID:    &userID,
+			taskBoardID: &taskBoardID,
+			before:      collaboratorAuditFields(previous.Role, previous.BoardRoleID),
+			after:       collaboratorAuditFields(role, boardRoleID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("Collaborator role changed",
+		zap.String("taskBoardID", taskBoardID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("role", role),
+		zap.String("changedBy", actorID.String()),
+	)
+	return service.taskBoardRepo.CheckUserRole(taskBoardID, userID)
+}
+
+// RemoveCollaborator takes a user off the board and unassigns their tasks
+// there. The default policy lets owners remove anyone and every user remove
+// themselves, but the last owner has to transfer ownership before leaving.
+func (service *TaskBoardServiceImpl) RemoveCollaborator(taskBoardID uuid.UUID, userID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) error {
+	err := service.changeMembership(taskBoardID, func(repo repositories.TaskBoardRepository, auditRepo repositories.AuditLogRepository, roles map[uuid.UUID]string) error {
+		if !service.allowsMember(repo, taskBoardID, actorID, policy.MemberRemove, userID) {
+			return forbidden(policy.MemberRemove)
+		}
+		current, ok := roles[userID]
+		if !ok {
+			return fmt.Errorf("collaborator not found")
+		}
+		if current == policy.RoleOwner && actorID != userID && subjectOn(repo, taskBoardID, actorID).Role != policy.RoleOwner {
+			return &ForbiddenError{message: "only board owners can remove owners"}
+		}
+		previous, err := repo.FindCollaborator(taskBoardID, userID)
+		if err != nil {
+			return err
+		}
+		if err := repo.RemoveCollaborator(taskBoardID, userID); err != nil {
+			return err
+		}
+		if err := repo.UnassignUserTasks(taskBoardID, userID); err != nil {
+			return err
+		}
+		return writeAudit(auditRepo, meta, auditEntry{
+			action:      models.AuditMemberRemove,
+			actorID:     &actorID,
+			entityType:  models.AuditEntityCollaborator,
+			entityID:    &userID,
+			taskBoardID: &taskBoardID,
+			before:      collaboratorAuditFields(previous.Role, previous.BoardRoleID),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	service.logger.Info("Collaborator removed",
+		zap.String("taskBoardID", taskBoardID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("removedBy", actorID.String()),
+	)
+	return nil
+}
+
+// TransferOwnership makes another collaborator an owner and steps the acting
+// owner down in the same transaction.
+func (service *TaskBoardServiceImpl) TransferOwnership(taskBoardID uuid.UUID, transferDTO *dto.TransferOwnershipRequest, actorID uuid.UUID, meta models.RequestMeta) ([]models.UserTaskBoard, error) {
+	if transferDTO.UserID == actorID {
+		return nil, &ValidationError{message: "you already own this task board"}
+	}
+
+	previousOwnerRole := transferDTO.PreviousOwnerRole
+	if previousOwnerRole == "" {
+		previousOwnerRole = policy.RoleEditor
+	}
+
+	err := service.changeMembership(taskBoardID, func(repo repositories.TaskBoardRepository, auditRepo repositories.AuditLogRepository, roles map[uuid.UUID]string) error {
+		// Only an owner has ownership to hand over, whatever the policy grants.
+		if roles[actorID] != policy.RoleOwner || !service.allowsMember(repo, taskBoardID, actorID, policy.MemberManage, transferDTO.UserID) {
+			return &ForbiddenError{message: "only board owners can transfer ownership"}
+		}
+		if _, ok := roles[transferDTO.UserID]; !ok {
+			return fmt.Errorf("collaborator not found")
+		}
+		if err := repo.UpdateCollaboratorRole(taskBoardID, transferDTO.UserID, policy.RoleOwner, nil); err != nil {
+			return err
+		}
+		if err := repo.UpdateCollaboratorRole(taskBoardID, actorID, previousOwnerRole, nil); err != nil {
+			return err
+		}
+		// The changed roles are keyed by user ID.
+		return writeAudit(auditRepo, meta, auditEntry{
+			action:      models.AuditBoardTransfer,
+			actorID:     &actorID,
+			entityType:  models.AuditEntityTaskBoard,
+			entityID:    &taskBoardID,
+			taskBoardID: &taskBoardID,
+			before: auditFields{
+				actorID.String():              roles[actorID],
+				transferDTO.UserID.String(): roles[transferDTO.UserID],
+			},
+			after: auditFields{
+				actorID.String():              previousOwnerRole,
+				transferDTO.UserID.String(): policy.RoleOwner,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("Task board ownership transferred",
+		zap.String("taskBoardID", taskBoardID.String()),
+		zap.String("fromUserID", actorID.String()),
+		zap.String("toUserID", transferDTO.UserID.String()),
+	)
+	return service.taskBoardRepo.GetUsersOnTaskBoard(taskBoardID)
+}
+
+// auditBoard records a change to the board itself inside tx; before is nil
+// for a created board and after for a deleted one.
+func (service *TaskBoardServiceImpl) auditBoard(tx *gorm.DB, action string, actorID uuid.UUID, before *models.TaskBoard, after *models.TaskBoard, meta models.RequestMeta) error {
+	taskBoard := after
+	if taskBoard == nil {
+		taskBoard = before
+	}
+	return writeAudit(service.auditRepo.WithTx(tx), meta, auditEntry{
+		action:      action,
+		actorID:     &actorID,
+		entityType:  models.AuditEntityTaskBoard,
+		entityID:    &taskBoard.ID,
+		taskBoardID: &taskBoard.ID,
+		before:      taskBoardAuditFields(before),
+		after:       taskBoardAuditFields(after),
+	})
+}
+
+// allowsMember evaluates the policy inside the membership transaction; the
+// membership being changed belongs to userID.
+func (service *TaskBoardServiceImpl) allowsMember(repo repositories.TaskBoardRepository, taskBoardID uuid.UUID, actorID uuid.UUID, action policy.Action, userID uuid.UUID) bool {
+	subject := subjectOn(repo, taskBoardID, actorID)
+	return service.engine.Allows(subject, action, policy.Resource{TaskBoardID: taskBoardID, OwnerID: &userID})
+}
+
+// customRoleID checks that a custom role belongs to the board; preset roles
+// need no ID.
+func customRoleID(boardRoleRepo repositories.BoardRoleRepository, taskBoardID uuid.UUID, role string, roleID *uuid.UUID) (*uuid.UUID, error) {
+	if role != policy.RoleCustom {
+		return nil, nil
+	}
+	if roleID == nil {
+		return nil, &ValidationError{message: "role_id is required for a custom role"}
+	}
+	boardRole, err := boardRoleRepo.FindByID(*roleID)
+	if err != nil || boardRole.TaskBoardID != taskBoardID {
+		return nil, fmt.Errorf("role not found")
+	}
+	return &boardRole.ID, nil
+}
+
+// changeMembership runs change against the board's locked memberships, given
+// as a map of user ID to role, and rolls it back if the board would be left
+// without an owner. change gets repositories bound to the transaction.
+func (service *TaskBoardServiceImpl) changeMembership(taskBoardID uuid.UUID, change func(repo repositories.TaskBoardRepository, auditRepo repositories.AuditLogRepository, roles map[uuid.UUID]string) error) error {
+	return service.transactor.Transaction(func(tx *gorm.DB) error {
+		repo := service.taskBoardRepo.WithTx(tx)
+
+		collaborators, err := repo.LockCollaborators(taskBoardID)
+		if err != nil {
+			return err
+		}
+		roles := make(map[uuid.UUID]string, len(collaborators))
+		for _, collaborator := range collaborators {
+			roles[collaborator.UserID] = collaborator.Role
+		}
+
+		if err := change(repo, service.auditRepo.WithTx(tx), roles); err != nil {
+			return err
+		}
+
+		owners, err := repo.CountOwners(taskBoardID)
+		if err != nil {
+			return err
+		}
+		if owners == 0 {
+			return &ConflictError{message: "a task board must keep at least one owner; transfer ownership first"}
+		}
+		return nil
+	})
+}
+
+func (service *TaskBoardServiceImpl) SetWIPLimits(taskBoardID uuid.UUID, limitsDTO *dto.SetWIPLimitsRequest, actorID uuid.UUID, meta models.RequestMeta) ([]models.TaskBoardColumn, error) {
+	if _, err := service.taskBoardRepo.FindByID(taskBoardID); err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+
+	seen := make(map[string]bool, len(limitsDTO.Limits))
+	limits := make([]models.TaskBoardStatusLimit, 0, len(limitsDTO.Limits))
+	for _, limit := range limitsDTO.Limits {
+		if seen[limit.Status] {
+			return nil, fmt.Errorf("duplicate limit for status %s", limit.Status)
+		}
+		seen[limit.Status] = true
+		limits = append(limits, models.TaskBoardStatusLimit{
+			Status: limit.Status,
+			Limit:  limit.Limit,
+		})
+	}
+
+	err := service.transactor.Transaction(func(tx *gorm.DB) error {
+		repo := service.taskBoardRepo.WithTx(tx)
+		previous, err := repo.GetStatusLimits(taskBoardID)
+		if err != nil {
+			return err
+		}
+		if err := repo.SetStatusLimits(taskBoardID, limits); err != nil {
+			service.logger.Error("Error saving WIP limits", zap.Error(err))
+			return err
+		}
+		return writeAudit(service.auditRepo.WithTx(tx), meta, auditEntry{
+			action:      models.AuditBoardWIPLimits,
+			actorID:     &actorID,
+			entityType:  models.AuditEntityTaskBoard,
+			entityID:    &taskBoardID,
+			taskBoardID: &taskBoardID,
+			before:      wipLimitAuditFields(previous),
+			after:       wipLimitAuditFields(limits),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return boardColumns(service.taskBoardRepo, taskBoardID)
+}
+
+// boardColumns counts every status column of the board regardless of any
+// filter applied to the returned tasks, so limits are always judged on the full board.
+func boardColumns(taskBoardRepo repositories.TaskBoardRepository, taskBoardID uuid.UUID) ([]models.TaskBoardColumn, error) {
+	counts, err := taskBoardRepo.CountTasksByStatus(taskBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := taskBoardRepo.GetStatusLimits(taskBoardID)
+	if err != nil {
+		return nil, err
+	}
+	limitByStatus := make(map[string]int, len(limits))
+	for _, limit := range limits {
+		limitByStatus[limit.Status] = limit.Limit
+	}
+
+	columns := make([]models.TaskBoardColumn, 0, len(models.TaskStatuses))
+	for _, status := range models.TaskStatuses {
+		column := models.TaskBoardColumn{Status: status, Count: counts[status]}
+		if limit, ok := limitByStatus[status]; ok {
+			column.Limit = &limit
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
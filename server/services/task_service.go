@@ -1,26 +1,50 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 	"server/dto"
 	"server/gateway"
 	"server/models"
+	"server/policy"
 	"server/repositories"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// maxBulkTasks bounds how many tasks one bulk request may touch.
+const maxBulkTasks = 500
+
+// errBulkRolledBack aborts the transaction of an atomic bulk operation in
+// which some task failed.
+var errBulkRolledBack = errors.New("bulk operation rolled back")
+
 type TaskService interface {
-	CreateTask(taskDTO *dto.AssignTask) (*models.Task, error)
+	CreateTask(taskDTO *dto.AssignTask, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error)
 	FindTaskByID(taskID uuid.UUID) (*models.Task, error)
-	UpdateTask(taskID uuid.UUID, taskDTO *dto.UpdateTaskRequest) (*models.Task, error)
-	DeleteTask(taskID uuid.UUID) error
+	UpdateTask(taskID uuid.UUID, taskDTO *dto.UpdateTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error)
+	MoveTask(taskID uuid.UUID, moveDTO *dto.MoveTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, []string, error)
+	DeleteTask(taskID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) error
+	BulkUpdateTasks(bulkDTO *dto.BulkTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.BulkTaskResult, error)
+	ArchiveTask(taskID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error)
+	RestoreTask(taskID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error)
 }
 
 type TaskServiceImpl struct {
 	taskRepo      repositories.TaskRepository
 	taskBoardRepo repositories.TaskBoardRepository
+	customFieldRepo repositories.CustomFieldRepository
+	labelRepo     repositories.LabelRepository
+	auditRepo     repositories.AuditLogRepository
+	watcherRepo   repositories.WatcherRepository
+	notifier      *Notifier
+	transactor    repositories.Transactor
+	engine        *policy.Engine
 	wsService     *gateway.WebSocketService
 	logger        *zap.Logger
 }
@@ -37,18 +61,62 @@ func (service *TaskServiceImpl) FindTaskByID(taskID uuid.UUID) (*models.Task, er
 func NewTaskService(
 	taskRepo repositories.TaskRepository,
 	taskBoardRepo repositories.TaskBoardRepository,
+	customFieldRepo repositories.CustomFieldRepository,
+	labelRepo repositories.LabelRepository,
+	auditRepo repositories.AuditLogRepository,
+	watcherRepo repositories.WatcherRepository,
+	notifier *Notifier,
+	transactor repositories.Transactor,
+	engine *policy.Engine,
 	wsService *gateway.WebSocketService,
 	logger *zap.Logger,
 ) *TaskServiceImpl {
 	return &TaskServiceImpl{
 		taskRepo:      taskRepo,
 		taskBoardRepo: taskBoardRepo,
+		customFieldRepo: customFieldRepo,
+		labelRepo:     labelRepo,
+		auditRepo:     auditRepo,
+		watcherRepo:   watcherRepo,
+		notifier:      notifier,
+		transactor:    transactor,
+		engine:        engine,
 		wsService:     wsService,
 		logger:        logger,
 	}
 }
 
-func (service *TaskServiceImpl) CreateTask(taskDTO *dto.AssignTask) (*models.Task, error) {
+// withTx returns a copy of the service whose repositories run inside tx.
+func (service *TaskServiceImpl) withTx(tx *gorm.DB) *TaskServiceImpl {
+	txService := *service
+	txService.taskRepo = service.taskRepo.WithTx(tx)
+	txService.taskBoardRepo = service.taskBoardRepo.WithTx(tx)
+	txService.customFieldRepo = service.customFieldRepo.WithTx(tx)
+	txService.labelRepo = service.labelRepo.WithTx(tx)
+	txService.auditRepo = service.auditRepo.WithTx(tx)
+	txService.watcherRepo = service.watcherRepo.WithTx(tx)
+	return &txService
+}
+
+func (service *TaskServiceImpl) CreateTask(taskDTO *dto.AssignTask, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error) {
+	// The board comes from the body, so the route cannot authorize it.
+	if err := service.authorize(actorID, policy.TaskCreate, policy.Resource{TaskBoardID: taskDTO.TaskBoardID}); err != nil {
+		return nil, err
+	}
+
+	if err := service.checkBoardWritable(taskDTO.TaskBoardID); err != nil {
+		return nil, err
+	}
+
+	if err := service.checkAssignee(taskDTO.TaskBoardID, taskDTO.AssigneeID); err != nil {
+		return nil, err
+	}
+
+	labels, err := service.boardLabels(taskDTO.TaskBoardID, taskDTO.LabelIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &models.Task{
 		TaskBoardID: taskDTO.TaskBoardID,
 		Title:       taskDTO.Title,
@@ -57,48 +125,1004 @@ func (service *TaskServiceImpl) CreateTask(taskDTO *dto.AssignTask) (*models.Tas
 		Priority:    taskDTO.Priority,
 		StartDate:   taskDTO.StartDate,
 		EndDate:     taskDTO.EndDate,
+		EstimateMinutes: taskDTO.EstimateMinutes,
+		AssigneeID:  taskDTO.AssigneeID,
+		CreatedByID: &actorID,
+		Labels:      labels,
+	}
+
+	customFieldValues, err := service.customFieldValues(taskDTO.TaskBoardID, taskDTO.CustomFields)
+	if err != nil {
+		return nil, err
 	}
+	task.CustomFieldValues = customFieldValues
 
-	taskResponse, err := service.taskRepo.Create(task)
+	var taskResponse *models.Task
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		var err error
+		taskResponse, err = service.withTx(tx).createTask(task, taskDTO.OverrideWIPLimit, meta)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	service.wsService.Broadcast("create", taskResponse)
+	service.announceCreated(taskResponse)
 
 	return taskResponse, nil
 }
 
-func (service *TaskServiceImpl) UpdateTask(taskID uuid.UUID, taskDTO *dto.UpdateTaskRequest) (*models.Task, error) {
-	task, err := service.taskRepo.FindByID(taskID)
+// createTask stores a task within the WIP limit of its column, has its
+// creator and assignee watch it and records it in the audit log, as done by
+// its creator. It must run in the transaction that writes the task; call
+// announceCreated once that commits.
+func (service *TaskServiceImpl) createTask(task *models.Task, overrideWIPLimit bool, meta models.RequestMeta) (*models.Task, error) {
+	// the actor is only checked for an override, which tasks without a
+	// creator never ask for
+	var actorID uuid.UUID
+	if task.CreatedByID != nil {
+		actorID = *task.CreatedByID
+	}
+	if err := service.checkWIPLimit(task.TaskBoardID, task.Status, actorID, overrideWIPLimit); err != nil {
+		return nil, err
+	}
+
+	created, err := service.taskRepo.Create(task)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.autoWatch(created.ID, created.CreatedByID, created.AssigneeID); err != nil {
+		return nil, err
+	}
+	if err := service.auditBy(models.AuditTaskCreate, created.CreatedByID, nil, created, meta); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// announceCreated sends a new task to its board's subscribers and tells its
+// watchers about it.
+func (service *TaskServiceImpl) announceCreated(task *models.Task) {
+	var actorID uuid.UUID
+	if task.CreatedByID != nil {
+		actorID = *task.CreatedByID
+	}
+	service.wsService.BroadcastToBoard(task.TaskBoardID.String(), "create", task)
+	service.notifier.TaskCreated(actorID, task)
+}
+
+func (service *TaskServiceImpl) UpdateTask(taskID uuid.UUID, taskDTO *dto.UpdateTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error) {
+	var updatedTask, before *models.Task
+	err := service.transactor.Transaction(func(tx *gorm.DB) error {
+		var err error
+		updatedTask, before, err = service.withTx(tx).updateTask(taskID, taskDTO, actorID, meta)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-		task.TaskBoardID = taskDTO.TaskBoardID
+	service.wsService.BroadcastToBoard(updatedTask.TaskBoardID.String(), "update", updatedTask)
+	service.notifier.TaskChanged(actorID, before, updatedTask)
+
+	return updatedTask, nil
+}
+
+// updateTask does the work of UpdateTask without announcing it, and also
+// returns the task as it was before.
+func (service *TaskServiceImpl) updateTask(taskID uuid.UUID, taskDTO *dto.UpdateTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, *models.Task, error) {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkTaskWritable(task); err != nil {
+		return nil, nil, err
+	}
+
+	if task.TaskBoardID != taskDTO.TaskBoardID {
+		return nil, nil, &ValidationError{message: "use POST /api/tasks/:id/move to move a task to another board"}
+	}
+
+	if task.Status != taskDTO.Status {
+		if err := service.checkWIPLimit(task.TaskBoardID, taskDTO.Status, actorID, taskDTO.OverrideWIPLimit); err != nil {
+			return nil, nil, err
+		}
+	}
+	before := *task
+
 		task.Title =       taskDTO.Title
 		task.Description= taskDTO.Description
 		task.Status =      taskDTO.Status
 		task.Priority =  taskDTO.Priority
 		task.StartDate =   taskDTO.StartDate
 		task.EndDate =     taskDTO.EndDate
+		task.EstimateMinutes = taskDTO.EstimateMinutes
+		task.CustomFieldValues = nil
+		task.Labels = nil
+		task.Assignee = nil
+
+	if taskDTO.ClearEstimate && taskDTO.EstimateMinutes != nil {
+		return nil, nil, &ValidationError{message: "estimate_minutes and clear_estimate cannot be used together"}
+	}
+	if taskDTO.Unassign && taskDTO.AssigneeID != nil {
+		return nil, nil, &ValidationError{message: "assignee_id and unassign cannot be used together"}
+	}
+	if taskDTO.ClearEstimate && before.EstimateMinutes != nil {
+		// Update skips nil fields, so the estimate is cleared on its own
+		if err := service.taskRepo.ClearEstimate(taskID, actorID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if taskDTO.AssigneeID != nil {
+		if err := service.checkAssignee(task.TaskBoardID, taskDTO.AssigneeID); err != nil {
+			return nil, nil, err
+		}
+		task.AssigneeID = taskDTO.AssigneeID
+	}
+	if taskDTO.Unassign {
+		// Update skips nil fields, so the assignee is cleared on its own
+		task.AssigneeID = nil
+		if before.AssigneeID != nil {
+			if err := service.taskRepo.SetAssignee(taskID, nil, actorID); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var labels []models.Label
+	if taskDTO.LabelIDs != nil {
+		if labels, err = service.boardLabels(task.TaskBoardID, taskDTO.LabelIDs); err != nil {
+			return nil, nil, err
+		}
+	}
 
-	updatedTask, err := service.taskRepo.Update(taskID, task)
+	var customFieldValues []models.TaskCustomFieldValue
+	if taskDTO.CustomFields != nil {
+		if customFieldValues, err = service.customFieldValues(taskDTO.TaskBoardID, taskDTO.CustomFields); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	updatedTask, err := service.taskRepo.Update(taskID, task, actorID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := service.autoWatch(taskID, taskDTO.AssigneeID); err != nil {
+		return nil, nil, err
+	}
+
+	if taskDTO.CustomFields != nil {
+		if err := service.customFieldRepo.ReplaceTaskValues(taskID, customFieldValues); err != nil {
+			return nil, nil, err
+		}
+		updatedTask.CustomFieldValues = customFieldValues
 	}
 
-	service.wsService.Broadcast("update", updatedTask)
+	if taskDTO.LabelIDs != nil {
+		if err := service.taskRepo.ReplaceLabels(taskID, labels); err != nil {
+			return nil, nil, err
+		}
+		updatedTask.Labels = labels
+	}
 
-	return updatedTask, nil
+	after, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := service.audit(models.AuditTaskUpdate, actorID, &before, after, meta); err != nil {
+		return nil, nil, err
+	}
+
+	return updatedTask, &before, nil
 }
 
-func (service *TaskServiceImpl) DeleteTask(taskID uuid.UUID) error {
-	if err := service.taskRepo.Delete(taskID); err != nil {
+// DeleteTask moves the task to the trash, from which it can be restored until
+// the retention job purges it.
+func (service *TaskServiceImpl) DeleteTask(taskID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) error {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
 		return fmt.Errorf("failed to delete task with ID %s: %w", taskID, err)
 	}
 
-	service.wsService.Broadcast("delete", taskID)
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := service.withTx(tx)
+		if err := txService.taskRepo.Delete(taskID); err != nil {
+			return fmt.Errorf("failed to delete task with ID %s: %w", taskID, err)
+		}
+		return txService.audit(models.AuditTaskDelete, actorID, task, nil, meta)
+	})
+	if err != nil {
+		return err
+	}
+
+	service.wsService.BroadcastToBoard(task.TaskBoardID.String(), "delete", taskID)
+
+	return nil
+}
+
+// ArchiveTask hides the task from its board and makes it read-only.
+func (service *TaskServiceImpl) ArchiveTask(taskID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error) {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := service.authorize(actorID, policy.TaskArchive, taskResource(task)); err != nil {
+		return nil, err
+	}
+	if task.ArchivedAt != nil {
+		return task, nil
+	}
+	if err := checkTaskWritable(task); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	before := *task
+	task.ArchivedAt = &now
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := service.withTx(tx)
+		if err := txService.taskRepo.SetArchived(taskID, &now); err != nil {
+			return err
+		}
+		return txService.audit(models.AuditTaskArchive, actorID, &before, task, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.wsService.BroadcastToBoard(task.TaskBoardID.String(), "archive", taskID)
+
+	return task, nil
+}
+
+// RestoreTask brings a task back from the trash or the archive. Its board
+// must be active, and the task counts against the board's WIP limits again.
+func (service *TaskServiceImpl) RestoreTask(taskID uuid.UUID, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, error) {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		if task, err = service.taskRepo.FindDeletedByID(taskID); err != nil {
+			return nil, fmt.Errorf("task not found")
+		}
+	}
+	if err := service.authorize(actorID, policy.TaskArchive, taskResource(task)); err != nil {
+		return nil, err
+	}
+	if task.ArchivedAt == nil && !task.DeletedAt.Valid {
+		return task, nil
+	}
+	if err := service.checkBoardWritable(task.TaskBoardID); err != nil {
+		return nil, err
+	}
+	var restoredTask *models.Task
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		txService := service.withTx(tx)
+		if err := txService.checkWIPLimit(task.TaskBoardID, task.Status, actorID, false); err != nil {
+			return err
+		}
+		if err := txService.taskRepo.Restore(taskID); err != nil {
+			return err
+		}
+		var err error
+		if restoredTask, err = txService.taskRepo.FindByID(taskID); err != nil {
+			return err
+		}
+		return txService.audit(models.AuditTaskRestore, actorID, task, restoredTask, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.wsService.BroadcastToBoard(restoredTask.TaskBoardID.String(), "restore", restoredTask)
+
+	return restoredTask, nil
+}
+
+// MoveTask moves a task to another board. The caller needs the editor role on
+// both boards. Custom field values are carried over to target fields with the
+// same name and type when still valid there, and dropped otherwise; the names
+// of dropped source fields are returned. Labels follow to same-named labels
+// on the target board, and the assignee stays if they can see the target
+// board. The task keeps its ID, so its time entries, recurrence and reminders
+// stay attached.
+func (service *TaskServiceImpl) MoveTask(taskID uuid.UUID, moveDTO *dto.MoveTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, []string, error) {
+	var movedTask *models.Task
+	var sourceBoardID uuid.UUID
+	var dropped []string
+	err := service.transactor.Transaction(func(tx *gorm.DB) error {
+		var err error
+		movedTask, sourceBoardID, dropped, err = service.withTx(tx).moveTask(taskID, moveDTO, actorID, meta)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event := map[string]interface{}{
+		"task":               movedTask,
+		"from_task_board_id": sourceBoardID,
+		"to_task_board_id":   movedTask.TaskBoardID,
+	}
+	service.wsService.BroadcastToBoard(sourceBoardID.String(), "move", event)
+	service.wsService.BroadcastToBoard(movedTask.TaskBoardID.String(), "move", event)
+
+	return movedTask, dropped, nil
+}
+
+// moveTask does the work of MoveTask without announcing it, and also returns
+// the board the task came from.
+func (service *TaskServiceImpl) moveTask(taskID uuid.UUID, moveDTO *dto.MoveTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.Task, uuid.UUID, []string, error) {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, uuid.Nil, nil, fmt.Errorf("task not found")
+	}
+
+	sourceBoardID := task.TaskBoardID
+	targetBoardID := moveDTO.TaskBoardID
+	if sourceBoardID == targetBoardID {
+		return nil, uuid.Nil, nil, &ValidationError{message: "task is already on this task board"}
+	}
+
+	if err := checkTaskWritable(task); err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+	if err := service.checkBoardWritable(targetBoardID); err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+
+	// Moving updates the task on its board and creates it on the other one.
+	if err := service.authorize(actorID, policy.TaskUpdate, taskResource(task)); err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+	if err := service.authorize(actorID, policy.TaskCreate, policy.Resource{TaskBoardID: targetBoardID}); err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+
+	status := task.Status
+	if moveDTO.Status != "" {
+		status = moveDTO.Status
+	}
+	if err := service.checkWIPLimit(targetBoardID, status, actorID, moveDTO.OverrideWIPLimit); err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+
+	values, dropped, err := service.mapCustomFieldValues(task, targetBoardID, moveDTO.CustomFields)
+	if err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+
+	labels, droppedLabels, err := service.mapLabels(task, targetBoardID)
+	if err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+
+	assigneeID := task.AssigneeID
+	if assigneeID != nil {
+		if _, err := service.taskBoardRepo.CheckUserRole(targetBoardID, *assigneeID); err != nil {
+			assigneeID = nil
+		}
+	}
+
+	movedTask, err := service.taskRepo.Move(taskID, repositories.TaskMove{
+		TaskBoardID: targetBoardID,
+		Status:      status,
+		Values:      values,
+		Labels:      labels,
+		AssigneeID:  assigneeID,
+		ActorID:     actorID,
+	})
+	if err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+
+	after, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+	if err := service.audit(models.AuditTaskMove, actorID, task, after, meta); err != nil {
+		return nil, uuid.Nil, nil, err
+	}
+
+	service.logger.Info("Task moved",
+		zap.String("taskID", taskID.String()),
+		zap.String("fromTaskBoardID", sourceBoardID.String()),
+		zap.String("toTaskBoardID", targetBoardID.String()),
+		zap.Strings("droppedCustomFields", dropped),
+		zap.Strings("droppedLabels", droppedLabels),
+		zap.Bool("unassigned", task.AssigneeID != nil && assigneeID == nil),
+	)
+
+	return movedTask, sourceBoardID, dropped, nil
+}
+
+// mapLabels finds the target board's labels named like the task's current
+// ones, case-insensitively, and returns the names that have no match.
+func (service *TaskServiceImpl) mapLabels(task *models.Task, targetBoardID uuid.UUID) ([]models.Label, []string, error) {
+	if len(task.Labels) == 0 {
+		return nil, nil, nil
+	}
+
+	targetLabels, err := service.labelRepo.FindByTaskBoardID(targetBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]models.Label, len(targetLabels))
+	for _, label := range targetLabels {
+		byName[strings.ToLower(label.Name)] = label
+	}
+
+	var labels []models.Label
+	var dropped []string
+	for _, label := range task.Labels {
+		if target, ok := byName[strings.ToLower(label.Name)]; ok {
+			labels = append(labels, target)
+		} else {
+			dropped = append(dropped, label.Name)
+		}
+	}
+	return labels, dropped, nil
+}
+
+// mapCustomFieldValues translates a task's values onto the target board's
+// fields, lets explicit input override them, and validates the result
+// against the target board, including its required fields.
+func (service *TaskServiceImpl) mapCustomFieldValues(task *models.Task, targetBoardID uuid.UUID, overrides map[string]json.RawMessage) ([]models.TaskCustomFieldValue, []string, error) {
+	sourceFields, err := service.customFieldRepo.FindByTaskBoardID(task.TaskBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetFields, err := service.customFieldRepo.FindByTaskBoardID(targetBoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetByKey := make(map[string]models.CustomField, len(targetFields))
+	for _, field := range targetFields {
+		targetByKey[customFieldKey(field)] = field
+	}
+
+	isTargetMember := func(userID uuid.UUID) bool {
+		_, err := service.taskBoardRepo.CheckUserRole(targetBoardID, userID)
+		return err == nil
+	}
+
+	input := make(map[string]json.RawMessage)
+	var dropped []string
+	for _, sourceField := range sourceFields {
+		raw, ok := customFieldJSON(sourceField, task.CustomFieldValues)
+		if !ok {
+			continue
+		}
+		targetField, ok := targetByKey[customFieldKey(sourceField)]
+		if !ok {
+			dropped = append(dropped, sourceField.Name)
+			continue
+		}
+		if _, err := parseCustomFieldValue(targetField, raw, isTargetMember); err != nil {
+			dropped = append(dropped, sourceField.Name)
+			continue
+		}
+		input[targetField.ID.String()] = raw
+	}
+	for key, raw := range overrides {
+		input[key] = raw
+	}
+
+	values, err := buildCustomFieldValues(targetFields, input, isTargetMember)
+	if err != nil {
+		return nil, nil, err
+	}
+	return values, dropped, nil
+}
+
+func customFieldKey(field models.CustomField) string {
+	return strings.ToLower(strings.TrimSpace(field.Name)) + "/" + field.Type
+}
+
+// customFieldJSON renders a task's stored values for one field in the JSON
+// form accepted by buildCustomFieldValues.
+func customFieldJSON(field models.CustomField, values []models.TaskCustomFieldValue) (json.RawMessage, bool) {
+	var selected []string
+	var single interface{}
+	for _, value := range values {
+		if value.CustomFieldID != field.ID {
+			continue
+		}
+		switch {
+		case value.NumberValue != nil:
+			single = *value.NumberValue
+		case value.DateValue != nil:
+			single = value.DateValue.Format(time.RFC3339)
+		case value.TextValue != nil && field.Type == models.CustomFieldMultiSelect:
+			selected = append(selected, *value.TextValue)
+		case value.TextValue != nil:
+			single = *value.TextValue
+		}
+	}
+
+	var data []byte
+	switch {
+	case selected != nil:
+		data, _ = json.Marshal(selected)
+	case single != nil:
+		data, _ = json.Marshal(single)
+	default:
+		return nil, false
+	}
+	return data, true
+}
+
+// checkWIPLimit rejects adding one more task to a full status column unless
+// someone the policy allows to override it explicitly asks to. It locks the
+// column's limit until the transaction ends, so it must run in the same
+// transaction as the write that adds the task.
+func (service *TaskServiceImpl) checkWIPLimit(taskBoardID uuid.UUID, status string, actorID uuid.UUID, override bool) error {
+	limit, err := service.taskBoardRepo.LockStatusLimit(taskBoardID, status)
+	if err != nil {
+		return err
+	}
+	if limit == nil {
+		return nil
+	}
+
+	count, err := service.taskRepo.CountByStatus(taskBoardID, status)
+	if err != nil {
+		return err
+	}
+	if count < int64(limit.Limit) {
+		return nil
+	}
+
+	if !override {
+		return &ConflictError{message: fmt.Sprintf("WIP limit reached for %s: %d of %d tasks", status, count, limit.Limit)}
+	}
+
+	if err := service.authorize(actorID, policy.TaskOverrideWIP, policy.Resource{TaskBoardID: taskBoardID}); err != nil {
+		return err
+	}
+
+	service.logger.Info("WIP limit overridden",
+		zap.String("taskBoardID", taskBoardID.String()),
+		zap.String("status", status),
+		zap.String("userID", actorID.String()),
+	)
+	return nil
+}
 
+func (service *TaskServiceImpl) customFieldValues(taskBoardID uuid.UUID, input map[string]json.RawMessage) ([]models.TaskCustomFieldValue, error) {
+	fields, err := service.customFieldRepo.FindByTaskBoardID(taskBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCustomFieldValues(fields, input, func(userID uuid.UUID) bool {
+		_, err := service.taskBoardRepo.CheckUserRole(taskBoardID, userID)
+		return err == nil
+	})
+}
+
+// checkAssignee requires an assignee to have access to the board, directly,
+// through a team or through its workspace.
+func (service *TaskServiceImpl) checkAssignee(taskBoardID uuid.UUID, assigneeID *uuid.UUID) error {
+	if assigneeID == nil {
+		return nil
+	}
+	if _, err := service.taskBoardRepo.CheckUserRole(taskBoardID, *assigneeID); err != nil {
+		return &ValidationError{message: "assignee must be a collaborator on the task board"}
+	}
+	return nil
+}
+
+// boardLabels loads the listed labels, rejecting any that belong to another board.
+func (service *TaskServiceImpl) boardLabels(taskBoardID uuid.UUID, labelIDs []uuid.UUID) ([]models.Label, error) {
+	labels, err := service.labelRepo.FindByIDs(taskBoardID, labelIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uuid.UUID]bool, len(labels))
+	for _, label := range labels {
+		found[label.ID] = true
+	}
+	for _, labelID := range labelIDs {
+		if !found[labelID] {
+			return nil, &ValidationError{message: fmt.Sprintf("label %s does not belong to the task board", labelID)}
+		}
+	}
+	return labels, nil
+}
+
+// BulkUpdateTasks applies one operation to many tasks in a single
+// transaction. Each task runs in its own savepoint, so a failing task never
+// leaves partial writes behind: in best_effort mode the other tasks are kept,
+// in atomic mode everything is rolled back. Every task gets a result, and
+// once the transaction commits each affected board receives one "bulk" event.
+func (service *TaskServiceImpl) BulkUpdateTasks(bulkDTO *dto.BulkTaskRequest, actorID uuid.UUID, meta models.RequestMeta) (*models.BulkTaskResult, error) {
+	if err := validateBulkRequest(bulkDTO); err != nil {
+		return nil, err
+	}
+
+	taskIDs, err := service.bulkTaskIDs(bulkDTO, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := bulkDTO.Mode
+	if mode == "" {
+		mode = "atomic"
+	}
+	result := &models.BulkTaskResult{
+		Operation: bulkDTO.Operation,
+		Mode:      mode,
+		Items:     make([]models.BulkTaskItem, 0, len(taskIDs)),
+	}
+	events := newBulkEvents(bulkDTO.Operation)
+	subjects := make(map[uuid.UUID]policy.Subject)
+	var changes []taskChange
+
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		for _, taskID := range taskIDs {
+			var task, before *models.Task
+			var boardIDs []uuid.UUID
+			err := tx.Transaction(func(itemTx *gorm.DB) error {
+				var err error
+				task, before, boardIDs, err = service.withTx(itemTx).applyBulkOperation(taskID, bulkDTO, actorID, subjects, meta)
+				return err
+			})
+
+			item := models.BulkTaskItem{TaskID: taskID, Status: models.BulkItemSucceeded}
+			if err != nil {
+				item.Status = models.BulkItemFailed
+				item.Error = err.Error()
+				result.Failed++
+			} else {
+				result.Succeeded++
+				events.add(task, boardIDs)
+				changes = append(changes, taskChange{before: before, after: task})
+			}
+			result.Items = append(result.Items, item)
+		}
+
+		if mode == "atomic" && result.Failed > 0 {
+			return errBulkRolledBack
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBulkRolledBack) {
+		for i := range result.Items {
+			if result.Items[i].Status == models.BulkItemSucceeded {
+				result.Items[i].Status = models.BulkItemRolledBack
+			}
+		}
+		result.Succeeded = 0
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Committed = true
+	service.logger.Info("Bulk task operation",
+		zap.String("operation", bulkDTO.Operation),
+		zap.String("mode", mode),
+		zap.String("userID", actorID.String()),
+		zap.Int("succeeded", result.Succeeded),
+		zap.Int("failed", result.Failed),
+	)
+	events.broadcast(service.wsService)
+	// edits notify like UpdateTask does; moves and deletions do not notify
+	if bulkDTO.Operation != "move" && bulkDTO.Operation != "delete" {
+		for _, change := range changes {
+			service.notifier.TaskChanged(actorID, change.before, change.after)
+		}
+	}
+
+	return result, nil
+}
+
+func validateBulkRequest(bulkDTO *dto.BulkTaskRequest) error {
+	if (len(bulkDTO.TaskIDs) == 0) == (bulkDTO.Filter == nil) {
+		return &ValidationError{message: "provide either task_ids or filter"}
+	}
+
+	switch bulkDTO.Operation {
+	case "update":
+		if bulkDTO.Status == "" && bulkDTO.Priority == "" {
+			return &ValidationError{message: "update needs a status or a priority"}
+		}
+	case "label":
+		if len(bulkDTO.AddLabelIDs) == 0 && len(bulkDTO.RemoveLabelIDs) == 0 {
+			return &ValidationError{message: "label needs add_label_ids or remove_label_ids"}
+		}
+	case "move":
+		if bulkDTO.TaskBoardID == uuid.Nil {
+			return &ValidationError{message: "move needs a task_board_id"}
+		}
+	}
+	return nil
+}
+
+// bulkTaskIDs resolves the request to a de-duplicated list of task IDs,
+// running the filter against its board when one is given.
+func (service *TaskServiceImpl) bulkTaskIDs(bulkDTO *dto.BulkTaskRequest, actorID uuid.UUID) ([]uuid.UUID, error) {
+	var candidates []uuid.UUID
+	if filter := bulkDTO.Filter; filter != nil {
+		if _, err := service.taskBoardRepo.CheckUserRole(filter.TaskBoardID, actorID); err != nil {
+			return nil, &ForbiddenError{message: "you do not have access to this task board"}
+		}
+
+		fields, err := service.customFieldRepo.FindByTaskBoardID(filter.TaskBoardID)
+		if err != nil {
+			return nil, err
+		}
+		taskFilter, err := buildTaskFilter(fields, &dto.TaskBoardQuery{
+			Status:       filter.Status,
+			Priority:     filter.Priority,
+			CustomFields: filter.CustomFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		taskBoard, _, err := service.taskBoardRepo.FindByIDWithFilter(filter.TaskBoardID, taskFilter, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range taskBoard.Tasks {
+			candidates = append(candidates, task.ID)
+		}
+	} else {
+		candidates = bulkDTO.TaskIDs
+	}
+
+	seen := make(map[uuid.UUID]bool, len(candidates))
+	taskIDs := make([]uuid.UUID, 0, len(candidates))
+	for _, taskID := range candidates {
+		if !seen[taskID] {
+			seen[taskID] = true
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+
+	if len(taskIDs) > maxBulkTasks {
+		return nil, &ValidationError{message: fmt.Sprintf("a bulk operation may touch at most %d tasks, got %d", maxBulkTasks, len(taskIDs))}
+	}
+	return taskIDs, nil
+}
+
+// applyBulkOperation applies the operation to one task. It returns the task
+// as it now stands, as it was before, and the boards that should hear about
+// it. Deleting is
+// authorized as task.delete and every other operation as task.update.
+func (service *TaskServiceImpl) applyBulkOperation(taskID uuid.UUID, bulkDTO *dto.BulkTaskRequest, actorID uuid.UUID, subjects map[uuid.UUID]policy.Subject, meta models.RequestMeta) (*models.Task, *models.Task, []uuid.UUID, error) {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("task not found")
+	}
+
+	action := policy.TaskUpdate
+	if bulkDTO.Operation == "delete" {
+		action = policy.TaskDelete
+	}
+	if !service.allowsOnBoard(subjects, actorID, action, taskResource(task)) {
+		return nil, nil, nil, forbidden(action)
+	}
+
+	if bulkDTO.Operation != "delete" {
+		if err := checkTaskWritable(task); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	switch bulkDTO.Operation {
+	case "update":
+		changes := &models.Task{Priority: bulkDTO.Priority}
+		if bulkDTO.Status != "" && bulkDTO.Status != task.Status {
+			if err := service.checkWIPLimit(task.TaskBoardID, bulkDTO.Status, actorID, bulkDTO.OverrideWIPLimit); err != nil {
+				return nil, nil, nil, err
+			}
+			changes.Status = bulkDTO.Status
+		}
+		if changes.Priority != "" || changes.Status != "" {
+			if _, err := service.taskRepo.Update(taskID, changes, actorID); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+
+	case "assign":
+		if err := service.checkAssignee(task.TaskBoardID, bulkDTO.AssigneeID); err != nil {
+			return nil, nil, nil, err
+		}
+		if err := service.taskRepo.SetAssignee(taskID, bulkDTO.AssigneeID, actorID); err != nil {
+			return nil, nil, nil, err
+		}
+		if err := service.autoWatch(taskID, bulkDTO.AssigneeID); err != nil {
+			return nil, nil, nil, err
+		}
+
+	case "label":
+		labels, err := service.boardLabels(task.TaskBoardID, bulkDTO.AddLabelIDs)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := service.taskRepo.AddLabels(taskID, labels); err != nil {
+			return nil, nil, nil, err
+		}
+		if err := service.taskRepo.RemoveLabels(taskID, bulkDTO.RemoveLabelIDs); err != nil {
+			return nil, nil, nil, err
+		}
+
+	case "move":
+		movedTask, sourceBoardID, _, err := service.moveTask(taskID, &dto.MoveTaskRequest{
+			TaskBoardID:      bulkDTO.TaskBoardID,
+			Status:           bulkDTO.Status,
+			OverrideWIPLimit: bulkDTO.OverrideWIPLimit,
+		}, actorID, meta)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return movedTask, task, []uuid.UUID{sourceBoardID, movedTask.TaskBoardID}, nil
+
+	case "delete":
+		if err := service.taskRepo.Delete(taskID); err != nil {
+			return nil, nil, nil, err
+		}
+		if err := service.audit(models.AuditTaskDelete, actorID, task, nil, meta); err != nil {
+			return nil, nil, nil, err
+		}
+		return task, task, []uuid.UUID{task.TaskBoardID}, nil
+	}
+
+	updatedTask, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := service.audit(models.AuditTaskUpdate, actorID, task, updatedTask, meta); err != nil {
+		return nil, nil, nil, err
+	}
+	return updatedTask, task, []uuid.UUID{task.TaskBoardID}, nil
+}
+
+// allowsOnBoard evaluates the policy for the actor on the resource's board,
+// caching the subject in subjects for the rest of the bulk operation.
+func (service *TaskServiceImpl) allowsOnBoard(subjects map[uuid.UUID]policy.Subject, actorID uuid.UUID, action policy.Action, resource policy.Resource) bool {
+	subject, ok := subjects[resource.TaskBoardID]
+	if !ok {
+		subject = subjectOn(service.taskBoardRepo, resource.TaskBoardID, actorID)
+		subjects[resource.TaskBoardID] = subject
+	}
+	return service.engine.Allows(subject, action, resource)
+}
+
+// bulkEvent is the single realtime message a board receives for a bulk
+// operation. Tasks is left out for deletes.
+type bulkEvent struct {
+	Operation string        `json:"operation"`
+	TaskIDs   []uuid.UUID   `json:"task_ids"`
+	Tasks     []models.Task `json:"tasks,omitempty"`
+}
+
+// taskChange is a task as it was and as it now is.
+type taskChange struct {
+	before *models.Task
+	after  *models.Task
+}
+
+type bulkEvents struct {
+	operation string
+	boards    []uuid.UUID
+	byBoard   map[uuid.UUID]*bulkEvent
+}
+
+func newBulkEvents(operation string) *bulkEvents {
+	return &bulkEvents{operation: operation, byBoard: make(map[uuid.UUID]*bulkEvent)}
+}
+
+func (events *bulkEvents) add(task *models.Task, boardIDs []uuid.UUID) {
+	for _, boardID := range boardIDs {
+		event, ok := events.byBoard[boardID]
+		if !ok {
+			event = &bulkEvent{Operation: events.operation}
+			events.byBoard[boardID] = event
+			events.boards = append(events.boards, boardID)
+		}
+		event.TaskIDs = append(event.TaskIDs, task.ID)
+		if events.operation != "delete" {
+			event.Tasks = append(event.Tasks, *task)
+		}
+	}
+}
+
+func (events *bulkEvents) broadcast(wsService *gateway.WebSocketService) {
+	for _, boardID := range events.boards {
+		wsService.BroadcastToBoard(boardID.String(), "bulk", events.byBoard[boardID])
+	}
+}
+
+func (service *TaskServiceImpl) authorize(actorID uuid.UUID, action policy.Action, resource policy.Resource) error {
+	return authorize(service.engine, service.taskBoardRepo, actorID, action, resource)
+}
+
+// audit records a change to a task; before is nil for a created task and
+// after for a deleted one. A task that changed boards is recorded on both,
+// so each board's log shows it arriving or leaving.
+func (service *TaskServiceImpl) audit(action string, actorID uuid.UUID, before *models.Task, after *models.Task, meta models.RequestMeta) error {
+	return service.auditBy(action, &actorID, before, after, meta)
+}
+
+// auditBy is audit for changes whose actor may be unknown, such as recurring
+// instances of tasks created before their creator was recorded.
+func (service *TaskServiceImpl) auditBy(action string, actorID *uuid.UUID, before *models.Task, after *models.Task, meta models.RequestMeta) error {
+	task := after
+	if task == nil {
+		task = before
+	}
+	boardIDs := []uuid.UUID{task.TaskBoardID}
+	if before != nil && after != nil && before.TaskBoardID != after.TaskBoardID {
+		boardIDs = []uuid.UUID{before.TaskBoardID, after.TaskBoardID}
+	}
+
+	for i := range boardIDs {
+		err := writeAudit(service.auditRepo, meta, auditEntry{
+			action:      action,
+			actorID:     actorID,
+			entityType:  models.AuditEntityTask,
+			entityID:    &task.ID,
+			taskBoardID: &boardIDs[i],
+			before:      taskAuditFields(before),
+			after:       taskAuditFields(after),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// autoWatch makes the given users watch the task, leaving alone those who
+// opted out of it. Nil users are skipped.
+func (service *TaskServiceImpl) autoWatch(taskID uuid.UUID, userIDs ...*uuid.UUID) error {
+	for _, userID := range userIDs {
+		if userID == nil {
+			continue
+		}
+		if err := service.watcherRepo.AutoWatchTask(taskID, *userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskResource describes a task to the policy; it belongs to its creator.
+func taskResource(task *models.Task) policy.Resource {
+	return policy.Resource{TaskBoardID: task.TaskBoardID, OwnerID: task.CreatedByID}
+}
+
+// checkBoardWritable rejects changes to a missing or archived board.
+func (service *TaskServiceImpl) checkBoardWritable(taskBoardID uuid.UUID) error {
+	taskBoard, err := service.taskBoardRepo.FindByID(taskBoardID)
+	if err != nil {
+		return &ValidationError{message: "task board not found"}
+	}
+	if taskBoard.ArchivedAt != nil {
+		return &ConflictError{message: "task board is archived; restore it first"}
+	}
+	return nil
+}
+
+// checkTaskWritable rejects changes to an archived task or to a task on an
+// archived or deleted board. task.TaskBoard must be preloaded; a deleted
+// board preloads as the zero value.
+func checkTaskWritable(task *models.Task) error {
+	if task.ArchivedAt != nil {
+		return &ConflictError{message: "task is archived; restore it first"}
+	}
+	if task.TaskBoard.ID == uuid.Nil {
+		return &ConflictError{message: "task board is deleted; restore it first"}
+	}
+	if task.TaskBoard.ArchivedAt != nil {
+		return &ConflictError{message: "task board is archived; restore it first"}
+	}
 	return nil
 }
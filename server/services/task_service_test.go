@@ -0,0 +1,200 @@
+package services
+
+import (
+	"server/dto"
+	"server/models"
+	"server/repositories"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// storedTaskRepository holds one task. Like the real Update, it leaves the
+// assignee and estimate alone when the changes carry none.
+type storedTaskRepository struct {
+	repositories.TaskRepository
+	task models.Task
+}
+
+func (repo *storedTaskRepository) FindByID(taskID uuid.UUID) (*models.Task, error) {
+	task := repo.task
+	return &task, nil
+}
+
+func (repo *storedTaskRepository) Update(taskID uuid.UUID, changes *models.Task, actorID uuid.UUID) (*models.Task, error) {
+	assigneeID, estimateMinutes := repo.task.AssigneeID, repo.task.EstimateMinutes
+	repo.task = *changes
+	if changes.AssigneeID == nil {
+		repo.task.AssigneeID = assigneeID
+	}
+	if changes.EstimateMinutes == nil {
+		repo.task.EstimateMinutes = estimateMinutes
+	}
+	return repo.FindByID(taskID)
+}
+
+func (repo *storedTaskRepository) SetAssignee(taskID uuid.UUID, assigneeID *uuid.UUID, actorID uuid.UUID) error {
+	repo.task.AssigneeID = assigneeID
+	return nil
+}
+
+func (repo *storedTaskRepository) ClearEstimate(taskID uuid.UUID, actorID uuid.UUID) error {
+	repo.task.EstimateMinutes = nil
+	return nil
+}
+
+type discardAuditLogRepository struct {
+	repositories.AuditLogRepository
+}
+
+func (repo *discardAuditLogRepository) Create(entry *models.AuditLog) error {
+	return nil
+}
+
+func TestUpdateTaskAssignee(t *testing.T) {
+	taskBoardID, assigneeID := uuid.New(), uuid.New()
+	otherID := uuid.New()
+
+	tests := []struct {
+		name      string
+		request   func(*dto.UpdateTaskRequest)
+		wantError bool
+		want      *uuid.UUID
+	}{
+		{
+			name:    "assignee is kept when the request names none",
+			request: func(*dto.UpdateTaskRequest) {},
+			want:    &assigneeID,
+		},
+		{
+			name:    "unassign clears the assignee",
+			request: func(request *dto.UpdateTaskRequest) { request.Unassign = true },
+			want:    nil,
+		},
+		{
+			name: "unassign cannot be combined with an assignee",
+			request: func(request *dto.UpdateTaskRequest) {
+				request.Unassign = true
+				request.AssigneeID = &otherID
+			},
+			wantError: true,
+			want:      &assigneeID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := models.Task{
+				ID:          uuid.New(),
+				TaskBoardID: taskBoardID,
+				TaskBoard:   models.TaskBoard{ID: taskBoardID},
+				Title:       "Task",
+				Status:      "todo",
+				Priority:    "medium",
+				AssigneeID:  &assigneeID,
+			}
+			repo := &storedTaskRepository{task: task}
+			service := &TaskServiceImpl{taskRepo: repo, auditRepo: &discardAuditLogRepository{}}
+
+			request := &dto.UpdateTaskRequest{
+				TaskBoardID: taskBoardID,
+				Title:       task.Title,
+				Status:      task.Status,
+				Priority:    task.Priority,
+				StartDate:   time.Now(),
+				EndDate:     time.Now(),
+			}
+			test.request(request)
+
+			_, _, err := service.updateTask(task.ID, request, uuid.New(), models.RequestMeta{})
+			if test.wantError {
+				if _, ok := err.(*ValidationError); !ok {
+					t.Fatalf("updateTask error = %v, want a ValidationError", err)
+				}
+			} else if err != nil {
+				t.Fatalf("updateTask: %v", err)
+			}
+
+			got := repo.task.AssigneeID
+			if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+				t.Errorf("assignee = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUpdateTaskEstimate(t *testing.T) {
+	taskBoardID := uuid.New()
+	estimate, other := 90, 30
+
+	tests := []struct {
+		name      string
+		request   func(*dto.UpdateTaskRequest)
+		wantError bool
+		want      *int
+	}{
+		{
+			name:    "estimate is kept when the request names none",
+			request: func(*dto.UpdateTaskRequest) {},
+			want:    &estimate,
+		},
+		{
+			name:    "estimate is replaced",
+			request: func(request *dto.UpdateTaskRequest) { request.EstimateMinutes = &other },
+			want:    &other,
+		},
+		{
+			name:    "clear_estimate clears the estimate",
+			request: func(request *dto.UpdateTaskRequest) { request.ClearEstimate = true },
+			want:    nil,
+		},
+		{
+			name: "clear_estimate cannot be combined with an estimate",
+			request: func(request *dto.UpdateTaskRequest) {
+				request.ClearEstimate = true
+				request.EstimateMinutes = &other
+			},
+			wantError: true,
+			want:      &estimate,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := models.Task{
+				ID:              uuid.New(),
+				TaskBoardID:     taskBoardID,
+				TaskBoard:       models.TaskBoard{ID: taskBoardID},
+				Title:           "Task",
+				Status:          "todo",
+				Priority:        "medium",
+				EstimateMinutes: &estimate,
+			}
+			repo := &storedTaskRepository{task: task}
+			service := &TaskServiceImpl{taskRepo: repo, auditRepo: &discardAuditLogRepository{}}
+
+			request := &dto.UpdateTaskRequest{
+				TaskBoardID: taskBoardID,
+				Title:       task.Title,
+				Status:      task.Status,
+				Priority:    task.Priority,
+				StartDate:   time.Now(),
+				EndDate:     time.Now(),
+			}
+			test.request(request)
+
+			_, _, err := service.updateTask(task.ID, request, uuid.New(), models.RequestMeta{})
+			if test.wantError {
+				if _, ok := err.(*ValidationError); !ok {
+					t.Fatalf("updateTask error = %v, want a ValidationError", err)
+				}
+			} else if err != nil {
+				t.Fatalf("updateTask: %v", err)
+			}
+
+			got := repo.task.EstimateMinutes
+			if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+				t.Errorf("estimate = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
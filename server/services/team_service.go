@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TeamService interface {
+	GetTeams(workspaceID uuid.UUID, params *pagination.Params) ([]models.Team, *pagination.Page, error)
+	CreateTeam(workspaceID uuid.UUID, teamDTO *dto.TeamRequest) (*models.Team, error)
+	UpdateTeam(workspaceID uuid.UUID, teamID uuid.UUID, teamDTO *dto.TeamRequest) (*models.Team, error)
+	DeleteTeam(workspaceID uuid.UUID, teamID uuid.UUID) error
+	GetTeamMembers(workspaceID uuid.UUID, teamID uuid.UUID, params *pagination.Params) ([]models.TeamMember, *pagination.Page, error)
+	AddTeamMember(workspaceID uuid.UUID, teamID uuid.UUID, memberDTO *dto.TeamMemberRequest) (*models.TeamMember, error)
+	RemoveTeamMember(workspaceID uuid.UUID, teamID uuid.UUID, userID uuid.UUID) error
+	GetBoardTeams(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskBoardTeam, *pagination.Page, error)
+	GrantTeam(taskBoardID uuid.UUID, grantDTO *dto.BoardTeamRequest, actorID uuid.UUID) (*models.TaskBoardTeam, error)
+	UpdateTeamGrant(taskBoardID uuid.UUID, teamID uuid.UUID, roleDTO *dto.UpdateCollaboratorRequest, actorID uuid.UUID) (*models.TaskBoardTeam, error)
+	RevokeTeam(taskBoardID uuid.UUID, teamID uuid.UUID, actorID uuid.UUID) error
+}
+
+type TeamServiceImpl struct {
+	teamRepo      repositories.TeamRepository
+	workspaceRepo repositories.WorkspaceRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	boardRoleRepo repositories.BoardRoleRepository
+	logger        *zap.Logger
+}
+
+func NewTeamService(teamRepo repositories.TeamRepository, workspaceRepo repositories.WorkspaceRepository, taskBoardRepo repositories.TaskBoardRepository, boardRoleRepo repositories.BoardRoleRepository, logger *zap.Logger) *TeamServiceImpl {
+	return &TeamServiceImpl{
+		teamRepo:      teamRepo,
+		workspaceRepo: workspaceRepo,
+		taskBoardRepo: taskBoardRepo,
+		boardRoleRepo: boardRoleRepo,
+		logger:        logger,
+	}
+}
+
+func (service *TeamServiceImpl) GetTeams(workspaceID uuid.UUID, params *pagination.Params) ([]models.Team, *pagination.Page, error) {
+	return service.teamRepo.FindByWorkspaceID(workspaceID, params)
+}
+
+func (service *TeamServiceImpl) CreateTeam(workspaceID uuid.UUID, teamDTO *dto.TeamRequest) (*models.Team, error) {
+	team := &models.Team{WorkspaceID: workspaceID, Name: strings.TrimSpace(teamDTO.Name)}
+	if team.Name == "" {
+		return nil, &ValidationError{message: "team name is required"}
+	}
+
+	created, err := service.teamRepo.Create(team)
+	if err != nil {
+		service.logger.Error("Error creating team", zap.Error(err))
+		return nil, teamConflict(team.Name, err)
+	}
+	return created, nil
+}
+
+func (service *TeamServiceImpl) UpdateTeam(workspaceID uuid.UUID, teamID uuid.UUID, teamDTO *dto.TeamRequest) (*models.Team, error) {
+	team, err := service.findTeam(workspaceID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	team.Name = strings.TrimSpace(teamDTO.Name)
+	if team.Name == "" {
+		return nil, &ValidationError{message: "team name is required"}
+	}
+
+	updated, err := service.teamRepo.Update(team)
+	if err != nil {
+		return nil, teamConflict(team.Name, err)
+	}
+	return updated, nil
+}
+
+// DeleteTeam removes the team along with the access it granted on boards.
+func (service *TeamServiceImpl) DeleteTeam(workspaceID uuid.UUID, teamID uuid.UUID) error {
+	if _, err := service.findTeam(workspaceID, teamID); err != nil {
+		return err
+	}
+	return service.teamRepo.Delete(teamID)
+}
+
+func (service *TeamServiceImpl) GetTeamMembers(workspaceID uuid.UUID, teamID uuid.UUID, params *pagination.Params) ([]models.TeamMember, *pagination.Page, error) {
+	if _, err := service.findTeam(workspaceID, teamID); err != nil {
+		return nil, nil, err
+	}
+	return service.teamRepo.FindMembers(teamID, params)
+}
+
+// AddTeamMember adds a workspace member to the team. They reach the team's
+// boards straight away, since access is resolved on every request.
+func (service *TeamServiceImpl) AddTeamMember(workspaceID uuid.UUID, teamID uuid.UUID, memberDTO *dto.TeamMemberRequest) (*models.TeamMember, error) {
+	if _, err := service.findTeam(workspaceID, teamID); err != nil {
+		return nil, err
+	}
+	if _, err := service.workspaceRepo.FindMember(workspaceID, memberDTO.UserID); err != nil {
+		return nil, &ValidationError{message: "team members must belong to the workspace"}
+	}
+
+	member, err := service.teamRepo.AddMember(teamID, memberDTO.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+			return nil, &ConflictError{message: "user is already on this team"}
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (service *TeamServiceImpl) RemoveTeamMember(workspaceID uuid.UUID, teamID uuid.UUID, userID uuid.UUID) error {
+	if _, err := service.findTeam(workspaceID, teamID); err != nil {
+		return err
+	}
+	return service.teamRepo.RemoveMember(teamID, userID)
+}
+
+func (service *TeamServiceImpl) GetBoardTeams(taskBoardID uuid.UUID, params *pagination.Params) ([]models.TaskBoardTeam, *pagination.Page, error) {
+	return service.teamRepo.FindBoardGrants(taskBoardID, params)
+}
+
+// GrantTeam gives a team of the board's workspace a role on the board. As with
+// collaborators, only owners may grant the owner role.
+func (service *TeamServiceImpl) GrantTeam(taskBoardID uuid.UUID, grantDTO *dto.BoardTeamRequest, actorID uuid.UUID) (*models.TaskBoardTeam, error) {
+	taskBoard, err := service.taskBoardRepo.FindByID(taskBoardID)
+	if err != nil {
+		return nil, fmt.Errorf("task board not found")
+	}
+	team, err := service.teamRepo.FindByID(grantDTO.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("team not found")
+	}
+	if taskBoard.WorkspaceID == nil || *taskBoard.WorkspaceID != team.WorkspaceID {
+		return nil, &ValidationError{message: "only teams of the board's workspace can be granted access"}
+	}
+	if _, err := service.teamRepo.FindBoardGrant(taskBoardID, team.ID); err == nil {
+		return nil, &ConflictError{message: "team already has access to this task board"}
+	}
+
+	if grantDTO.Role == policy.RoleOwner && subjectOn(service.taskBoardRepo, taskBoardID, actorID).Role != policy.RoleOwner {
+		return nil, &ForbiddenError{message: "only board owners can grant the owner role"}
+	}
+	boardRoleID, err := customRoleID(service.boardRoleRepo, taskBoardID, grantDTO.Role, grantDTO.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.teamRepo.GrantBoard(&models.TaskBoardTeam{
+		TaskBoardID: taskBoardID,
+		TeamID:      team.ID,
+		Role:        grantDTO.Role,
+		BoardRoleID: boardRoleID,
+	})
+}
+
+func (service *TeamServiceImpl) UpdateTeamGrant(taskBoardID uuid.UUID, teamID uuid.UUID, roleDTO *dto.UpdateCollaboratorRequest, actorID uuid.UUID) (*models.TaskBoardTeam, error) {
+	grant, err := service.teamRepo.FindBoardGrant(taskBoardID, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("team not found")
+	}
+	if (roleDTO.Role == policy.RoleOwner || grant.Role == policy.RoleOwner) && subjectOn(service.taskBoardRepo, taskBoardID, actorID).Role != policy.RoleOwner {
+		return nil, &ForbiddenError{message: "only board owners can grant or take away the owner role"}
+	}
+	boardRoleID, err := customRoleID(service.boardRoleRepo, taskBoardID, roleDTO.Role, roleDTO.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.teamRepo.UpdateBoardGrant(taskBoardID, teamID, roleDTO.Role, boardRoleID); err != nil {
+		return nil, err
+	}
+	return service.teamRepo.FindBoardGrant(taskBoardID, teamID)
+}
+
+// RevokeTeam takes the team's grant off the board. Members keep any access
+// they hold directly or through other teams.
+func (service *TeamServiceImpl) RevokeTeam(taskBoardID uuid.UUID, teamID uuid.UUID, actorID uuid.UUID) error {
+	grant, err := service.teamRepo.FindBoardGrant(taskBoardID, teamID)
+	if err != nil {
+		return fmt.Errorf("team not found")
+	}
+	if grant.Role == policy.RoleOwner && subjectOn(service.taskBoardRepo, taskBoardID, actorID).Role != policy.RoleOwner {
+		return &ForbiddenError{message: "only board owners can take away the owner role"}
+	}
+	return service.teamRepo.RevokeBoard(taskBoardID, teamID)
+}
+
+func (service *TeamServiceImpl) findTeam(workspaceID uuid.UUID, teamID uuid.UUID) (*models.Team, error) {
+	team, err := service.teamRepo.FindByID(teamID)
+	if err != nil || team.WorkspaceID != workspaceID {
+		return nil, fmt.Errorf("team not found")
+	}
+	return team, nil
+}
+
+func teamConflict(name string, err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+		return &ConflictError{message: fmt.Sprintf("team %q already exists in this workspace", name)}
+	}
+	return err
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type TimeTrackingService interface {
+	LogTime(taskID uuid.UUID, actorID uuid.UUID, logDTO *dto.LogTimeRequest) (*models.TimeEntry, error)
+	GetTaskEntries(taskID uuid.UUID, params *pagination.Params) ([]models.TimeEntry, *pagination.Page, error)
+	DeleteEntry(entryID uuid.UUID, actorID uuid.UUID) error
+	StartTimer(taskID uuid.UUID, actorID uuid.UUID, timerDTO *dto.StartTimerRequest) (*models.TimeEntry, error)
+	StopTimer(actorID uuid.UUID) (*models.TimeEntry, error)
+	GetRunningTimer(actorID uuid.UUID) (*models.TimeEntry, error)
+	Summarize(filter repositories.TimeEntryFilter, query *dto.TimeReportQuery) (*models.TimeSummary, error)
+	ExportEntries(filter repositories.TimeEntryFilter, query *dto.TimeReportQuery) ([]models.TimeEntry, error)
+}
+
+type TimeTrackingServiceImpl struct {
+	timeEntryRepo repositories.TimeEntryRepository
+	taskRepo      repositories.TaskRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	engine        *policy.Engine
+	logger        *zap.Logger
+}
+
+func NewTimeTrackingService(
+	timeEntryRepo repositories.TimeEntryRepository,
+	taskRepo repositories.TaskRepository,
+	taskBoardRepo repositories.TaskBoardRepository,
+	engine *policy.Engine,
+	logger *zap.Logger,
+) *TimeTrackingServiceImpl {
+	return &TimeTrackingServiceImpl{
+		timeEntryRepo: timeEntryRepo,
+		taskRepo:      taskRepo,
+		taskBoardRepo: taskBoardRepo,
+		engine:        engine,
+		logger:        logger,
+	}
+}
+
+func (service *TimeTrackingServiceImpl) LogTime(taskID uuid.UUID, actorID uuid.UUID, logDTO *dto.LogTimeRequest) (*models.TimeEntry, error) {
+	task, err := service.editableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", logDTO.Date)
+	if err != nil {
+		return nil, &ValidationError{message: "date must look like 2006-01-02"}
+	}
+
+	return service.timeEntryRepo.Create(&models.TimeEntry{
+		TaskID:          task.ID,
+		TaskBoardID:     task.TaskBoardID,
+		UserID:          actorID,
+		Date:            date,
+		DurationSeconds: int64(logDTO.DurationMinutes) * 60,
+		Note:            strings.TrimSpace(logDTO.Note),
+	})
+}
+
+func (service *TimeTrackingServiceImpl) GetTaskEntries(taskID uuid.UUID, params *pagination.Params) ([]models.TimeEntry, *pagination.Page, error) {
+	return service.timeEntryRepo.FindByTaskID(taskID, params)
+}
+
+// DeleteEntry is authorized as time.delete on the entry, which belongs to its
+// author: by default users remove their own entries and owners anyone's.
+func (service *TimeTrackingServiceImpl) DeleteEntry(entryID uuid.UUID, actorID uuid.UUID) error {
+	entry, err := service.timeEntryRepo.FindByID(entryID)
+	if err != nil {
+		return fmt.Errorf("time entry not found")
+	}
+
+	resource := policy.Resource{TaskBoardID: entry.TaskBoardID, OwnerID: &entry.UserID}
+	if err := authorize(service.engine, service.taskBoardRepo, actorID, policy.TimeDelete, resource); err != nil {
+		return err
+	}
+
+	return service.timeEntryRepo.Delete(entryID)
+}
+
+func (service *TimeTrackingServiceImpl) StartTimer(taskID uuid.UUID, actorID uuid.UUID, timerDTO *dto.StartTimerRequest) (*models.TimeEntry, error) {
+	task, err := service.editableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := service.timeEntryRepo.FindRunningByUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return nil, &ConflictError{message: "a timer is already running; stop it first"}
+	}
+
+	now := time.Now().UTC()
+	entry, err := service.timeEntryRepo.Create(&models.TimeEntry{
+		TaskID:      task.ID,
+		TaskBoardID: task.TaskBoardID,
+		UserID:      actorID,
+		Date:        now.Truncate(24 * time.Hour),
+		StartedAt:   &now,
+		Note:        strings.TrimSpace(timerDTO.Note),
+	})
+	if err != nil {
+		// The partial unique index rejects a second timer started concurrently.
+		if strings.Contains(err.Error(), "idx_time_entries_running") {
+			return nil, &ConflictError{message: "a timer is already running; stop it first"}
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (service *TimeTrackingServiceImpl) StopTimer(actorID uuid.UUID) (*models.TimeEntry, error) {
+	entry, err := service.timeEntryRepo.FindRunningByUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("no timer is running")
+	}
+
+	now := time.Now().UTC()
+	entry.EndedAt = &now
+	entry.DurationSeconds = int64(now.Sub(*entry.StartedAt).Seconds())
+
+	return service.timeEntryRepo.Update(entry)
+}
+
+func (service *TimeTrackingServiceImpl) GetRunningTimer(actorID uuid.UUID) (*models.TimeEntry, error) {
+	entry, err := service.timeEntryRepo.FindRunningByUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("no timer is running")
+	}
+	return entry, nil
+}
+
+func (service *TimeTrackingServiceImpl) Summarize(filter repositories.TimeEntryFilter, query *dto.TimeReportQuery) (*models.TimeSummary, error) {
+	if err := applyReportRange(&filter, query); err != nil {
+		return nil, err
+	}
+
+	groupBy := query.GroupBy
+	if groupBy == "" {
+		groupBy = "date"
+	}
+
+	groups, err := service.timeEntryRepo.Summarize(filter, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.TimeSummary{GroupBy: groupBy, Groups: groups}
+	for _, group := range groups {
+		summary.TotalSeconds += group.Seconds
+	}
+
+	if filter.TaskID != nil {
+		if task, err := service.taskRepo.FindByID(*filter.TaskID); err == nil {
+			summary.EstimateMinutes = task.EstimateMinutes
+		}
+	}
+
+	return summary, nil
+}
+
+func (service *TimeTrackingServiceImpl) ExportEntries(filter repositories.TimeEntryFilter, query *dto.TimeReportQuery) ([]models.TimeEntry, error) {
+	if err := applyReportRange(&filter, query); err != nil {
+		return nil, err
+	}
+	return service.timeEntryRepo.FindEntries(filter)
+}
+
+// editableTask loads a task the actor may log time on, which takes time.log
+// on the task.
+func (service *TimeTrackingServiceImpl) editableTask(taskID uuid.UUID, actorID uuid.UUID) (*models.Task, error) {
+	task, err := service.taskRepo.FindByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	if err := authorize(service.engine, service.taskBoardRepo, actorID, policy.TimeLog, taskResource(task)); err != nil {
+		return nil, err
+	}
+	if err := checkTaskWritable(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func applyReportRange(filter *repositories.TimeEntryFilter, query *dto.TimeReportQuery) error {
+	if query.From != "" {
+		from, err := time.Parse("2006-01-02", query.From)
+		if err != nil {
+			return &ValidationError{message: "from must look like 2006-01-02"}
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, err := time.Parse("2006-01-02", query.To)
+		if err != nil {
+			return &ValidationError{message: "to must look like 2006-01-02"}
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return &ValidationError{message: "to must not be before from"}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"server/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type TrashService interface {
+	PurgeExpired(now time.Time) (int64, error)
+}
+
+type TrashServiceImpl struct {
+	taskBoardRepo repositories.TaskBoardRepository
+	taskRepo      repositories.TaskRepository
+	retention     time.Duration
+	logger        *zap.Logger
+}
+
+func NewTrashService(
+	taskBoardRepo repositories.TaskBoardRepository,
+	taskRepo repositories.TaskRepository,
+	retention time.Duration,
+	logger *zap.Logger,
+) *TrashServiceImpl {
+	return &TrashServiceImpl{
+		taskBoardRepo: taskBoardRepo,
+		taskRepo:      taskRepo,
+		retention:     retention,
+		logger:        logger,
+	}
+}
+
+// PurgeExpired permanently removes boards and tasks that have been in the
+// trash for longer than the retention period, returning how many rows went.
+func (service *TrashServiceImpl) PurgeExpired(now time.Time) (int64, error) {
+	cutoff := now.Add(-service.retention)
+
+	boards, err := service.taskBoardRepo.PurgeDeleted(cutoff)
+	if err != nil {
+		return 0, err
+	}
+	tasks, err := service.taskRepo.PurgeDeleted(cutoff)
+	if err != nil {
+		return boards, err
+	}
+
+	if boards > 0 || tasks > 0 {
+		service.logger.Info("Purged trash",
+			zap.Int64("taskBoards", boards),
+			zap.Int64("tasks", tasks),
+			zap.Time("deletedBefore", cutoff),
+		)
+	}
+	return boards + tasks, nil
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"server/dto"
 	"server/models"
+	"server/pagination"
 	"server/repositories"
 	"server/utils"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type ConflictError struct {
@@ -19,24 +21,31 @@ func (e *ConflictError) Error() string {
 }
 
 type UserService interface {
-	CreateUser(userDTO *dto.CreateUserRequest) (*models.User, error)
-	FindAllUsers() ([]models.User, error)
+	CreateUser(userDTO *dto.CreateUserRequest, meta models.RequestMeta) (*models.User, error)
+	FindAllUsers(params *pagination.Params) ([]models.User, *pagination.Page, error)
 }
 
 type UserServiceImpl struct {
-	userRepo repositories.UserRepository
-	logger   *zap.Logger
+	userRepo       repositories.UserRepository
+	invitationRepo repositories.InvitationRepository
+	taskBoardRepo  repositories.TaskBoardRepository
+	auditRepo      repositories.AuditLogRepository
+	transactor     repositories.Transactor
+	logger         *zap.Logger
 }
 
-func NewUserService(userRepo repositories.UserRepository, logger *zap.Logger) UserService {
+func NewUserService(userRepo repositories.UserRepository, invitationRepo repositories.InvitationRepository, taskBoardRepo repositories.TaskBoardRepository, auditRepo repositories.AuditLogRepository, transactor repositories.Transactor, logger *zap.Logger) UserService {
 	return &UserServiceImpl{
-		userRepo: userRepo,
-		
-		logger:   logger,
+		userRepo:       userRepo,
+		invitationRepo: invitationRepo,
+		taskBoardRepo:  taskBoardRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+		logger:         logger,
 	}
 }
 
-func (service *UserServiceImpl) CreateUser(userDTO *dto.CreateUserRequest) (*models.User, error) {	
+func (service *UserServiceImpl) CreateUser(userDTO *dto.CreateUserRequest, meta models.RequestMeta) (*models.User, error) {	
 	// ==== Validate email format ====
 	existingUser, err := service.userRepo.FindByEmail(userDTO.Email)
 	if err != nil {
@@ -60,20 +69,70 @@ func (service *UserServiceImpl) CreateUser(userDTO *dto.CreateUserRequest) (*mod
 		Password: hashedPassword,
 	}
 
-	if err := service.userRepo.Create(&user); err != nil {
-		service.logger.Error("Failed to create user", zap.Error(err))
-		return nil, fmt.Errorf("failed to create user")
+	if userDTO.InvitationToken == "" {
+		err := service.transactor.Transaction(func(tx *gorm.DB) error {
+			return service.createUser(tx, &user, meta)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+
+	// ==== Join the invited board ====
+	invitation, err := service.invitationRepo.FindByTokenHash(utils.HashLinkToken(userDTO.InvitationToken))
+	if err != nil {
+		return nil, &ValidationError{message: "invitation not found"}
+	}
+	err = service.transactor.Transaction(func(tx *gorm.DB) error {
+		if err := service.createUser(tx, &user, meta); err != nil {
+			return err
+		}
+		collaborator, err := acceptInvitation(service.invitationRepo.WithTx(tx), service.taskBoardRepo.WithTx(tx), invitation, &user)
+		if err != nil {
+			return err
+		}
+		return writeAudit(service.auditRepo.WithTx(tx), meta, auditEntry{
+			action:      models.AuditMemberAdd,
+			actorID:     &user.ID,
+			entityType:  models.AuditEntityCollaborator,
+			entityID:    &user.ID,
+			taskBoardID: &collaborator.TaskBoardID,
+			after:       collaboratorAuditFields(collaborator.Role, collaborator.BoardRoleID),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	service.logger.Info("User registered through invitation",
+		zap.String("userID", user.ID.String()),
+		zap.String("taskBoardID", invitation.TaskBoardID.String()))
 	return &user, nil
 }
 
-func (service *UserServiceImpl) FindAllUsers() ([]models.User, error) {
-	users, err := service.userRepo.FindAll()
+// createUser inserts the user and audits the sign-up, as done by the user
+// themselves, inside tx.
+func (service *UserServiceImpl) createUser(tx *gorm.DB, user *models.User, meta models.RequestMeta) error {
+	if err := service.userRepo.WithTx(tx).Create(user); err != nil {
+		service.logger.Error("Failed to create user", zap.Error(err))
+		return fmt.Errorf("failed to create user")
+	}
+	return writeAudit(service.auditRepo.WithTx(tx), meta, auditEntry{
+		action:     models.AuditUserCreate,
+		actorID:    &user.ID,
+		entityType: models.AuditEntityUser,
+		entityID:   &user.ID,
+		after:      userAuditFields(user),
+	})
+}
+
+func (service *UserServiceImpl) FindAllUsers(params *pagination.Params) ([]models.User, *pagination.Page, error) {
+	users, page, err := service.userRepo.FindAll(params)
 	if err != nil {
 		service.logger.Error("Error fetching users", zap.Error(err))
-		return nil, fmt.Errorf("error fetching users")
+		return nil, nil, fmt.Errorf("error fetching users")
 	}
 
-	return users, nil
+	return users, page, nil
 }
\ No newline at end of file
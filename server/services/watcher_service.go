@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"server/models"
+	"server/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type WatcherService interface {
+	GetTaskWatchers(taskID uuid.UUID) ([]models.TaskWatcher, error)
+	WatchTask(taskID uuid.UUID, userID uuid.UUID) error
+	UnwatchTask(taskID uuid.UUID, userID uuid.UUID) error
+	IsWatchingTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) (bool, error)
+	WatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error
+	UnwatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error
+}
+
+type WatcherServiceImpl struct {
+	watcherRepo repositories.WatcherRepository
+	taskRepo    repositories.TaskRepository
+	logger      *zap.Logger
+}
+
+func NewWatcherService(watcherRepo repositories.WatcherRepository, taskRepo repositories.TaskRepository, logger *zap.Logger) *WatcherServiceImpl {
+	return &WatcherServiceImpl{
+		watcherRepo: watcherRepo,
+		taskRepo:    taskRepo,
+		logger:      logger,
+	}
+}
+
+func (service *WatcherServiceImpl) GetTaskWatchers(taskID uuid.UUID) ([]models.TaskWatcher, error) {
+	return service.watcherRepo.FindTaskWatchers(taskID)
+}
+
+// WatchTask makes the user watch the task, undoing an earlier opt-out.
+func (service *WatcherServiceImpl) WatchTask(taskID uuid.UUID, userID uuid.UUID) error {
+	if _, err := service.taskRepo.FindByID(taskID); err != nil {
+		return fmt.Errorf("task not found")
+	}
+	return service.watcherRepo.SetTaskWatching(taskID, userID, true)
+}
+
+// UnwatchTask opts the user out of the task: they stop watching it, even
+// through its board, and creating, being assigned or commenting on it no
+// longer makes them watch it.
+func (service *WatcherServiceImpl) UnwatchTask(taskID uuid.UUID, userID uuid.UUID) error {
+	if _, err := service.taskRepo.FindByID(taskID); err != nil {
+		return fmt.Errorf("task not found")
+	}
+	return service.watcherRepo.SetTaskWatching(taskID, userID, false)
+}
+
+func (service *WatcherServiceImpl) IsWatchingTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) (bool, error) {
+	return service.watcherRepo.IsWatchingTaskBoard(taskBoardID, userID)
+}
+
+// WatchTaskBoard makes the user watch every task on the board, except those
+// they opted out of.
+func (service *WatcherServiceImpl) WatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error {
+	return service.watcherRepo.WatchTaskBoard(taskBoardID, userID)
+}
+
+func (service *WatcherServiceImpl) UnwatchTaskBoard(taskBoardID uuid.UUID, userID uuid.UUID) error {
+	return service.watcherRepo.UnwatchTaskBoard(taskBoardID, userID)
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/dto"
+	"server/models"
+	"server/pagination"
+	"server/policy"
+	"server/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type WorkspaceService interface {
+	CreateWorkspace(workspaceDTO *dto.WorkspaceRequest, actorID uuid.UUID) (*models.Workspace, error)
+	GetWorkspaces(userID uuid.UUID, params *pagination.Params) ([]models.Workspace, *pagination.Page, error)
+	GetWorkspace(workspaceID uuid.UUID, userID uuid.UUID) (*models.Workspace, error)
+	UpdateWorkspace(workspaceID uuid.UUID, workspaceDTO *dto.WorkspaceRequest) (*models.Workspace, error)
+	DeleteWorkspace(workspaceID uuid.UUID) error
+	FindMember(workspaceID uuid.UUID, userID uuid.UUID) (*models.WorkspaceMember, error)
+	GetMembers(workspaceID uuid.UUID, params *pagination.Params) ([]models.WorkspaceMember, *pagination.Page, error)
+	AddMember(workspaceID uuid.UUID, memberDTO *dto.WorkspaceMemberRequest) (*models.WorkspaceMember, error)
+	UpdateMemberRole(workspaceID uuid.UUID, userID uuid.UUID, roleDTO *dto.UpdateWorkspaceMemberRequest) (*models.WorkspaceMember, error)
+	RemoveMember(workspaceID uuid.UUID, userID uuid.UUID, actorID uuid.UUID) error
+	GetBoards(workspaceID uuid.UUID, userID uuid.UUID, options repositories.ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error)
+}
+
+type WorkspaceServiceImpl struct {
+	workspaceRepo repositories.WorkspaceRepository
+	taskBoardRepo repositories.TaskBoardRepository
+	userRepo      repositories.UserRepository
+	transactor    repositories.Transactor
+	logger        *zap.Logger
+}
+
+func NewWorkspaceService(workspaceRepo repositories.WorkspaceRepository, taskBoardRepo repositories.TaskBoardRepository, userRepo repositories.UserRepository, transactor repositories.Transactor, logger *zap.Logger) *WorkspaceServiceImpl {
+	return &WorkspaceServiceImpl{
+		workspaceRepo: workspaceRepo,
+		taskBoardRepo: taskBoardRepo,
+		userRepo:      userRepo,
+		transactor:    transactor,
+		logger:        logger,
+	}
+}
+
+// CreateWorkspace makes the actor the new workspace's first admin.
+func (service *WorkspaceServiceImpl) CreateWorkspace(workspaceDTO *dto.WorkspaceRequest, actorID uuid.UUID) (*models.Workspace, error) {
+	workspace := &models.Workspace{Name: workspaceDTO.Name}
+	applyWorkspaceSettings(workspace, workspaceDTO)
+
+	workspace, err := service.workspaceRepo.Create(workspace, actorID)
+	if err != nil {
+		service.logger.Error("Error creating workspace", zap.Error(err))
+		return nil, err
+	}
+	service.logger.Info("Workspace created", zap.String("workspaceID", workspace.ID.String()))
+	return workspace, nil
+}
+
+// GetWorkspaces lists the user's workspaces, personal one first, creating
+// the personal workspace if they do not have one yet.
+func (service *WorkspaceServiceImpl) GetWorkspaces(userID uuid.UUID, params *pagination.Params) ([]models.Workspace, *pagination.Page, error) {
+	if _, err := personalWorkspace(service.workspaceRepo, userID); err != nil {
+		return nil, nil, err
+	}
+	return service.workspaceRepo.FindByUserID(userID, params)
+}
+
+func (service *WorkspaceServiceImpl) GetWorkspace(workspaceID uuid.UUID, userID uuid.UUID) (*models.Workspace, error) {
+	workspace, err := service.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("workspace not found")
+	}
+	if member, err := service.workspaceRepo.FindMember(workspaceID, userID); err == nil {
+		workspace.Role = member.Role
+	}
+	return workspace, nil
+}
+
+func (service *WorkspaceServiceImpl) UpdateWorkspace(workspaceID uuid.UUID, workspaceDTO *dto.WorkspaceRequest) (*models.Workspace, error) {
+	workspace, err := service.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("workspace not found")
+	}
+	workspace.Name = workspaceDTO.Name
+	applyWorkspaceSettings(workspace, workspaceDTO)
+
+	return service.workspaceRepo.Update(workspace)
+}
+
+// DeleteWorkspace removes an empty workspace. Personal workspaces stay, and
+// boards, trashed ones included, have to be deleted or purged first.
+func (service *WorkspaceServiceImpl) DeleteWorkspace(workspaceID uuid.UUID) error {
+	workspace, err := service.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		return fmt.Errorf("workspace not found")
+	}
+	if workspace.PersonalOwnerID != nil {
+		return &ConflictError{message: "personal workspaces cannot be deleted"}
+	}
+
+	boards, err := service.workspaceRepo.CountBoards(workspaceID)
+	if err != nil {
+		return err
+	}
+	if boards > 0 {
+		return &ConflictError{message: fmt.Sprintf("the workspace still has %d task boards", boards)}
+	}
+	return service.workspaceRepo.Delete(workspaceID)
+}
+
+func (service *WorkspaceServiceImpl) FindMember(workspaceID uuid.UUID, userID uuid.UUID) (*models.WorkspaceMember, error) {
+	return service.workspaceRepo.FindMember(workspaceID, userID)
+}
+
+func (service *WorkspaceServiceImpl) GetMembers(workspaceID uuid.UUID, params *pagination.Params) ([]models.WorkspaceMember, *pagination.Page, error) {
+	return service.workspaceRepo.FindMembers(workspaceID, params)
+}
+
+// AddMember adds a registered user to the workspace.
+func (service *WorkspaceServiceImpl) AddMember(workspaceID uuid.UUID, memberDTO *dto.WorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	user, err := service.userRepo.FindByEmail(memberDTO.Email)
+	if err != nil {
+		return nil, fmt.Errorf("error finding user: %v", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if _, err := service.workspaceRepo.FindMember(workspaceID, user.ID); err == nil {
+		return nil, &ConflictError{message: "user is already a member of this workspace"}
+	}
+
+	return service.workspaceRepo.AddMember(&models.WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		Role:        memberDTO.Role,
+	})
+}
+
+func (service *WorkspaceServiceImpl) UpdateMemberRole(workspaceID uuid.UUID, userID uuid.UUID, roleDTO *dto.UpdateWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	err := service.changeMembers(workspaceID, func(repo repositories.WorkspaceRepository, roles map[uuid.UUID]string) error {
+		if _, ok := roles[userID]; !ok {
+			return fmt.Errorf("member not found")
+		}
+		return repo.UpdateMemberRole(workspaceID, userID, roleDTO.Role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service.workspaceRepo.FindMember(workspaceID, userID)
+}
+
+// RemoveMember takes a user out of the workspace. Admins may remove anyone
+// and every member may leave. Their memberships on individual boards stay.
+func (service *WorkspaceServiceImpl) RemoveMember(workspaceID uuid.UUID, userID uuid.UUID, actorID uuid.UUID) error {
+	return service.changeMembers(workspaceID, func(repo repositories.WorkspaceRepository, roles map[uuid.UUID]string) error {
+		if actorID != userID && roles[actorID] != policy.WorkspaceAdmin {
+			return &ForbiddenError{message: "only workspace admins can remove other members"}
+		}
+		if _, ok := roles[userID]; !ok {
+			return fmt.Errorf("member not found")
+		}
+		return repo.RemoveMember(workspaceID, userID)
+	})
+}
+
+// GetBoards lists the workspace's boards the user can open: every board for
+// admins and, unless members get no default role, for members; only the
+// boards they were added to for guests.
+func (service *WorkspaceServiceImpl) GetBoards(workspaceID uuid.UUID, userID uuid.UUID, options repositories.ListOptions, params *pagination.Params) ([]models.TaskBoard, *pagination.Page, error) {
+	return service.taskBoardRepo.FindByWorkspaceID(workspaceID, userID, options, params)
+}
+
+// changeMembers runs change against the workspace's locked memberships, given
+// as a map of user ID to role, and rolls it back if the workspace would be
+// left without an admin.
+func (service *WorkspaceServiceImpl) changeMembers(workspaceID uuid.UUID, change func(repo repositories.WorkspaceRepository, roles map[uuid.UUID]string) error) error {
+	return service.transactor.Transaction(func(tx *gorm.DB) error {
+		repo := service.workspaceRepo.WithTx(tx)
+
+		members, err := repo.LockMembers(workspaceID)
+		if err != nil {
+			return err
+		}
+		roles := make(map[uuid.UUID]string, len(members))
+		for _, member := range members {
+			roles[member.UserID] = member.Role
+		}
+
+		if err := change(repo, roles); err != nil {
+			return err
+		}
+
+		admins, err := repo.CountAdmins(workspaceID)
+		if err != nil {
+			return err
+		}
+		if admins == 0 {
+			return &ConflictError{message: "a workspace must keep at least one admin"}
+		}
+		return nil
+	})
+}
+
+func applyWorkspaceSettings(workspace *models.Workspace, workspaceDTO *dto.WorkspaceRequest) {
+	if workspaceDTO.DefaultBoardRole != "" {
+		workspace.Settings.DefaultBoardRole = workspaceDTO.DefaultBoardRole
+	}
+	if workspaceDTO.RestrictBoardCreation != nil {
+		workspace.Settings.RestrictBoardCreation = *workspaceDTO.RestrictBoardCreation
+	}
+}
+
+// personalWorkspace returns the user's personal workspace, creating it on
+// first use.
+func personalWorkspace(workspaceRepo repositories.WorkspaceRepository, userID uuid.UUID) (*models.Workspace, error) {
+	workspace, err := workspaceRepo.FindPersonal(userID)
+	if err == nil {
+		return workspace, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	workspace, err = workspaceRepo.Create(&models.Workspace{Name: "Personal", PersonalOwnerID: &userID}, userID)
+	if err != nil {
+		// A concurrent request may have created it first.
+		if existing, findErr := workspaceRepo.FindPersonal(userID); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// boardWorkspace picks the workspace a board the actor creates goes into: the
+// one asked for, where admins and, unless creation is restricted, members may
+// add boards; or else the actor's personal workspace.
+func boardWorkspace(workspaceRepo repositories.WorkspaceRepository, workspaceID *uuid.UUID, actorID uuid.UUID) (uuid.UUID, error) {
+	if workspaceID == nil {
+		workspace, err := personalWorkspace(workspaceRepo, actorID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return workspace.ID, nil
+	}
+
+	workspace, err := workspaceRepo.FindByID(*workspaceID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("workspace not found")
+	}
+	member, err := workspaceRepo.FindMember(workspace.ID, actorID)
+	if err != nil {
+		return uuid.Nil, &ForbiddenError{message: "you are not a member of this workspace"}
+	}
+	switch member.Role {
+	case policy.WorkspaceAdmin:
+	case policy.WorkspaceMember:
+		if workspace.Settings.RestrictBoardCreation {
+			return uuid.Nil, &ForbiddenError{message: "only workspace admins can create task boards here"}
+		}
+	default:
+		return uuid.Nil, &ForbiddenError{message: "workspace guests cannot create task boards"}
+	}
+	return workspace.ID, nil
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewLinkToken returns a random URL-safe token for an invitation or share
+// link.
+func NewLinkToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashLinkToken returns the hex SHA-256 of a token, which is what gets
+// stored and looked up.
+func HashLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// InviteLink returns the client page that accepts an invitation.
+func InviteLink(token string) string {
+	return clientURL() + "/invitations/" + token
+}
+
+// ShareLink returns the client page showing a board shared publicly.
+func ShareLink(token string) string {
+	return clientURL() + "/share/" + token
+}
+
+// clientURL is CLIENT_URL, default http://localhost:3000.
+func clientURL() string {
+	base := os.Getenv("CLIENT_URL")
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	return strings.TrimRight(base, "/")
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Mailer sends plain-text email.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// NewMailerFromEnv returns an SMTP mailer configured by SMTP_HOST, SMTP_PORT,
+// SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM, or a mailer that only logs
+// messages when SMTP_HOST is not set.
+func NewMailerFromEnv() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return &LogMailer{}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	return &SMTPMailer{
+		addr:     host + ":" + port,
+		host:     host,
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	message, err := buildMessage(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if err := smtp.SendMail(m.addr, auth, m.from, []string{to}, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// subjectLineBreaks turns line breaks in a subject into spaces.
+var subjectLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// buildMessage writes a plain-text email. Subjects carry user input, such as
+// an inviter's name, so line breaks in them become spaces and anything not
+// plain ASCII is encoded as in RFC 2047; addresses with line breaks are
+// rejected. No header can then be smuggled into the message.
+func buildMessage(from, to, subject, body string) ([]byte, error) {
+	for _, address := range []string{from, to} {
+		if strings.ContainsAny(address, "\r\n") {
+			return nil, fmt.Errorf("invalid email address %q", address)
+		}
+	}
+
+	message := strings.Join([]string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subjectLineBreaks.Replace(subject)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+	return []byte(message), nil
+}
+
+// LogMailer writes messages to the log instead of sending them, for local development.
+type LogMailer struct{}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	log.Printf("Email to %s: %s\n%s", to, subject, body)
+	return nil
+}
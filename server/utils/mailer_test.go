@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/mail"
+	"testing"
+)
+
+func TestBuildMessageHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		subject     string
+		wantSubject string
+	}{
+		{"plain", `Ana invited you to "Roadmap"`, `Ana invited you to "Roadmap"`},
+		{"CRLF injection", "Ana\r\nBcc: victim@example.com", "Ana Bcc: victim@example.com"},
+		{"bare LF injection", "Ana\nBcc: victim@example.com\n\nforged body", "Ana Bcc: victim@example.com  forged body"},
+		{"bare CR", "Ana\rBcc: victim@example.com", "Ana Bcc: victim@example.com"},
+		{"non-ASCII", "Zoë invited you to “Roadmap”", "Zoë invited you to “Roadmap”"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := buildMessage("noreply@example.com", "ana@example.com", test.subject, "Hello")
+			if err != nil {
+				t.Fatalf("buildMessage: %v", err)
+			}
+			message, err := mail.ReadMessage(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("ReadMessage: %v", err)
+			}
+
+			for key := range message.Header {
+				switch key {
+				case "From", "To", "Subject", "Mime-Version", "Content-Type":
+				default:
+					t.Errorf("unexpected header %s: %q", key, message.Header.Get(key))
+				}
+			}
+
+			subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+			if err != nil {
+				t.Fatalf("decode subject: %v", err)
+			}
+			if subject != test.wantSubject {
+				t.Errorf("Subject = %q, want %q", subject, test.wantSubject)
+			}
+
+			body, err := io.ReadAll(message.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if string(body) != "Hello" {
+				t.Errorf("body = %q, want %q", body, "Hello")
+			}
+		})
+	}
+}
+
+func TestBuildMessageRejectsAddressesWithLineBreaks(t *testing.T) {
+	addresses := []struct{ from, to string }{
+		{"noreply@example.com", "ana@example.com\r\nBcc: victim@example.com"},
+		{"noreply@example.com", "ana@example.com\nBcc: victim@example.com"},
+		{"noreply@example.com\r\nBcc: victim@example.com", "ana@example.com"},
+	}
+	for _, address := range addresses {
+		if _, err := buildMessage(address.from, address.to, "Subject", "Body"); err == nil {
+			t.Errorf("buildMessage(%q, %q) succeeded, want an error", address.from, address.to)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceRule is the subset of RFC 5545 RRULE supported for recurring tasks:
+// FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY (weekly only), UNTIL and COUNT.
+type RecurrenceRule struct {
+	Frequency string
+	Interval  int
+	ByWeekday []time.Weekday
+	Until     *time.Time
+	Count     int
+}
+
+// ParseRecurrenceRule parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("recurrence rule is empty")
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate recurrence rule part %s", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			if val != FrequencyDaily && val != FrequencyWeekly && val != FrequencyMonthly {
+				return nil, fmt.Errorf("unsupported recurrence frequency %s", val)
+			}
+			rule.Frequency = val
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 || interval > 365 {
+				return nil, fmt.Errorf("recurrence interval must be between 1 and 365")
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				weekday, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("invalid recurrence weekday %s", code)
+				}
+				if !containsWeekday(rule.ByWeekday, weekday) {
+					rule.ByWeekday = append(rule.ByWeekday, weekday)
+				}
+			}
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("recurrence count must be a positive number")
+			}
+			rule.Count = count
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %s", key)
+		}
+	}
+
+	if rule.Frequency == "" {
+		return nil, fmt.Errorf("recurrence rule needs FREQ")
+	}
+	if rule.Until != nil && rule.Count > 0 {
+		return nil, fmt.Errorf("recurrence rule cannot have both UNTIL and COUNT")
+	}
+	if len(rule.ByWeekday) > 0 && rule.Frequency != FrequencyWeekly {
+		return nil, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("recurrence UNTIL must look like 20060102 or 20060102T150405Z")
+}
+
+// String renders the rule in canonical RRULE form.
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Frequency}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByWeekday) > 0 {
+		codes := make([]string, 0, len(r.ByWeekday))
+		for _, code := range []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"} {
+			if containsWeekday(r.ByWeekday, weekdayCodes[code]) {
+				codes = append(codes, code)
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence following previous. start is the first
+// occurrence of the series; monthly rules keep its day of the month and skip
+// months that are too short for it, as RRULE does.
+func (r *RecurrenceRule) Next(previous, start time.Time) time.Time {
+	switch r.Frequency {
+	case FrequencyWeekly:
+		if len(r.ByWeekday) == 0 {
+			return previous.AddDate(0, 0, 7*r.Interval)
+		}
+		offset := mondayOffset(previous)
+		weekStart := previous.AddDate(0, 0, -offset)
+		for day := offset + 1; day < 7; day++ {
+			candidate := weekStart.AddDate(0, 0, day)
+			if containsWeekday(r.ByWeekday, candidate.Weekday()) {
+				return candidate
+			}
+		}
+		nextWeek := weekStart.AddDate(0, 0, 7*r.Interval)
+		for day := 0; day < 7; day++ {
+			candidate := nextWeek.AddDate(0, 0, day)
+			if containsWeekday(r.ByWeekday, candidate.Weekday()) {
+				return candidate
+			}
+		}
+		return nextWeek
+	case FrequencyMonthly:
+		day := start.Day()
+		for step := 1; ; step++ {
+			monthStart := time.Date(previous.Year(), previous.Month()+time.Month(step*r.Interval), 1,
+				previous.Hour(), previous.Minute(), previous.Second(), previous.Nanosecond(), previous.Location())
+			if day <= monthStart.AddDate(0, 1, -1).Day() {
+				return monthStart.AddDate(0, 0, day-1)
+			}
+		}
+	default:
+		return previous.AddDate(0, 0, r.Interval)
+	}
+}
+
+// Allows reports whether an occurrence may still be created once occurrences
+// instances of the series already exist.
+func (r *RecurrenceRule) Allows(occurrence time.Time, occurrences int) bool {
+	if r.Count > 0 && occurrences >= r.Count {
+		return false
+	}
+	if r.Until != nil && occurrence.After(*r.Until) {
+		return false
+	}
+	return true
+}
+
+func mondayOffset(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
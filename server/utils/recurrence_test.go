@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustParseRule(t *testing.T, value string) *RecurrenceRule {
+	t.Helper()
+	rule, err := ParseRecurrenceRule(value)
+	if err != nil {
+		t.Fatalf("ParseRecurrenceRule(%q): %v", value, err)
+	}
+	return rule
+}
+
+// occurrences returns the first n occurrences of the rule from start.
+func occurrences(rule *RecurrenceRule, start time.Time, n int) []time.Time {
+	series := []time.Time{start}
+	for len(series) < n {
+		series = append(series, rule.Next(series[len(series)-1], start))
+	}
+	return series
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(value string) time.Time {
+		at, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+	local := func(value string) time.Time {
+		at, err := time.ParseInLocation("2006-01-02 15:04", value, newYork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "daily",
+			rule:  "FREQ=DAILY",
+			start: utc("2026-01-30 09:00"),
+			want:  []time.Time{utc("2026-01-30 09:00"), utc("2026-01-31 09:00"), utc("2026-02-01 09:00")},
+		},
+		{
+			name:  "daily through a leap day",
+			rule:  "FREQ=DAILY",
+			start: utc("2028-02-28 09:00"),
+			want:  []time.Time{utc("2028-02-28 09:00"), utc("2028-02-29 09:00"), utc("2028-03-01 09:00")},
+		},
+		{
+			name:  "every third day",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: utc("2026-12-30 09:00"),
+			want:  []time.Time{utc("2026-12-30 09:00"), utc("2027-01-02 09:00"), utc("2027-01-05 09:00")},
+		},
+		{
+			name:  "monthly on the 31st skips shorter months",
+			rule:  "FREQ=MONTHLY",
+			start: utc("2026-01-31 09:00"),
+			want:  []time.Time{utc("2026-01-31 09:00"), utc("2026-03-31 09:00"), utc("2026-05-31 09:00"), utc("2026-07-31 09:00"), utc("2026-08-31 09:00")},
+		},
+		{
+			name:  "monthly on the 30th skips February",
+			rule:  "FREQ=MONTHLY",
+			start: utc("2026-01-30 09:00"),
+			want:  []time.Time{utc("2026-01-30 09:00"), utc("2026-03-30 09:00"), utc("2026-04-30 09:00")},
+		},
+		{
+			name:  "monthly on the 29th keeps February only in leap years",
+			rule:  "FREQ=MONTHLY",
+			start: utc("2027-01-29 09:00"),
+			want:  []time.Time{utc("2027-01-29 09:00"), utc("2027-03-29 09:00")},
+		},
+		{
+			name:  "monthly on the 29th in a leap year",
+			rule:  "FREQ=MONTHLY",
+			start: utc("2028-01-29 09:00"),
+			want:  []time.Time{utc("2028-01-29 09:00"), utc("2028-02-29 09:00"), utc("2028-03-29 09:00")},
+		},
+		{
+			name:  "yearly leap day waits for the next leap year",
+			rule:  "FREQ=MONTHLY;INTERVAL=12",
+			start: utc("2028-02-29 09:00"),
+			want:  []time.Time{utc("2028-02-29 09:00"), utc("2032-02-29 09:00"), utc("2036-02-29 09:00")},
+		},
+		{
+			name:  "every other month at month end",
+			rule:  "FREQ=MONTHLY;INTERVAL=2",
+			start: utc("2026-08-31 09:00"),
+			want:  []time.Time{utc("2026-08-31 09:00"), utc("2026-10-31 09:00"), utc("2026-12-31 09:00"), utc("2027-08-31 09:00")},
+		},
+		{
+			name:  "weekly",
+			rule:  "FREQ=WEEKLY",
+			start: utc("2026-12-28 09:00"),
+			want:  []time.Time{utc("2026-12-28 09:00"), utc("2027-01-04 09:00")},
+		},
+		{
+			name:  "every other week on Monday and Thursday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: utc("2026-10-19 09:00"),
+			want:  []time.Time{utc("2026-10-19 09:00"), utc("2026-10-22 09:00"), utc("2026-11-02 09:00"), utc("2026-11-05 09:00")},
+		},
+		{
+			name:  "BYDAY starting on Sunday",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,SU",
+			start: utc("2026-10-25 09:00"),
+			want:  []time.Time{utc("2026-10-25 09:00"), utc("2026-10-26 09:00"), utc("2026-11-01 09:00")},
+		},
+		{
+			name:  "daily keeps the local time across the spring DST change",
+			rule:  "FREQ=DAILY",
+			start: local("2026-03-07 09:00"),
+			want:  []time.Time{local("2026-03-07 09:00"), local("2026-03-08 09:00"), local("2026-03-09 09:00")},
+		},
+		{
+			name:  "weekly keeps the local time across the autumn DST change",
+			rule:  "FREQ=WEEKLY;BYDAY=SA,SU",
+			start: local("2026-10-31 09:00"),
+			want:  []time.Time{local("2026-10-31 09:00"), local("2026-11-01 09:00"), local("2026-11-07 09:00")},
+		},
+		{
+			name:  "monthly keeps the local time across DST",
+			rule:  "FREQ=MONTHLY",
+			start: local("2026-02-15 09:00"),
+			want:  []time.Time{local("2026-02-15 09:00"), local("2026-03-15 09:00"), local("2026-04-15 09:00")},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := occurrences(mustParseRule(t, test.rule), test.start, len(test.want))
+			for i := range test.want {
+				if !got[i].Equal(test.want[i]) {
+					t.Errorf("occurrence %d = %s, want %s", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceNextAcrossDSTKeepsWallClock(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, time.March, 7, 9, 0, 0, 0, newYork)
+	next := mustParseRule(t, "FREQ=DAILY").Next(start, start)
+	if next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf("next occurrence at %s, want 09:00 local", next)
+	}
+	if elapsed := next.Sub(start); elapsed != 23*time.Hour {
+		t.Errorf("%s elapsed over the short day, want 23h", elapsed)
+	}
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;byday=th,mo,mo", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"FREQ=MONTHLY;INTERVAL=1;COUNT=3", "FREQ=MONTHLY;COUNT=3"},
+		{"FREQ=DAILY;UNTIL=20261231", "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{"FREQ=DAILY;UNTIL=20261231T120000Z", "FREQ=DAILY;UNTIL=20261231T120000Z"},
+	}
+	for _, test := range tests {
+		if got := mustParseRule(t, test.input).String(); got != test.want {
+			t.Errorf("ParseRecurrenceRule(%q).String() = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseRecurrenceRuleErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "empty"},
+		{"INTERVAL=2", "needs FREQ"},
+		{"FREQ=YEARLY", "unsupported recurrence frequency"},
+		{"FREQ=DAILY;INTERVAL=0", "interval must be between 1 and 365"},
+		{"FREQ=DAILY;INTERVAL=366", "interval must be between 1 and 365"},
+		{"FREQ=DAILY;FREQ=WEEKLY", "duplicate"},
+		{"FREQ=DAILY;BYDAY=MO", "BYDAY is only supported with FREQ=WEEKLY"},
+		{"FREQ=WEEKLY;BYDAY=XX", "invalid recurrence weekday"},
+		{"FREQ=DAILY;COUNT=0", "count must be a positive number"},
+		{"FREQ=DAILY;UNTIL=2026-12-31", "UNTIL must look like"},
+		{"FREQ=DAILY;COUNT=2;UNTIL=20261231", "both UNTIL and COUNT"},
+		{"FREQ=DAILY;BYMONTH=1", "unsupported recurrence rule part"},
+		{"FREQ", "invalid recurrence rule part"},
+	}
+	for _, test := range tests {
+		_, err := ParseRecurrenceRule(test.input)
+		if err == nil {
+			t.Errorf("ParseRecurrenceRule(%q) succeeded, want an error containing %q", test.input, test.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("ParseRecurrenceRule(%q) error = %q, want it to contain %q", test.input, err, test.want)
+		}
+	}
+}
+
+func TestRecurrenceAllows(t *testing.T) {
+	at := time.Date(2026, time.December, 31, 23, 59, 59, 0, time.UTC)
+	tests := []struct {
+		rule        string
+		occurrence  time.Time
+		occurrences int
+		want        bool
+	}{
+		{"FREQ=DAILY", at.AddDate(10, 0, 0), 1000, true},
+		{"FREQ=DAILY;COUNT=3", at, 2, true},
+		{"FREQ=DAILY;COUNT=3", at, 3, false},
+		{"FREQ=DAILY;UNTIL=20261231", at, 1, true},
+		{"FREQ=DAILY;UNTIL=20261231", at.Add(time.Second), 1, false},
+	}
+	for _, test := range tests {
+		if got := mustParseRule(t, test.rule).Allows(test.occurrence, test.occurrences); got != test.want {
+			t.Errorf("%s: Allows(%s, %d) = %v, want %v", test.rule, test.occurrence, test.occurrences, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"context"
+	"server/gateway"
+	"server/policy"
+	"server/repositories"
+	"server/services"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const recurringTasksInterval = time.Minute
+
+// StartRecurringTaskScheduler creates the next instance of recurring tasks
+// until ctx is cancelled.
+func StartRecurringTaskScheduler(ctx context.Context, db *gorm.DB, wsService *gateway.WebSocketService, engine *policy.Engine, logger *zap.Logger) {
+	recurrenceRepo := repositories.NewTaskRecurrenceRepository(db)
+	taskRepo := repositories.NewTaskRepository(db)
+	taskBoardRepo := repositories.NewTaskBoardRepository(db)
+	watcherRepo := repositories.NewWatcherRepository(db)
+	notifier := services.NewNotifier(repositories.NewNotificationRepository(db), watcherRepo, repositories.NewUserRepository(db), taskBoardRepo, wsService, logger)
+	taskService := services.NewTaskService(taskRepo, taskBoardRepo, repositories.NewCustomFieldRepository(db), repositories.NewLabelRepository(db), repositories.NewAuditLogRepository(db), watcherRepo, notifier, repositories.NewTransactor(db), engine, wsService, logger)
+	recurrenceService := services.NewRecurrenceService(recurrenceRepo, taskRepo, taskService, logger)
+
+	go run(ctx, recurringTasksInterval, func(now time.Time) {
+		created, err := recurrenceService.CreateDueInstances(now)
+		if err != nil {
+			logger.Error("Recurring task scheduler failed", zap.Error(err))
+			return
+		}
+		if created > 0 {
+			logger.Info("Created recurring task instances", zap.Int("count", created))
+		}
+	})
+}
+
+// run calls job immediately and then on every tick until ctx is cancelled.
+func run(ctx context.Context, interval time.Duration, job func(now time.Time)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	job(time.Now().UTC())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			job(now.UTC())
+		}
+	}
+}
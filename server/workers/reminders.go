@@ -0,0 +1,33 @@
+package workers
+
+import (
+	"context"
+	"server/gateway"
+	"server/repositories"
+	"server/services"
+	"server/utils"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const remindersInterval = time.Minute
+
+// StartReminderWorker sends due-date and overdue reminders until ctx is cancelled.
+func StartReminderWorker(ctx context.Context, db *gorm.DB, wsService *gateway.WebSocketService, mailer utils.Mailer, logger *zap.Logger) {
+	reminderRepo := repositories.NewReminderRepository(db)
+	taskBoardRepo := repositories.NewTaskBoardRepository(db)
+	reminderService := services.NewReminderService(reminderRepo, taskBoardRepo, wsService, mailer, logger)
+
+	go run(ctx, remindersInterval, func(now time.Time) {
+		sent, err := reminderService.SendDueReminders(now)
+		if err != nil {
+			logger.Error("Reminder worker failed", zap.Error(err))
+			return
+		}
+		if sent > 0 {
+			logger.Info("Sent task reminders", zap.Int("count", sent))
+		}
+	})
+}
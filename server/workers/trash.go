@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"context"
+	"os"
+	"server/repositories"
+	"server/services"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	trashPurgeInterval    = time.Hour
+	defaultTrashRetention = 30
+)
+
+// StartTrashPurger permanently deletes trashed boards and tasks once they are
+// older than TRASH_RETENTION_DAYS (30 by default) until ctx is cancelled.
+func StartTrashPurger(ctx context.Context, db *gorm.DB, logger *zap.Logger) {
+	days := defaultTrashRetention
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			logger.Warn("Ignoring invalid TRASH_RETENTION_DAYS", zap.String("value", value))
+		} else {
+			days = parsed
+		}
+	}
+
+	trashService := services.NewTrashService(
+		repositories.NewTaskBoardRepository(db),
+		repositories.NewTaskRepository(db),
+		time.Duration(days)*24*time.Hour,
+		logger,
+	)
+
+	go run(ctx, trashPurgeInterval, func(now time.Time) {
+		if _, err := trashService.PurgeExpired(now); err != nil {
+			logger.Error("Trash purger failed", zap.Error(err))
+		}
+	})
+}